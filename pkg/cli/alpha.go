@@ -47,13 +47,15 @@ Alpha subcommands are for unstable features.
 	for i := range alphaCommands {
 		alpha.AddCommand(alphaCommands[i])
 	}
+	alpha.AddCommand(c.newConfigCmd())
+	alpha.AddCommand(c.newDescribeCmd())
+	alpha.AddCommand(c.newGenerateCmd())
+	alpha.AddCommand(c.newMigrateCmd())
 	return alpha
 }
 
 func (c *CLI) addAlphaCmd() {
-	if (len(alphaCommands) + len(c.extraAlphaCommands)) > 0 {
-		c.cmd.AddCommand(c.newAlphaCmd())
-	}
+	c.cmd.AddCommand(c.newAlphaCmd())
 }
 
 func (c *CLI) addExtraAlphaCommands() error {
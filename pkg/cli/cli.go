@@ -17,20 +17,25 @@ limitations under the License.
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
-	yamlstore "sigs.k8s.io/kubebuilder/v3/pkg/config/store/yaml"
+	"sigs.k8s.io/kubebuilder/v3/pkg/config/store"
 	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/stage"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 const (
@@ -39,6 +44,8 @@ const (
 
 	pluginsFlag        = "plugins"
 	projectVersionFlag = "project-version"
+	nonInteractiveFlag = "non-interactive"
+	yesFlag            = "yes"
 )
 
 // CLI is the command line utility that is used to scaffold kubebuilder project files.
@@ -63,9 +70,24 @@ type CLI struct { //nolint:maligned
 	extraAlphaCommands []*cobra.Command
 	// Whether to add a completion command to the CLI.
 	completionCommand bool
+	// Working directory the CLI is run from, defaults to the one of the process.
+	workingDir string
+	// Environment variables set while the CLI is run, on top of the ones of the process.
+	env map[string]string
+	// Standard streams the CLI is run with, default to the ones of the process.
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	// Runner of the commands run by plugins, defaults to running them as processes.
+	runner util.Runner
 
 	/* Internal fields */
 
+	// Options the CLI was created with, which create the CLIs that replay commands, e.g. for generate.
+	options []Option
+
+	// Command line arguments without the command name, defaults to os.Args[1:].
+	args []string
+
 	// Plugin keys to scaffold with.
 	pluginKeys []string
 	// Project version to scaffold.
@@ -79,6 +101,12 @@ type CLI struct { //nolint:maligned
 
 	// Underlying fs
 	fs machinery.Filesystem
+	// Path of the project configuration file, relative to the working directory.
+	projectFile string
+
+	// Whether to collect the result of the executed subcommand, and the collected result.
+	collectResult bool
+	result        *Result
 }
 
 // New creates a new CLI instance.
@@ -98,6 +126,8 @@ func New(options ...Option) (*CLI, error) {
 
 	// Build the cmd tree.
 	if err := c.buildCmd(); err != nil {
+		// Report the error for any command line, except for the subcommands that were still added
+		c.cmd.Args = cobra.ArbitraryArgs
 		c.cmd.RunE = errCmdFunc(err)
 		return c, nil
 	}
@@ -129,6 +159,10 @@ func newCLI(options ...Option) (*CLI, error) {
 		plugins:        make(map[string]plugin.Plugin),
 		defaultPlugins: make(map[config.Version][]string),
 		fs:             machinery.Filesystem{FS: afero.NewOsFs()},
+		stdin:          os.Stdin,
+		stdout:         os.Stdout,
+		stderr:         os.Stderr,
+		options:        append([]Option{}, options...),
 	}
 
 	// Apply provided options.
@@ -138,6 +172,27 @@ func newCLI(options ...Option) (*CLI, error) {
 		}
 	}
 
+	// The working directory is not changed for the process, so it has to exist in the filesystem.
+	if c.workingDir != "" {
+		if info, err := c.fs.FS.Stat(c.workingDir); err != nil {
+			return nil, fmt.Errorf("invalid working directory %q: %w", c.workingDir, err)
+		} else if !info.IsDir() {
+			return nil, fmt.Errorf("invalid working directory %q: not a directory", c.workingDir)
+		}
+	}
+
+	// Add the external plugins found in the plugins root directory, which is not part of the project.
+	// Their executables are run in the environment set by options to get their metadata.
+	ctx := util.WithEnvironment(context.Background(), c.environment())
+	externalPlugins, err := discoverExternalPlugins(ctx, afero.NewOsFs(),
+		append([]string{c.commandName}, c.arguments()...), c.getenv, c.stderr)
+	if err != nil {
+		return nil, err
+	}
+	if err := WithPlugins(externalPlugins...)(c); err != nil {
+		return nil, fmt.Errorf("unable to add external plugins: %w", err)
+	}
+
 	return c, nil
 }
 
@@ -162,23 +217,37 @@ func (c *CLI) buildCmd() error {
 			// stable version not registered, let's bail out
 			return err
 		}
+	case errors.As(err, &store.LoadError{}):
+		// Alpha subcommands, e.g. `alpha config validate`, are still available to diagnose the configuration file
+		c.addAlphaCmd()
+		return err
 	default:
 		return err
 	}
 
 	// Resolve plugins for project version and plugin keys.
 	if err := c.resolvePlugins(); err != nil {
+		// Alpha subcommands are still available to diagnose a configuration file with unknown plugins
+		c.addAlphaCmd()
 		return err
 	}
 
 	// Add the subcommands
 	c.addSubcommands()
 
+	// Add the subcommands provided by the resolved plugins
+	c.addCustomSubcommands()
+
 	return nil
 }
 
 // getInfo obtains the plugin keys and project version resolving conflicts between the project config file and flags.
 func (c *CLI) getInfo() error {
+	// Find the project configuration file
+	if err := c.resolveProjectFile(); err != nil {
+		return err
+	}
+
 	// Get plugin keys and project version from project configuration file
 	// We discard the error if file doesn't exist because not being able to read a project configuration
 	// file is not fatal for some commands. The ones that require it need to check its existence later.
@@ -205,8 +274,7 @@ func (c *CLI) getInfo() error {
 
 // getInfoFromConfigFile obtains the project version and plugin keys from the project config file.
 func (c *CLI) getInfoFromConfigFile() error {
-	// Read the project configuration file
-	cfg := yamlstore.New(c.fs)
+	cfg := newStore(c.filesystem(), c.projectFileName())
 	if err := cfg.Load(); err != nil {
 		return err
 	}
@@ -251,7 +319,7 @@ func (c *CLI) getInfoFromFlags(hasConfigFile bool) error {
 	fs.ParseErrorsWhitelist = pflag.ParseErrorsWhitelist{UnknownFlags: true}
 
 	// Parse the arguments
-	if err := fs.Parse(os.Args[1:]); err != nil {
+	if err := fs.Parse(c.arguments()); err != nil {
 		return err
 	}
 
@@ -322,9 +390,26 @@ const unstablePluginMsg = " (plugin version is unstable, there may be an upgrade
 
 // resolvePlugins selects from the available plugins those that match the project version and plugin keys provided.
 func (c *CLI) resolvePlugins() error {
-	knownProjectVersion := c.projectVersion.Validate() == nil
+	resolvedPlugins, projectVersion, err := c.resolvePluginKeys(c.pluginKeys, c.projectVersion)
+	if err != nil {
+		return err
+	}
 
-	for _, pluginKey := range c.pluginKeys {
+	c.resolvedPlugins = resolvedPlugins
+	c.projectVersion = projectVersion
+	return nil
+}
+
+// resolvePluginKeys selects from the available plugins those that match the provided plugin keys and project
+// version, if valid. It returns them along with the provided project version, or the one they support if not valid.
+func (c CLI) resolvePluginKeys(
+	pluginKeys []string,
+	projectVersion config.Version,
+) ([]plugin.Plugin, config.Version, error) {
+	knownProjectVersion := projectVersion.Validate() == nil
+
+	resolvedPlugins := make([]plugin.Plugin, 0, len(pluginKeys))
+	for _, pluginKey := range pluginKeys {
 		var extraErrMsg string
 
 		plugins := make([]plugin.Plugin, 0, len(c.plugins))
@@ -334,63 +419,78 @@ func (c *CLI) resolvePlugins() error {
 		// We can omit the error because plugin keys have already been validated
 		plugins, _ = plugin.FilterPluginsByKey(plugins, pluginKey)
 		if knownProjectVersion {
-			plugins = plugin.FilterPluginsByProjectVersion(plugins, c.projectVersion)
-			extraErrMsg += fmt.Sprintf(" for project version %q", c.projectVersion)
+			plugins = plugin.FilterPluginsByProjectVersion(plugins, projectVersion)
+			extraErrMsg += fmt.Sprintf(" for project version %q", projectVersion)
 		}
 
-		// Plugins are often released as "unstable" (alpha/beta) versions, then upgraded to "stable".
-		// This upgrade effectively removes a plugin, which is fine because unstable plugins are
-		// under no support contract. However users should be notified _why_ their plugin cannot be found.
 		if _, version := plugin.SplitKey(pluginKey); version != "" {
-			var ver plugin.Version
-			if err := ver.Parse(version); err != nil {
-				return fmt.Errorf("error parsing input plugin version from key %q: %v", pluginKey, err)
+			var versionRange plugin.VersionRange
+			if err := versionRange.Parse(version); err != nil {
+				return nil, projectVersion,
+					fmt.Errorf("error parsing input plugin version from key %q: %v", pluginKey, err)
 			}
-			if !ver.IsStable() {
+
+			// Plugins are often released as "unstable" (alpha/beta) versions, then upgraded to "stable".
+			// This upgrade effectively removes a plugin, which is fine because unstable plugins are
+			// under no support contract. However users should be notified _why_ their plugin cannot be found.
+			if versionRange.Exact && !versionRange.Min.IsStable() {
 				extraErrMsg += unstablePluginMsg
 			}
+
+			// Version ranges may match several versions of a plugin, resolve to the preferred one.
+			// Its exact key will be the one stored in the plugin chain of the project configuration.
+			if !versionRange.Exact {
+				plugins = plugin.FilterPreferredVersions(plugins)
+			}
 		}
 
 		// Only 1 plugin can match
 		switch len(plugins) {
 		case 1:
-			c.resolvedPlugins = append(c.resolvedPlugins, plugins[0])
+			resolvedPlugins = append(resolvedPlugins, plugins[0])
 		case 0:
-			return fmt.Errorf("no plugin could be resolved with key %q%s", pluginKey, extraErrMsg)
+			return nil, projectVersion, fmt.Errorf("no plugin could be resolved with key %q%s", pluginKey, extraErrMsg)
 		default:
-			return fmt.Errorf("ambiguous plugin %q%s", pluginKey, extraErrMsg)
+			return nil, projectVersion, fmt.Errorf("ambiguous plugin %q%s", pluginKey, extraErrMsg)
 		}
 	}
 
+	// Validate the relationships between the resolved plugins and sort them accordingly
+	resolvedPlugins, err := plugin.ResolveDependencies(resolvedPlugins)
+	if err != nil {
+		return nil, projectVersion, err
+	}
+
 	// Now we can try to resolve the project version if not known by this point
-	if !knownProjectVersion && len(c.resolvedPlugins) > 0 {
+	if !knownProjectVersion && len(resolvedPlugins) > 0 {
 		// Extract the common supported project versions
-		supportedProjectVersions := plugin.CommonSupportedProjectVersions(c.resolvedPlugins...)
+		supportedProjectVersions := plugin.CommonSupportedProjectVersions(resolvedPlugins...)
 
 		// If there is only one common supported project version, resolve to it
 	ProjectNumberVersionSwitch:
 		switch len(supportedProjectVersions) {
 		case 1:
-			c.projectVersion = supportedProjectVersions[0]
+			projectVersion = supportedProjectVersions[0]
 		case 0:
-			return fmt.Errorf("no project version supported by all the resolved plugins")
+			return nil, projectVersion, fmt.Errorf("no project version supported by all the resolved plugins")
 		default:
 			supportedProjectVersionStrings := make([]string, 0, len(supportedProjectVersions))
 			for _, supportedProjectVersion := range supportedProjectVersions {
 				// In case one of the multiple supported versions is the default one, choose that and exit the switch
 				if supportedProjectVersion.Compare(c.defaultProjectVersion) == 0 {
-					c.projectVersion = c.defaultProjectVersion
+					projectVersion = c.defaultProjectVersion
 					break ProjectNumberVersionSwitch
 				}
 				supportedProjectVersionStrings = append(supportedProjectVersionStrings,
 					fmt.Sprintf("%q", supportedProjectVersion))
 			}
-			return fmt.Errorf("ambiguous project version, resolved plugins support the following project versions: %s",
+			return nil, projectVersion, fmt.Errorf(
+				"ambiguous project version, resolved plugins support the following project versions: %s",
 				strings.Join(supportedProjectVersionStrings, ", "))
 		}
 	}
 
-	return nil
+	return resolvedPlugins, projectVersion, nil
 }
 
 // addSubcommands returns a root command with a subcommand tree reflecting the
@@ -414,6 +514,15 @@ func (c *CLI) addSubcommands() {
 		c.cmd.AddCommand(createCmd)
 	}
 
+	// kubebuilder delete
+	deleteCmd := c.newDeleteCmd()
+	// kubebuilder delete api
+	deleteCmd.AddCommand(c.newDeleteAPICmd())
+	deleteCmd.AddCommand(c.newDeleteWebhookCmd())
+	if deleteCmd.HasSubCommands() {
+		c.cmd.AddCommand(deleteCmd)
+	}
+
 	// kubebuilder edit
 	c.cmd.AddCommand(c.newEditCmd())
 
@@ -444,7 +553,7 @@ func (c *CLI) addExtraCommands() error {
 func (c CLI) printDeprecationWarnings() {
 	for _, p := range c.resolvedPlugins {
 		if d, isDeprecated := p.(plugin.Deprecated); isDeprecated {
-			fmt.Printf(noticeColor, fmt.Sprintf(deprecationFmt, d.DeprecationWarning()))
+			fmt.Fprintf(c.stdout, noticeColor, fmt.Sprintf(deprecationFmt, d.DeprecationWarning()))
 		}
 	}
 }
@@ -459,6 +568,31 @@ func (c CLI) metadata() plugin.CLIMetadata {
 // Run executes the CLI utility.
 //
 // If an error is found, command help and examples will be printed.
+// The execution is cancelled on interrupt and termination signals, so that plugins can stop gracefully
+// and the changes made to the project are rolled back.
 func (c CLI) Run() error {
-	return c.cmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return c.run(ctx)
+}
+
+// run executes the CLI utility with the provided context. Plugins are executed in the working directory,
+// environment and standard streams set by options.
+func (c CLI) run(ctx context.Context) error {
+	c.cmd.SetIn(c.stdin)
+	c.cmd.SetOut(c.stdout)
+	c.cmd.SetErr(c.stderr)
+	if c.args != nil {
+		c.cmd.SetArgs(c.args)
+	}
+	return c.cmd.ExecuteContext(ctx)
+}
+
+// arguments returns the command line arguments, without the command name.
+func (c CLI) arguments() []string {
+	if c.args != nil {
+		return c.args
+	}
+	return os.Args[1:]
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package cli
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -31,6 +32,7 @@ import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	cfgv2 "sigs.k8s.io/kubebuilder/v3/pkg/config/v2"
 	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	cfgv4 "sigs.k8s.io/kubebuilder/v3/pkg/config/v4"
 	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/stage"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
@@ -137,6 +139,20 @@ plugins:
 				Expect(err).To(HaveOccurred())
 			})
 		})
+
+		When("the plugins of the project configuration cannot be resolved", func() {
+			It("should still provide the alpha subcommands", func() {
+				Expect(afero.WriteFile(c.fs.FS, "PROJECT", []byte(`layout:
+- unknown.kubebuilder.io/v1
+version: "3"
+`), 0600)).To(Succeed())
+
+				Expect(c.buildCmd()).NotTo(Succeed())
+				validate, _, err := c.cmd.Find([]string{alphaCommand, "config", "validate"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(validate.Name()).To(Equal("validate"))
+			})
+		})
 	})
 
 	// TODO: test CLI.getInfoFromConfigFile using a mock filesystem
@@ -570,6 +586,213 @@ plugins:
 			})
 		})
 
+		When("providing plugins with custom subcommands", func() {
+			var (
+				args []string
+
+				dashboard, docs *mockSubcommand
+			)
+
+			BeforeEach(func() {
+				args = os.Args
+
+				dashboard, docs = &mockSubcommand{}, &mockSubcommand{}
+			})
+			AfterEach(func() { os.Args = args })
+
+			newCustomPlugin := func(extra ...plugin.CustomSubcommand) plugin.Plugin {
+				subcommands := []plugin.CustomSubcommand{
+					{Path: "create dashboard", Short: "Scaffold a dashboard", Subcommand: dashboard},
+					{Path: " generate  docs ", Short: "Generate the docs", Subcommand: docs},
+				}
+				return newMockCustomPlugin("custom.example.com", "v1", append(subcommands, extra...), projectVersion)
+			}
+
+			findCommand := func(path ...string) *cobra.Command {
+				cmd, _, err := c.cmd.Find(path)
+				if err != nil || cmd.Name() != path[len(path)-1] {
+					return nil
+				}
+				return cmd
+			}
+
+			It("should add them to existing and new parent commands", func() {
+				p := newCustomPlugin()
+				c, err = New(
+					WithPlugins(&goPluginV3.Plugin{}, p),
+					WithDefaultPlugins(projectVersion, &goPluginV3.Plugin{}, p),
+					WithDefaultProjectVersion(projectVersion),
+				)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(findCommand("create", "api")).NotTo(BeNil())
+				Expect(findCommand("create", "dashboard")).NotTo(BeNil())
+				Expect(findCommand("create", "dashboard").Short).To(Equal("Scaffold a dashboard"))
+				Expect(findCommand("generate")).NotTo(BeNil())
+				Expect(findCommand("generate", "docs")).NotTo(BeNil())
+				Expect(findCommand("generate", "docs").Flags().Lookup("flag")).NotTo(BeNil())
+			})
+
+			It("should run their hooks", func() {
+				p := newCustomPlugin()
+				c, err = newCLI(
+					WithPlugins(p),
+					WithDefaultProjectVersion(projectVersion),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				c.fs = machinery.Filesystem{FS: afero.NewMemMapFs()}
+				Expect(afero.WriteFile(c.fs.FS, "PROJECT", []byte(`domain: example.com
+layout:
+- custom.example.com/v1
+projectName: test
+repo: example.com/test
+version: "3"
+`), 0600)).To(Succeed())
+				Expect(c.buildCmd()).To(Succeed())
+
+				c.cmd.SetArgs([]string{"generate", "docs", "--flag", "value"})
+				Expect(c.Run()).To(Succeed())
+
+				Expect(docs.flag).To(Equal("value"))
+				Expect(docs.config).NotTo(BeNil())
+				Expect(docs.config.GetDomain()).To(Equal("example.com"))
+				Expect(docs.hooks).To(Equal([]string{"InjectConfig", "PreScaffold", "Scaffold", "PostScaffold"}))
+				Expect(dashboard.hooks).To(BeEmpty())
+			})
+
+			newCustomCLI := func(subcommands ...plugin.CustomSubcommand) *bytes.Buffer {
+				stderr := &bytes.Buffer{}
+				p := newCustomPlugin(subcommands...)
+				c, err = New(
+					WithPlugins(&goPluginV3.Plugin{}, p),
+					WithDefaultPlugins(projectVersion, &goPluginV3.Plugin{}, p),
+					WithDefaultProjectVersion(projectVersion),
+					WithStderr(stderr),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(findCommand("create", "dashboard")).NotTo(BeNil())
+				return stderr
+			}
+
+			DescribeTable("should report invalid ones when they are run",
+				func(subcommand plugin.CustomSubcommand, expected string) {
+					newCustomCLI(subcommand)
+
+					cmd := findCommand(strings.Fields(subcommand.Path)...)
+					Expect(cmd).NotTo(BeNil())
+					Expect(cmd.RunE(cmd, nil)).To(MatchError(ContainSubstring(expected)))
+					Expect(cmd.Long).To(ContainSubstring(expected))
+				},
+				Entry("for duplicated ones",
+					plugin.CustomSubcommand{Path: "create dashboard", Subcommand: &mockSubcommand{}}, "more than once"),
+				Entry("for ones without subcommand", plugin.CustomSubcommand{Path: "generate manual"},
+					"invalid custom subcommand"),
+			)
+
+			DescribeTable("should skip them with a warning",
+				func(path, expected string) {
+					stderr := newCustomCLI(plugin.CustomSubcommand{Path: path, Subcommand: &mockSubcommand{}})
+
+					Expect(stderr.String()).To(ContainSubstring(expected))
+					Expect(findCommand("create", "api")).NotTo(BeNil())
+				},
+				Entry("for conflicting ones", "create api", "command already exists"),
+				Entry("for an empty path", " ", "empty path"),
+			)
+		})
+
+		When("migrating a project", func() {
+			var (
+				args []string
+
+				migrate *mockSubcommand
+			)
+
+			BeforeEach(func() {
+				args = os.Args
+
+				migrate = &mockSubcommand{}
+			})
+			AfterEach(func() { os.Args = args })
+
+			newMigrateCLI := func(previousKey string, flags ...string) {
+				oldPlugin := newMockPlugin("old.example.com", "v1", projectVersion, cfgv4.Version)
+				newPlugin := newMockMigratorPlugin("new.example.com", "v2", previousKey, migrate,
+					projectVersion, cfgv4.Version)
+				c, err = newCLI(
+					WithPlugins(oldPlugin, newPlugin),
+					WithDefaultPlugins(projectVersion, newPlugin),
+					WithDefaultProjectVersion(projectVersion),
+				)
+				Expect(err).NotTo(HaveOccurred())
+				c.fs = machinery.Filesystem{FS: afero.NewMemMapFs()}
+				Expect(afero.WriteFile(c.fs.FS, "PROJECT", []byte(`domain: example.com
+layout:
+- old.example.com/v1
+projectName: test
+repo: example.com/test
+version: "3"
+`), 0600)).To(Succeed())
+				os.Args = append([]string{"kubebuilder", "alpha", "migrate"}, flags...)
+				Expect(c.buildCmd()).To(Succeed())
+				c.cmd.SetArgs(os.Args[1:])
+			}
+
+			It("should run the hooks of the plugins that can migrate the project and update its layout", func() {
+				newMigrateCLI("old.example.com/v1")
+				Expect(c.Run()).To(Succeed())
+
+				Expect(migrate.config).NotTo(BeNil())
+				Expect(migrate.config.GetDomain()).To(Equal("example.com"))
+				Expect(migrate.config.GetPluginChain()).To(Equal([]string{"new.example.com/v2"}))
+				Expect(migrate.hooks).To(Equal([]string{"InjectConfig", "PreScaffold", "Scaffold", "PostScaffold"}))
+
+				content, err := afero.ReadFile(c.fs.FS, "PROJECT")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("- new.example.com/v2"))
+				Expect(string(content)).NotTo(ContainSubstring("old.example.com/v1"))
+				Expect(string(content)).To(ContainSubstring(`version: "3"`))
+			})
+
+			It("should convert the project configuration to the provided project version", func() {
+				newMigrateCLI("old.example.com/v1", "--"+projectVersionFlag, cfgv4.Version.String())
+				Expect(c.Run()).To(Succeed())
+
+				Expect(migrate.config).NotTo(BeNil())
+				Expect(migrate.config.GetVersion().Compare(cfgv4.Version)).To(Equal(0))
+				Expect(migrate.config.GetDomain()).To(Equal("example.com"))
+				Expect(migrate.config.GetPluginChain()).To(Equal([]string{"new.example.com/v2"}))
+
+				content, err := afero.ReadFile(c.fs.FS, "PROJECT")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring(`version: "4"`))
+			})
+
+			It("should only convert the project configuration if the plugins do not change", func() {
+				newMigrateCLI("other.example.com/v1",
+					"--"+migrateToFlag, "old.example.com/v1", "--"+projectVersionFlag, cfgv4.Version.String())
+				Expect(c.Run()).To(Succeed())
+				Expect(migrate.hooks).To(BeEmpty())
+
+				content, err := afero.ReadFile(c.fs.FS, "PROJECT")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring("- old.example.com/v1"))
+				Expect(string(content)).To(ContainSubstring(`version: "4"`))
+			})
+
+			It("should fail for an invalid project version", func() {
+				newMigrateCLI("old.example.com/v1", "--"+projectVersionFlag, "a")
+				Expect(c.Run()).NotTo(Succeed())
+				Expect(migrate.hooks).To(BeEmpty())
+			})
+
+			It("should fail if no plugin can migrate the project", func() {
+				newMigrateCLI("other.example.com/v1")
+				Expect(c.Run()).NotTo(Succeed())
+				Expect(migrate.hooks).To(BeEmpty())
+			})
+		})
+
 		When("providing deprecated plugins", func() {
 			It("should succeed and print the deprecation notice", func() {
 				const (
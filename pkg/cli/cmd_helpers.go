@@ -17,18 +17,22 @@ limitations under the License.
 package cli
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	"sigs.k8s.io/kubebuilder/v3/pkg/config/store"
-	yamlstore "sigs.k8s.io/kubebuilder/v3/pkg/config/store/yaml"
 	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/external"
 )
 
 // noResolvedPluginError is returned by subcommands that require a plugin when none was resolved.
@@ -72,20 +76,30 @@ type keySubcommandTuple struct {
 	skip bool
 }
 
+// unbundledPlugins returns the resolved plugins replacing bundles by the plugins they wrap.
+func (c *CLI) unbundledPlugins() []plugin.Plugin {
+	return unbundlePlugins(c.resolvedPlugins)
+}
+
+// unbundlePlugins returns the provided plugins replacing bundles by the plugins they wrap.
+func unbundlePlugins(plugins []plugin.Plugin) []plugin.Plugin {
+	unbundled := make([]plugin.Plugin, 0, len(plugins))
+	for _, p := range plugins {
+		if bundle, isBundle := p.(plugin.Bundle); isBundle {
+			unbundled = append(unbundled, bundle.Plugins()...)
+		} else {
+			unbundled = append(unbundled, p)
+		}
+	}
+	return unbundled
+}
+
 // filterSubcommands returns a list of plugin keys and subcommands from a filtered list of resolved plugins.
 func (c *CLI) filterSubcommands(
 	filter func(plugin.Plugin) bool,
 	extract func(plugin.Plugin) plugin.Subcommand,
 ) []keySubcommandTuple {
-	// Unbundle plugins
-	plugins := make([]plugin.Plugin, 0, len(c.resolvedPlugins))
-	for _, p := range c.resolvedPlugins {
-		if bundle, isBundle := p.(plugin.Bundle); isBundle {
-			plugins = append(plugins, bundle.Plugins()...)
-		} else {
-			plugins = append(plugins, p)
-		}
-	}
+	plugins := c.unbundledPlugins()
 
 	tuples := make([]keySubcommandTuple, 0, len(plugins))
 	for _, p := range plugins {
@@ -99,6 +113,22 @@ func (c *CLI) filterSubcommands(
 	return tuples
 }
 
+// hasExternalPlugins returns true if any of the resolved plugins is an external plugin.
+func (c *CLI) hasExternalPlugins() bool {
+	for _, p := range c.resolvedPlugins {
+		plugins := []plugin.Plugin{p}
+		if bundle, isBundle := p.(plugin.Bundle); isBundle {
+			plugins = bundle.Plugins()
+		}
+		for _, bp := range plugins {
+			if _, isExternal := bp.(external.Plugin); isExternal {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // applySubcommandHooks runs the initialization hooks and configures the commands pre-run,
 // run, and post-run hooks with the appropriate execution hooks.
 func (c *CLI) applySubcommandHooks(
@@ -107,29 +137,54 @@ func (c *CLI) applySubcommandHooks(
 	errorMessage string,
 	createConfig bool,
 ) {
+	// We extract the plugin keys again instead of using the ones obtained when filtering subcommands
+	// as these plugins are unbundled but we want to keep bundle names in the plugin chain.
+	resolvedPlugins := make([]string, 0, len(c.resolvedPlugins))
+	for _, p := range c.resolvedPlugins {
+		resolvedPlugins = append(resolvedPlugins, plugin.KeyFor(p))
+	}
+
 	// In case we create a new project configuration we need to compute the plugin chain.
-	pluginChain := make([]string, 0, len(c.resolvedPlugins))
+	var pluginChain []string
 	if createConfig {
-		// We extract the plugin keys again instead of using the ones obtained when filtering subcommands
-		// as these plugins are unbundled but we want to keep bundle names in the plugin chain.
-		for _, p := range c.resolvedPlugins {
-			pluginChain = append(pluginChain, plugin.KeyFor(p))
-		}
+		pluginChain = resolvedPlugins
+	}
+
+	c.applyHooks(cmd, executionHooksFactory{
+		subcommands:     subcommands,
+		errorMessage:    errorMessage,
+		projectVersion:  c.projectVersion,
+		pluginChain:     pluginChain,
+		resolvedPlugins: resolvedPlugins,
+	}, createConfig)
+}
+
+// applyHooks runs the initialization hooks and configures the commands pre-run, run, and post-run hooks
+// with the execution hooks of the provided factory.
+func (c *CLI) applyHooks(cmd *cobra.Command, factory executionHooksFactory, createConfig bool) {
+	cmd.Flags().String(outputFlag, outputText,
+		fmt.Sprintf("output format, one of %q or %q; %q prints a machine-readable report of the execution "+
+			"and writes any other message to the standard error", outputText, outputJSON, outputJSON))
+
+	// External plugins receive the raw command line arguments, so flags that are only
+	// known by them must not result in parsing errors.
+	if c.hasExternalPlugins() {
+		cmd.FParseErrWhitelist.UnknownFlags = true
 	}
 
-	options := initializationHooks(cmd, subcommands, c.metadata())
+	options := initializationHooks(cmd, factory.subcommands, c.metadata())
 
-	factory := executionHooksFactory{
-		fs:             c.fs,
-		store:          yamlstore.New(c.fs),
-		subcommands:    subcommands,
-		errorMessage:   errorMessage,
-		projectVersion: c.projectVersion,
-		pluginChain:    pluginChain,
+	factory.env = c.environment()
+	factory.fs = c.filesystem()
+	factory.fs.Environment = factory.env
+	factory.projectFile = c.projectFileName()
+	factory.store = newStore(factory.fs, factory.projectFile)
+	if c.collectResult {
+		factory.onResult = func(r *Result) { c.result = r }
 	}
-	cmd.PreRunE = factory.preRunEFunc(options, createConfig)
-	cmd.RunE = factory.runEFunc()
-	cmd.PostRunE = factory.postRunEFunc()
+	cmd.PreRunE = factory.handleErrors(factory.preRunEFunc(options, createConfig))
+	cmd.RunE = factory.handleErrors(factory.runEFunc())
+	cmd.PostRunE = factory.handleErrors(factory.postRunEFunc(createConfig))
 }
 
 // initializationHooks executes update metadata and bind flags plugin hooks.
@@ -164,11 +219,8 @@ func initializationHooks(
 	}
 
 	// Bind flags hook.
-	for _, tuple := range subcommands {
-		if subcommand, hasFlags := tuple.subcommand.(plugin.HasFlags); hasFlags {
-			subcommand.BindFlags(cmd.Flags())
-		}
-	}
+	bindPluginFlags(cmd, subcommands)
+	groupFlagUsages(cmd)
 
 	return options
 }
@@ -176,25 +228,209 @@ func initializationHooks(
 type executionHooksFactory struct {
 	// fs is the filesystem abstraction to scaffold files to.
 	fs machinery.Filesystem
+	// env is the environment the plugins are executed in. It is handed to them through fs, the context of
+	// the hooks and the RequiresEnvironment hook, and it is not shared with other executions.
+	env *util.Environment
 	// store is the backend used to load/save the project configuration.
 	store store.Store
+	// projectFile is the path of the project configuration file loaded and saved by store.
+	projectFile string
 	// subcommands are the tuples representing the set of subcommands provided by the resolved plugins.
 	subcommands []keySubcommandTuple
 	// errorMessage is prepended to returned errors.
 	errorMessage string
 	// projectVersion is the project version that will be used to create new project configurations.
-	// It is only used for initialization.
+	// It is only used for initialization and migrations.
 	projectVersion config.Version
 	// pluginChain is the plugin chain configured for this project.
 	pluginChain []string
+	// migrateFrom are the keys of the plugins the project is migrated from, if any. In that case, the loaded
+	// project configuration is converted to projectVersion, keeping the configuration of these plugins.
+	migrateFrom []string
+	// resolvedPlugins are the keys of the resolved plugins.
+	resolvedPlugins []string
+	// dryRun is the in-memory overlay where changes are stored when running in dry-run mode.
+	// It is nil if dry-run mode is disabled.
+	dryRun *dryRunOverlay
+	// transaction journals the changes made by the hooks so that they are rolled back if any of them fails.
+	// It is nil in dry-run mode, as the changes are not applied to the project.
+	transaction *transaction
+	// report collects the result of the execution when the output format is JSON or a result is requested.
+	// It is nil otherwise.
+	report *Result
+	// stdout is the standard output of env, where the report is written.
+	// It is nil if the report is not written.
+	stdout io.Writer
+	// onResult receives the collected result once the execution finishes, if non-nil.
+	onResult func(*Result)
 }
 
-func (factory *executionHooksFactory) forEach(cb func(subcommand plugin.Subcommand) error, errorMessage string) error {
+// enableDryRun makes every hook scaffold to an in-memory overlay of the filesystem
+// and the commands run by plugins be only printed.
+func (factory *executionHooksFactory) enableDryRun() {
+	factory.dryRun = newDryRunOverlay(factory.fs)
+	factory.fs = factory.dryRun.filesystem()
+	factory.fs.Environment = factory.env
+	factory.store = newStore(factory.fs, factory.projectFile)
+	factory.env.DryRun = true
+}
+
+// enableTransaction makes every change to the project be journaled so that it can be rolled back.
+func (factory *executionHooksFactory) enableTransaction() {
+	factory.transaction = newTransaction(factory.fs)
+	factory.fs = factory.transaction.filesystem(factory.fs)
+	factory.store = newStore(factory.fs, factory.projectFile)
+}
+
+// rollback restores the project to the state it had before the hooks were executed, returning the provided error.
+func (factory *executionHooksFactory) rollback(err error) error {
+	if factory.transaction == nil || factory.transaction.empty() {
+		return err
+	}
+
+	if rollbackErr := factory.transaction.rollback(); rollbackErr != nil {
+		return fmt.Errorf("%w (unable to restore the project to its original state: %v)", err, rollbackErr)
+	}
+	factory.transaction = nil
+	fmt.Fprintln(factory.env.OutOrStdout(), "restored the project to its original state")
+	if factory.report != nil {
+		factory.report.RolledBack = true
+	}
+	return err
+}
+
+// collectReport collects the result of the execution, including the changes made to files without templates.
+func (factory *executionHooksFactory) collectReport() {
+	factory.report = newReport(factory.resolvedPlugins, factory.dryRun != nil)
+	factory.fs = machinery.Filesystem{
+		FS:          reportingFs{Fs: factory.fs.FS, report: factory.report},
+		Reporter:    factory.report.addFile,
+		Environment: factory.fs.Environment,
+	}
+	factory.store = newStore(factory.fs, factory.projectFile)
+	factory.env.CommandObserver = factory.report.addCommand
+}
+
+// enableReport collects the result of the execution in order to print it as a JSON report.
+// Any other message is written to the standard error so that the standard output only contains the report.
+func (factory *executionHooksFactory) enableReport() {
+	factory.collectReport()
+
+	factory.stdout = factory.env.OutOrStdout()
+	factory.env.Stdout = factory.env.ErrOrStderr()
+}
+
+// writeReport hands the report, including the provided error, to onResult and, if enabled,
+// restores the standard output of the environment and writes the report to it.
+func (factory *executionHooksFactory) writeReport(err error) error {
+	if factory.report == nil {
+		return err
+	}
+
+	factory.env.CommandObserver = nil
+
+	r := factory.report
+	factory.report = nil
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if factory.onResult != nil {
+		factory.onResult(r)
+	}
+	if factory.stdout == nil {
+		return err
+	}
+
+	factory.env.Stdout = factory.stdout
+	factory.stdout = nil
+	writeErr := r.write(factory.env.Stdout)
+	if err != nil {
+		return err
+	}
+	return writeErr
+}
+
+// convertConfig replaces the loaded project configuration by a new one of version projectVersion with its fields.
+// The new configuration is written and loaded again, as the store only allows to save new configurations
+// if they did not exist.
+func (factory *executionHooksFactory) convertConfig() error {
+	converted, err := config.ConvertTo(factory.store.Config(), factory.projectVersion, factory.migrateFrom...)
+	if err != nil {
+		return err
+	}
+
+	content, err := marshalConfig(converted, factory.projectFile)
+	if err != nil {
+		return err
+	}
+	if err := afero.WriteFile(factory.fs.FS, factory.projectFile, content, 0600); err != nil {
+		return err
+	}
+	return factory.store.Load()
+}
+
+// printFilesToReview prints the files that the subcommands report as needing to be reviewed manually.
+func (factory *executionHooksFactory) printFilesToReview() {
+	var files []plugin.FileToReview
+	for _, tuple := range factory.subcommands {
+		if subcommand, requiresReview := tuple.subcommand.(plugin.RequiresReview); requiresReview && !tuple.skip {
+			files = append(files, subcommand.FilesToReview()...)
+		}
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	fmt.Fprintln(factory.env.OutOrStdout(), "The following files need to be reviewed manually:")
+	for _, file := range files {
+		fmt.Fprintf(factory.env.OutOrStdout(), "  - %s: %s\n", file.Path, file.Reason)
+		if factory.report != nil {
+			factory.report.addFileToReview(file)
+		}
+	}
+}
+
+// commandContext returns the context of the command, which is only set if it is executed with a context.
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// hooksContext returns the context of the command carrying the environment the plugins are executed in.
+func (factory *executionHooksFactory) hooksContext(cmd *cobra.Command) context.Context {
+	return util.WithEnvironment(commandContext(cmd), factory.env)
+}
+
+// handleErrors wraps a cobra RunE function so that the changes are rolled back and the report is written
+// if it fails.
+func (factory *executionHooksFactory) handleErrors(
+	f func(*cobra.Command, []string) error,
+) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := f(cmd, args); err != nil {
+			return factory.writeReport(factory.rollback(err))
+		}
+		return nil
+	}
+}
+
+func (factory *executionHooksFactory) forEach(
+	ctx context.Context,
+	cb func(subcommand plugin.Subcommand) error,
+	errorMessage string,
+) error {
 	for i, tuple := range factory.subcommands {
 		if tuple.skip {
 			continue
 		}
 
+		// Do not run any other hook once the execution has been cancelled
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%s: %w", factory.errorMessage, err)
+		}
+
 		err := cb(tuple.subcommand)
 
 		var exitError plugin.ExitError
@@ -204,7 +440,10 @@ func (factory *executionHooksFactory) forEach(cb func(subcommand plugin.Subcomma
 		case errors.As(err, &exitError):
 			// Exit errors imply that no further hooks of this subcommand should be called, so we flag it to be skipped
 			factory.subcommands[i].skip = true
-			fmt.Printf("skipping remaining hooks of %q: %s\n", tuple.key, exitError.Reason)
+			fmt.Fprintf(factory.env.OutOrStdout(), "skipping remaining hooks of %q: %s\n", tuple.key, exitError.Reason)
+			if factory.report != nil {
+				factory.report.addSkip(tuple.key, exitError.Reason)
+			}
 		default:
 			// Any other error, wrap it
 			return fmt.Errorf("%s: %s %q: %w", factory.errorMessage, errorMessage, tuple.key, err)
@@ -220,7 +459,29 @@ func (factory *executionHooksFactory) preRunEFunc(
 	options *resourceOptions,
 	createConfig bool,
 ) func(*cobra.Command, []string) error {
-	return func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		ctx := factory.hooksContext(cmd)
+
+		if dryRun, _ := cmd.Flags().GetBool(dryRunFlag); dryRun {
+			factory.enableDryRun()
+		} else {
+			factory.enableTransaction()
+		}
+		factory.env.NonInteractive, _ = cmd.Flags().GetBool(nonInteractiveFlag)
+		factory.env.AssumeYes, _ = cmd.Flags().GetBool(yesFlag)
+
+		switch output, _ := cmd.Flags().GetString(outputFlag); output {
+		case outputText:
+			if factory.onResult != nil {
+				factory.collectReport()
+			}
+		case outputJSON:
+			factory.enableReport()
+		default:
+			return fmt.Errorf("%s: invalid output format %q, expected one of %q or %q",
+				factory.errorMessage, output, outputText, outputJSON)
+		}
+
 		if createConfig {
 			// Check if a project configuration is already present.
 			if err := factory.store.Load(); err == nil || !errors.Is(err, os.ErrNotExist) {
@@ -239,6 +500,14 @@ func (factory *executionHooksFactory) preRunEFunc(
 			} else if err != nil {
 				return fmt.Errorf("%s: unable to load configuration file: %w", factory.errorMessage, err)
 			}
+
+			// Convert the project configuration if the project is being migrated.
+			if len(factory.migrateFrom) != 0 {
+				if err := factory.convertConfig(); err != nil {
+					return fmt.Errorf("%s: unable to convert configuration file to project version %q: %w",
+						factory.errorMessage, factory.projectVersion, err)
+				}
+			}
 		}
 		cfg := factory.store.Config()
 
@@ -258,8 +527,18 @@ func (factory *executionHooksFactory) preRunEFunc(
 			res = options.newResource()
 		}
 
+		// Inject environment hook.
+		if err := factory.forEach(ctx, func(subcommand plugin.Subcommand) error {
+			if subcommand, requiresEnvironment := subcommand.(plugin.RequiresEnvironment); requiresEnvironment {
+				return subcommand.InjectEnvironment(factory.env)
+			}
+			return nil
+		}, "unable to inject the environment to"); err != nil {
+			return err
+		}
+
 		// Inject config hook.
-		if err := factory.forEach(func(subcommand plugin.Subcommand) error {
+		if err := factory.forEach(ctx, func(subcommand plugin.Subcommand) error {
 			if subcommand, requiresConfig := subcommand.(plugin.RequiresConfig); requiresConfig {
 				return subcommand.InjectConfig(cfg)
 			}
@@ -270,7 +549,7 @@ func (factory *executionHooksFactory) preRunEFunc(
 
 		if res != nil {
 			// Inject resource hook.
-			if err := factory.forEach(func(subcommand plugin.Subcommand) error {
+			if err := factory.forEach(ctx, func(subcommand plugin.Subcommand) error {
 				if subcommand, requiresResource := subcommand.(plugin.RequiresResource); requiresResource {
 					return subcommand.InjectResource(res)
 				}
@@ -282,12 +561,19 @@ func (factory *executionHooksFactory) preRunEFunc(
 			if err := res.Validate(); err != nil {
 				return fmt.Errorf("%s: created invalid resource: %w", factory.errorMessage, err)
 			}
+
+			if factory.report != nil {
+				factory.report.Resource = &res.GVK
+			}
 		}
 
 		// Pre-scaffold hook.
 		// nolint:revive
-		if err := factory.forEach(func(subcommand plugin.Subcommand) error {
-			if subcommand, hasPreScaffold := subcommand.(plugin.HasPreScaffold); hasPreScaffold {
+		if err := factory.forEach(ctx, func(subcommand plugin.Subcommand) error {
+			switch subcommand := subcommand.(type) {
+			case plugin.HasPreScaffoldWithContext:
+				return subcommand.PreScaffoldWithContext(ctx, factory.fs)
+			case plugin.HasPreScaffold:
 				return subcommand.PreScaffold(factory.fs)
 			}
 			return nil
@@ -301,10 +587,15 @@ func (factory *executionHooksFactory) preRunEFunc(
 
 // runEFunc returns a cobra RunE function that executes the scaffold hook.
 func (factory *executionHooksFactory) runEFunc() func(*cobra.Command, []string) error {
-	return func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		ctx := factory.hooksContext(cmd)
+
 		// Scaffold hook.
 		// nolint:revive
-		if err := factory.forEach(func(subcommand plugin.Subcommand) error {
+		if err := factory.forEach(ctx, func(subcommand plugin.Subcommand) error {
+			if subcommand, hasContext := subcommand.(plugin.ScaffolderWithContext); hasContext {
+				return subcommand.ScaffoldWithContext(ctx, factory.fs)
+			}
 			return subcommand.Scaffold(factory.fs)
 		}, "unable to scaffold with"); err != nil {
 			return err
@@ -316,16 +607,32 @@ func (factory *executionHooksFactory) runEFunc() func(*cobra.Command, []string)
 
 // postRunEFunc returns a cobra RunE function that saves the configuration
 // and executes the post-scaffold hook.
-func (factory *executionHooksFactory) postRunEFunc() func(*cobra.Command, []string) error {
-	return func(*cobra.Command, []string) error {
+func (factory *executionHooksFactory) postRunEFunc(createConfig bool) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		ctx := factory.hooksContext(cmd)
+
 		if err := factory.store.Save(); err != nil {
 			return fmt.Errorf("%s: unable to save configuration file: %w", factory.errorMessage, err)
 		}
+		if factory.report != nil {
+			operation := machinery.FileUpdated
+			if createConfig {
+				operation = machinery.FileCreated
+			}
+			factory.report.addFile(machinery.FileReport{
+				Path:           factory.projectFile,
+				Operation:      operation,
+				IfExistsAction: machinery.OverwriteFile,
+			})
+		}
 
 		// Post-scaffold hook.
 		// nolint:revive
-		if err := factory.forEach(func(subcommand plugin.Subcommand) error {
-			if subcommand, hasPostScaffold := subcommand.(plugin.HasPostScaffold); hasPostScaffold {
+		if err := factory.forEach(ctx, func(subcommand plugin.Subcommand) error {
+			switch subcommand := subcommand.(type) {
+			case plugin.HasPostScaffoldWithContext:
+				return subcommand.PostScaffoldWithContext(ctx)
+			case plugin.HasPostScaffold:
 				return subcommand.PostScaffold()
 			}
 			return nil
@@ -333,6 +640,21 @@ func (factory *executionHooksFactory) postRunEFunc() func(*cobra.Command, []stri
 			return err
 		}
 
-		return nil
+		// Print the files that need to be reviewed manually.
+		factory.printFilesToReview()
+
+		// Print the changes that would have been made in dry-run mode.
+		if factory.dryRun != nil {
+			if err := factory.dryRun.writeDiff(factory.env.OutOrStdout()); err != nil {
+				return fmt.Errorf("%s: unable to compute the changes: %w", factory.errorMessage, err)
+			}
+		}
+
+		if factory.transaction != nil {
+			factory.transaction.commit()
+			factory.transaction = nil
+		}
+
+		return factory.writeReport(nil)
 	}
 }
@@ -0,0 +1,355 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+const (
+	validateErrorMsg = "failed to validate the project configuration"
+	schemaErrorMsg   = "failed to get the project configuration schema"
+)
+
+func (c *CLI) newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the project configuration",
+		Long:  `Inspect the project configuration stored in the PROJECT file.`,
+	}
+	cmd.AddCommand(c.newConfigValidateCmd())
+	cmd.AddCommand(c.newConfigSchemaCmd())
+	return cmd
+}
+
+func (c *CLI) newConfigValidateCmd() *cobra.Command {
+	var inputDir string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the PROJECT file",
+		Long: `Validate the PROJECT file and report every problem found with its line number.
+
+The file is validated against the JSON Schema of its project version, including the schemas of the configurations
+stored by the plugins. The resources must have valid group, version and kind, all of them must use the same CRD
+version and the same webhook version, and the files scaffolded for them must exist. The plugin keys in the layout
+and the plugins field must be known.
+`,
+		Example: fmt.Sprintf(`  # Validate the PROJECT file in the current directory
+  %[1]s alpha config validate
+
+  # Validate the PROJECT file of another project
+  %[1]s alpha config validate --%[2]s path/to/project
+`, c.commandName, inputDirFlag),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			problems, err := c.validateConfig(inputDir)
+			if err != nil {
+				return fmt.Errorf("%s: %w", validateErrorMsg, err)
+			}
+
+			path := filepath.Join(inputDir, c.projectFileName())
+			for _, problem := range problems {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", path, problem)
+			}
+			if len(problems) != 0 {
+				return fmt.Errorf("%s: found %d problem(s) in %s", validateErrorMsg, len(problems), path)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", path)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&inputDir, inputDirFlag, ".", "directory containing the PROJECT file of the project")
+
+	return cmd
+}
+
+func (c *CLI) newConfigSchemaCmd() *cobra.Command {
+	var projectVersionStr string
+
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the PROJECT file",
+		Long: `Print the JSON Schema of the PROJECT file of a project version, including the schemas of the
+configurations stored by the plugins.
+`,
+		Example: fmt.Sprintf(`  # Print the JSON Schema of the default project version
+  %[1]s alpha config schema
+
+  # Print the JSON Schema of the project version 2
+  %[1]s alpha config schema --%[2]s 2
+`, c.commandName, projectVersionFlag),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			version := c.defaultProjectVersion
+			if projectVersionStr != "" {
+				if err := version.Parse(projectVersionStr); err != nil {
+					return fmt.Errorf("%s: invalid project version %q: %w", schemaErrorMsg, projectVersionStr, err)
+				}
+			}
+
+			schema, err := c.configSchema(version)
+			if err != nil {
+				return fmt.Errorf("%s: %w", schemaErrorMsg, err)
+			}
+			out, err := json.MarshalIndent(schema, "", "  ")
+			if err != nil {
+				return fmt.Errorf("%s: %w", schemaErrorMsg, err)
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&projectVersionStr, projectVersionFlag, "", "project version")
+
+	return cmd
+}
+
+// configSchema returns the JSON Schema of the project configuration of the provided version,
+// including the schemas of the configurations stored by the plugins.
+func (c CLI) configSchema(version config.Version) (config.Schema, error) {
+	schema, err := config.GetSchema(version)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range c.allPlugins() {
+		if p, isHasConfigSchema := p.(plugin.HasConfigSchema); isHasConfigSchema {
+			// Project versions that do not support plugin configurations can not contain them
+			if err := schema.SetPluginSchema(plugin.KeyFor(p), p.ConfigSchema()); err != nil {
+				break
+			}
+		}
+	}
+	return schema, nil
+}
+
+// allPlugins returns the registered plugins, including the plugins bundled by them.
+func (c CLI) allPlugins() map[string]plugin.Plugin {
+	plugins := make(map[string]plugin.Plugin, len(c.plugins))
+	for key, p := range c.plugins {
+		plugins[key] = p
+		if bundle, isBundle := p.(plugin.Bundle); isBundle {
+			for _, bundled := range bundle.Plugins() {
+				plugins[plugin.KeyFor(bundled)] = bundled
+			}
+		}
+	}
+	return plugins
+}
+
+// validateConfig validates the PROJECT file in dir and returns every problem found.
+func (c CLI) validateConfig(dir string) ([]config.Problem, error) {
+	in, err := afero.ReadFile(c.filesystem().FS, filepath.Join(dir, c.projectFileName()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := kyaml.Parse(string(in))
+	if err != nil {
+		// The error already includes the line
+		return []config.Problem{{Message: err.Error()}}, nil
+	}
+	root := doc.YNode()
+	if root == nil || root.Kind != kyaml.MappingNode {
+		return []config.Problem{{Line: 1, Message: "must be a YAML object"}}, nil
+	}
+
+	// The schema depends on the project version
+	versionNode := lookupNode(root, "version")
+	if versionNode == nil {
+		return []config.Problem{{Line: root.Line, Message: `missing required field "version"`}}, nil
+	}
+	var version config.Version
+	if err := version.Parse(versionNode.Value); err != nil {
+		return []config.Problem{{Line: versionNode.Line, Field: "version", Message: err.Error()}}, nil
+	}
+	schema, err := c.configSchema(version)
+	if err != nil {
+		return []config.Problem{{Line: versionNode.Line, Field: "version", Message: err.Error()}}, nil
+	}
+
+	problems := schema.Validate(doc.Document())
+	problems = append(problems, c.validatePluginKeys(root)...)
+	problems = append(problems, c.validateResources(root, dir)...)
+	return problems, nil
+}
+
+// validatePluginKeys checks that the plugin keys in the layout and the plugins fields are known.
+func (c CLI) validatePluginKeys(root *kyaml.Node) []config.Problem {
+	plugins := c.allPlugins()
+
+	var problems []config.Problem
+	if layout := lookupNode(root, "layout"); layout != nil {
+		keys := []*kyaml.Node{layout}
+		if layout.Kind == kyaml.SequenceNode {
+			keys = layout.Content
+		}
+		for i, key := range keys {
+			if _, found := plugins[key.Value]; key.Kind == kyaml.ScalarNode && !found {
+				problems = append(problems, config.Problem{
+					Line:    key.Line,
+					Field:   fmt.Sprintf("layout[%d]", i),
+					Message: fmt.Sprintf("unknown plugin key %q", key.Value),
+				})
+			}
+		}
+	}
+	if pluginConfigs := lookupNode(root, "plugins"); pluginConfigs != nil && pluginConfigs.Kind == kyaml.MappingNode {
+		for i := 0; i+1 < len(pluginConfigs.Content); i += 2 {
+			key := pluginConfigs.Content[i]
+			if _, found := plugins[key.Value]; !found {
+				problems = append(problems, config.Problem{
+					Line:    key.Line,
+					Field:   "plugins." + key.Value,
+					Message: fmt.Sprintf("unknown plugin key %q", key.Value),
+				})
+			}
+		}
+	}
+	return problems
+}
+
+// validateResources checks the GVKs of the resources, that they use the same CRD and webhook versions,
+// and that the files scaffolded for them exist.
+func (c CLI) validateResources(root *kyaml.Node, dir string) []config.Problem {
+	resources := lookupNode(root, "resources")
+	if resources == nil || resources.Kind != kyaml.SequenceNode {
+		return nil
+	}
+
+	multiGroup := false
+	if node := lookupNode(root, "multigroup"); node != nil {
+		multiGroup = node.Value == "true"
+	}
+	scaffolders := c.layoutPlugins(root)
+
+	var (
+		problems                   []config.Problem
+		crdVersion, webhookVersion string
+		gvks                       = make(map[resource.GVK]bool, len(resources.Content))
+	)
+	for i, node := range resources.Content {
+		field := fmt.Sprintf("resources[%d]", i)
+		problem := func(fieldNode *kyaml.Node, field, format string, args ...interface{}) {
+			line := node.Line
+			if fieldNode != nil {
+				line = fieldNode.Line
+			}
+			problems = append(problems, config.Problem{Line: line, Field: field, Message: fmt.Sprintf(format, args...)})
+		}
+
+		// Type errors are already reported by the schema
+		var res resource.Resource
+		content, err := kyaml.String(node)
+		if err != nil || yaml.Unmarshal([]byte(content), &res) != nil {
+			continue
+		}
+
+		if err := res.GVK.Validate(); err != nil {
+			problem(node, field, "invalid GVK: %v", err)
+			continue
+		}
+		if gvks[res.GVK] {
+			problem(node, field, "duplicated resource %s", res.GVK)
+		}
+		gvks[res.GVK] = true
+
+		if res.API != nil && res.API.CRDVersion != "" {
+			versionNode := lookupNode(lookupNode(node, "api"), "crdVersion")
+			if crdVersion == "" {
+				crdVersion = res.API.CRDVersion
+			} else if res.API.CRDVersion != crdVersion {
+				problem(versionNode, field+".api.crdVersion", "CRD version %q is different from the CRD version %q "+
+					"of the previous resources", res.API.CRDVersion, crdVersion)
+			}
+		}
+		if res.Webhooks != nil && res.Webhooks.WebhookVersion != "" {
+			versionNode := lookupNode(lookupNode(node, "webhooks"), "webhookVersion")
+			if webhookVersion == "" {
+				webhookVersion = res.Webhooks.WebhookVersion
+			} else if res.Webhooks.WebhookVersion != webhookVersion {
+				problem(versionNode, field+".webhooks.webhookVersion", "webhook version %q is different from the "+
+					"webhook version %q of the previous resources", res.Webhooks.WebhookVersion, webhookVersion)
+			}
+		}
+
+		for _, p := range scaffolders {
+			for _, path := range p.ScaffoldedFiles(res, multiGroup) {
+				if exists, err := afero.Exists(c.filesystem().FS, filepath.Join(dir, path)); err == nil && !exists {
+					problem(node, field, "scaffolded file %q does not exist", path)
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// layoutPlugins returns the plugins in the layout of the project configuration, including the plugins bundled by
+// them, that can list the files they scaffold.
+func (c CLI) layoutPlugins(root *kyaml.Node) []plugin.HasScaffoldedFiles {
+	layout := lookupNode(root, "layout")
+	if layout == nil {
+		return nil
+	}
+	keys := []*kyaml.Node{layout}
+	if layout.Kind == kyaml.SequenceNode {
+		keys = layout.Content
+	}
+
+	allPlugins := c.allPlugins()
+	var result []plugin.HasScaffoldedFiles
+	for _, key := range keys {
+		p, found := allPlugins[key.Value]
+		if key.Kind != kyaml.ScalarNode || !found {
+			// Unknown plugin keys are already reported
+			continue
+		}
+		plugins := []plugin.Plugin{p}
+		if bundle, isBundle := p.(plugin.Bundle); isBundle {
+			plugins = bundle.Plugins()
+		}
+		for _, p := range plugins {
+			if p, hasScaffoldedFiles := p.(plugin.HasScaffoldedFiles); hasScaffoldedFiles {
+				result = append(result, p)
+			}
+		}
+	}
+	return result
+}
+
+// lookupNode returns the value of the key in the YAML mapping node, or nil if it is not present.
+func lookupNode(node *kyaml.Node, key string) *kyaml.Node {
+	if node == nil || node.Kind != kyaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+var _ = Describe("validateConfig", func() {
+	var c *CLI
+
+	BeforeEach(func() {
+		goPlugin, err := plugin.NewBundle("go.kubebuilder.io", plugin.Version{Number: 3},
+			mockScaffoldedFilesPlugin{newMockPlugin("base.go.kubebuilder.io", "v3", cfgv3.Version).(mockPlugin)})
+		Expect(err).NotTo(HaveOccurred())
+		schemaPlugin := newMockConfigSchemaPlugin("schema.kubebuilder.io", "v1", config.Schema{
+			"type":                 "object",
+			"additionalProperties": false,
+			"properties":           map[string]interface{}{"dir": map[string]interface{}{"type": "string"}},
+		}, cfgv3.Version)
+
+		c = &CLI{
+			commandName: "kubebuilder",
+			fs:          machinery.Filesystem{FS: afero.NewMemMapFs()},
+			plugins: map[string]plugin.Plugin{
+				plugin.KeyFor(goPlugin):     goPlugin,
+				plugin.KeyFor(schemaPlugin): schemaPlugin,
+			},
+		}
+		Expect(c.fs.FS.MkdirAll("project/api/v1", 0755)).To(Succeed())
+		Expect(afero.WriteFile(c.fs.FS, "project/api/v1/captain_types.go", []byte{}, 0644)).To(Succeed())
+	})
+
+	It("should not report problems for a valid project configuration", func() {
+		Expect(afero.WriteFile(c.fs.FS, "project/PROJECT", []byte(`domain: example.com
+layout:
+- go.kubebuilder.io/v3
+repo: github.com/example/project
+resources:
+- api:
+    crdVersion: v1
+  domain: example.com
+  group: crew
+  kind: Captain
+  version: v1
+plugins:
+  schema.kubebuilder.io/v1:
+    dir: templates
+version: "3"
+`), 0644)).To(Succeed())
+
+		Expect(c.validateConfig("project")).To(BeEmpty())
+	})
+
+	It("should report every problem with its line", func() {
+		Expect(afero.WriteFile(c.fs.FS, "project/PROJECT", []byte(`domain: example.com
+layout:
+- go.kubebuilder.io/v3
+- unknown.kubebuilder.io/v1
+repo: github.com/example/project
+resources:
+- api:
+    crdVersion: v1
+  domain: example.com
+  group: crew
+  kind: Captain
+  version: v1
+  controller: true
+- api:
+    crdVersion: v1beta1
+  domain: example.com
+  group: crew
+  kind: firstMate
+  version: v1
+- api:
+    crdVersion: v1beta1
+    namespaced: "yes"
+  domain: example.com
+  group: crew
+  kind: Admiral
+  version: v1
+  unknown: field
+plugins:
+  schema.kubebuilder.io/v1:
+    dir: 1
+  unknown.kubebuilder.io/v1: {}
+version: "3"
+`), 0644)).To(Succeed())
+
+		problems, err := c.validateConfig("project")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(ConsistOf(
+			config.Problem{Line: 22, Field: "resources[2].api.namespaced",
+				Message: "must be of type boolean, found string"},
+			config.Problem{Line: 27, Field: "resources[2].unknown", Message: "unknown field"},
+			config.Problem{Line: 30, Field: "plugins.schema.kubebuilder.io/v1.dir",
+				Message: "must be of type string, found integer"},
+			config.Problem{Line: 4, Field: "layout[1]", Message: `unknown plugin key "unknown.kubebuilder.io/v1"`},
+			config.Problem{Line: 31, Field: "plugins.unknown.kubebuilder.io/v1",
+				Message: `unknown plugin key "unknown.kubebuilder.io/v1"`},
+			config.Problem{Line: 7, Field: "resources[0]",
+				Message: `scaffolded file "controllers/captain_controller.go" does not exist`},
+			config.Problem{Line: 14, Field: "resources[1]",
+				Message: "invalid GVK: invalid Kind: must start with an uppercase character"},
+		))
+	})
+
+	It("should report resources with different CRD versions", func() {
+		Expect(afero.WriteFile(c.fs.FS, "project/PROJECT", []byte(`resources:
+- api:
+    crdVersion: v1
+  group: crew
+  kind: Captain
+  version: v1
+- api:
+    crdVersion: v1beta1
+  group: crew
+  kind: Admiral
+  version: v1
+version: "3"
+`), 0644)).To(Succeed())
+
+		Expect(c.validateConfig("project")).To(Equal([]config.Problem{{
+			Line:    8,
+			Field:   "resources[1].api.crdVersion",
+			Message: `CRD version "v1beta1" is different from the CRD version "v1" of the previous resources`,
+		}}))
+	})
+
+	It("should report an unsupported project version", func() {
+		Expect(afero.WriteFile(c.fs.FS, "project/PROJECT", []byte(`version: "1"
+`), 0644)).To(Succeed())
+
+		problems, err := c.validateConfig("project")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].Line).To(Equal(1))
+		Expect(problems[0].Field).To(Equal("version"))
+	})
+
+	It("should fail if there is no project configuration file", func() {
+		_, err := c.validateConfig("nonexistent")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("configSchema", func() {
+	It("should include the schemas of the plugin configurations", func() {
+		pluginSchema := config.Schema{"type": "object"}
+		p := newMockConfigSchemaPlugin("schema.kubebuilder.io", "v1", pluginSchema, cfgv3.Version)
+		c := &CLI{plugins: map[string]plugin.Plugin{plugin.KeyFor(p): p}}
+
+		schema, err := c.configSchema(cfgv3.Version)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(schema).To(HaveKeyWithValue("properties",
+			HaveKeyWithValue("plugins", HaveKeyWithValue("properties",
+				HaveKeyWithValue("schema.kubebuilder.io/v1", pluginSchema)))))
+	})
+})
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+// addCustomSubcommands adds the subcommands provided by the resolved plugins that implement plugin.Custom.
+// Invalid custom subcommands report their error when run, and those without path or whose path is already
+// taken by another command are skipped with a warning, so that they do not break the rest of the CLI.
+func (c *CLI) addCustomSubcommands() {
+	// Group the subcommands by path keeping the order in which they were first provided
+	paths := make([]string, 0)
+	shorts := make(map[string]string)
+	subcommands := make(map[string][]keySubcommandTuple)
+	errs := make(map[string]error)
+	for _, p := range c.unbundledPlugins() {
+		custom, isCustom := p.(plugin.Custom)
+		if !isCustom {
+			continue
+		}
+
+		key := plugin.KeyFor(p)
+		provided := make(map[string]struct{})
+		for _, subcommand := range custom.GetCustomSubcommands() {
+			path := strings.Join(strings.Fields(subcommand.Path), " ")
+			if path == "" {
+				fmt.Fprintf(c.stderr, "Warning: skipping custom subcommand of plugin %q: empty path\n", key)
+				continue
+			}
+
+			if _, exists := shorts[path]; !exists {
+				paths = append(paths, path)
+				shorts[path] = subcommand.Short
+			}
+
+			if subcommand.Subcommand == nil {
+				errs[path] = fmt.Errorf("plugin %q provides an invalid custom subcommand %q", key, path)
+				continue
+			}
+			if _, duplicated := provided[path]; duplicated {
+				errs[path] = fmt.Errorf("plugin %q provides the custom subcommand %q more than once", key, path)
+				continue
+			}
+			provided[path] = struct{}{}
+
+			subcommands[path] = append(subcommands[path], keySubcommandTuple{
+				key:        key,
+				subcommand: subcommand.Subcommand,
+			})
+		}
+	}
+
+	for _, path := range paths {
+		names := strings.Fields(path)
+
+		parent := c.cmd
+		for _, name := range names[:len(names)-1] {
+			cmd := findSubcommand(parent, name)
+			if cmd == nil {
+				cmd = &cobra.Command{Use: name}
+				parent.AddCommand(cmd)
+			}
+			parent = cmd
+		}
+
+		name := names[len(names)-1]
+		if findSubcommand(parent, name) != nil {
+			fmt.Fprintf(c.stderr, "Warning: skipping custom subcommand %q: command already exists\n", path)
+			continue
+		}
+
+		cmd := &cobra.Command{
+			Use:   name,
+			Short: shorts[path],
+			Long:  shorts[path] + "\n",
+		}
+		if err, invalid := errs[path]; invalid {
+			cmdErr(cmd, err)
+		} else {
+			c.applySubcommandHooks(cmd, subcommands[path], fmt.Sprintf("failed to %s", path), false)
+		}
+		parent.AddCommand(cmd)
+	}
+}
+
+// findSubcommand returns the direct subcommand of cmd with the provided name, or nil if there is none.
+func findSubcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, subcommand := range cmd.Commands() {
+		if subcommand.Name() == name {
+			return subcommand
+		}
+	}
+	return nil
+}
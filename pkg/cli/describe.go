@@ -0,0 +1,249 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+)
+
+const (
+	describeErrorMsg = "failed to describe the project"
+
+	outputTable = "table"
+	outputYAML  = "yaml"
+
+	scopeNamespaced = "Namespaced"
+	scopeCluster    = "Cluster"
+)
+
+// projectDescription is the description of a project printed by the describe command.
+type projectDescription struct {
+	Version         string                `json:"version"`
+	Domain          string                `json:"domain"`
+	Repository      string                `json:"repo"`
+	ProjectName     string                `json:"projectName"`
+	Layout          []string              `json:"layout"`
+	MultiGroup      bool                  `json:"multigroup"`
+	ComponentConfig bool                  `json:"componentConfig"`
+	Resources       []resourceDescription `json:"resources"`
+}
+
+// resourceDescription is the description of a resource of a project printed by the describe command.
+type resourceDescription struct {
+	Group   string `json:"group"`
+	Domain  string `json:"domain"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	Plural  string `json:"plural"`
+	// Scope is either Namespaced or Cluster, and empty if the resource has no API.
+	Scope      string              `json:"scope,omitempty"`
+	API        bool                `json:"api"`
+	Controller bool                `json:"controller"`
+	Webhooks   webhooksDescription `json:"webhooks"`
+	Path       string              `json:"path"`
+}
+
+// webhooksDescription is the description of the webhooks of a resource printed by the describe command.
+type webhooksDescription struct {
+	Defaulting bool `json:"defaulting"`
+	Validation bool `json:"validation"`
+	Conversion bool `json:"conversion"`
+}
+
+// String returns the comma-separated list of the webhooks, or "-" if there are none.
+func (w webhooksDescription) String() string {
+	var webhooks []string
+	if w.Defaulting {
+		webhooks = append(webhooks, "defaulting")
+	}
+	if w.Validation {
+		webhooks = append(webhooks, "validation")
+	}
+	if w.Conversion {
+		webhooks = append(webhooks, "conversion")
+	}
+	return orDash(strings.Join(webhooks, ","))
+}
+
+func (c *CLI) newDescribeCmd() *cobra.Command {
+	var inputDir, output string
+
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Describe the project and its resources",
+		Long: `Describe the project and its resources as recorded in its PROJECT file, without modifying it.
+
+The description includes the domain, repository, project name, layout, and the multigroup and component config
+settings of the project, and the group, version, kind, plural, scope, scaffolded API, controller and webhooks, and
+Go package path of every resource. It can be printed as a table, or as YAML or JSON to be processed by other tools.
+`,
+		Example: fmt.Sprintf(`  # Describe the project in the current directory
+  %[1]s alpha describe
+
+  # Print the description of another project as JSON
+  %[1]s alpha describe --%[2]s path/to/project --%[3]s %[4]s
+`, c.commandName, inputDirFlag, outputFlag, outputJSON),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			description, err := c.describe(inputDir)
+			if err != nil {
+				return fmt.Errorf("%s: %w", describeErrorMsg, err)
+			}
+			if err := writeDescription(cmd.OutOrStdout(), description, output); err != nil {
+				return fmt.Errorf("%s: %w", describeErrorMsg, err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&inputDir, inputDirFlag, ".", "directory containing the PROJECT file of the project")
+	cmd.Flags().StringVarP(&output, outputFlag, "o", outputTable,
+		fmt.Sprintf("output format, one of %q, %q or %q", outputTable, outputYAML, outputJSON))
+
+	return cmd
+}
+
+// describe loads the PROJECT file in dir and returns the description of the project.
+func (c CLI) describe(dir string) (projectDescription, error) {
+	store := newStore(c.filesystem(), filepath.Join(dir, c.projectFileName()))
+	if err := store.Load(); err != nil {
+		return projectDescription{}, err
+	}
+	cfg := store.Config()
+
+	resources, err := cfg.GetResources()
+	if err != nil {
+		return projectDescription{}, fmt.Errorf("unable to get the resources: %w", err)
+	}
+
+	description := projectDescription{
+		Version:         cfg.GetVersion().String(),
+		Domain:          cfg.GetDomain(),
+		Repository:      cfg.GetRepository(),
+		ProjectName:     cfg.GetProjectName(),
+		Layout:          cfg.GetPluginChain(),
+		MultiGroup:      cfg.IsMultiGroup(),
+		ComponentConfig: cfg.IsComponentConfig(),
+		Resources:       make([]resourceDescription, 0, len(resources)),
+	}
+	if description.Layout == nil {
+		description.Layout = []string{}
+	}
+	for _, res := range resources {
+		description.Resources = append(description.Resources, describeResource(res))
+	}
+	return description, nil
+}
+
+// describeResource returns the description of a resource.
+func describeResource(res resource.Resource) resourceDescription {
+	description := resourceDescription{
+		Group:      res.Group,
+		Domain:     res.Domain,
+		Version:    res.Version,
+		Kind:       res.Kind,
+		Plural:     res.Plural,
+		API:        res.HasAPI(),
+		Controller: res.HasController(),
+		Webhooks: webhooksDescription{
+			Defaulting: res.HasDefaultingWebhook(),
+			Validation: res.HasValidationWebhook(),
+			Conversion: res.HasConversionWebhook(),
+		},
+		Path: res.Path,
+	}
+	if res.HasAPI() {
+		description.Scope = scopeCluster
+		if res.API.Namespaced {
+			description.Scope = scopeNamespaced
+		}
+	}
+	return description
+}
+
+// writeDescription writes the description of the project to w in the provided output format.
+func writeDescription(w io.Writer, description projectDescription, output string) error {
+	switch output {
+	case outputTable:
+		return writeDescriptionTable(w, description)
+	case outputYAML:
+		out, err := yaml.Marshal(description)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	case outputJSON:
+		out, err := json.MarshalIndent(description, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(out))
+		return err
+	default:
+		return fmt.Errorf("invalid output format %q, expected one of %q, %q or %q",
+			output, outputTable, outputYAML, outputJSON)
+	}
+}
+
+// writeDescriptionTable writes the project fields followed by a table with a row per resource.
+func writeDescriptionTable(w io.Writer, description projectDescription) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Version:\t%s\n", description.Version)
+	fmt.Fprintf(tw, "Domain:\t%s\n", orDash(description.Domain))
+	fmt.Fprintf(tw, "Repository:\t%s\n", orDash(description.Repository))
+	fmt.Fprintf(tw, "Project name:\t%s\n", orDash(description.ProjectName))
+	fmt.Fprintf(tw, "Layout:\t%s\n", orDash(strings.Join(description.Layout, ",")))
+	fmt.Fprintf(tw, "Multigroup:\t%t\n", description.MultiGroup)
+	fmt.Fprintf(tw, "Component config:\t%t\n", description.ComponentConfig)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(description.Resources) == 0 {
+		_, err := fmt.Fprintln(w, "\nNo resources")
+		return err
+	}
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tVERSION\tKIND\tPLURAL\tSCOPE\tAPI\tCONTROLLER\tWEBHOOKS\tPATH")
+	for _, res := range description.Resources {
+		group := resource.GVK{Group: res.Group, Domain: res.Domain}.QualifiedGroup()
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			orDash(group), res.Version, res.Kind, res.Plural, orDash(res.Scope), strconv.FormatBool(res.API),
+			strconv.FormatBool(res.Controller), res.Webhooks, orDash(res.Path))
+	}
+	return tw.Flush()
+}
+
+// orDash returns the provided value, or "-" if it is empty.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+)
+
+var _ = Describe("describe", func() {
+	const projectFile = `domain: example.com
+layout:
+- go.kubebuilder.io/v3
+projectName: project
+repo: github.com/example/project
+resources:
+- api:
+    crdVersion: v1
+    namespaced: true
+  controller: true
+  domain: example.com
+  group: crew
+  kind: Captain
+  path: github.com/example/project/api/v1
+  version: v1
+  webhooks:
+    defaulting: true
+    validation: true
+    webhookVersion: v1
+- controller: true
+  group: apps
+  kind: Deployment
+  path: k8s.io/api/apps/v1
+  version: v1
+version: "3"
+`
+
+	var (
+		c *CLI
+
+		expected = projectDescription{
+			Version:     "3",
+			Domain:      "example.com",
+			Repository:  "github.com/example/project",
+			ProjectName: "project",
+			Layout:      []string{"go.kubebuilder.io/v3"},
+			Resources: []resourceDescription{
+				{
+					Group:      "crew",
+					Domain:     "example.com",
+					Version:    "v1",
+					Kind:       "Captain",
+					Plural:     "captains",
+					Scope:      scopeNamespaced,
+					API:        true,
+					Controller: true,
+					Webhooks:   webhooksDescription{Defaulting: true, Validation: true},
+					Path:       "github.com/example/project/api/v1",
+				},
+				{
+					Group:      "apps",
+					Version:    "v1",
+					Kind:       "Deployment",
+					Plural:     "deployments",
+					Controller: true,
+					Path:       "k8s.io/api/apps/v1",
+				},
+			},
+		}
+	)
+
+	BeforeEach(func() {
+		c = &CLI{fs: machinery.Filesystem{FS: afero.NewMemMapFs()}}
+		Expect(afero.WriteFile(c.fs.FS, "project/PROJECT", []byte(projectFile), 0644)).To(Succeed())
+	})
+
+	It("should describe the project and its resources", func() {
+		Expect(c.describe("project")).To(Equal(expected))
+	})
+
+	It("should fail if the project configuration can not be loaded", func() {
+		_, err := c.describe("other")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should print the project fields and a row per resource as a table", func() {
+		var out bytes.Buffer
+		Expect(writeDescription(&out, expected, outputTable)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("Layout:            go.kubebuilder.io/v3\n"))
+		Expect(out.String()).To(ContainSubstring("Multigroup:        false\n"))
+		Expect(out.String()).To(MatchRegexp(`crew\.example\.com +v1 +Captain +captains +Namespaced +true +true ` +
+			`+defaulting,validation +github\.com/example/project/api/v1\n`))
+		Expect(out.String()).To(MatchRegexp(`apps +v1 +Deployment +deployments +- +false +true +- ` +
+			`+k8s\.io/api/apps/v1\n`))
+	})
+
+	DescribeTable("should print the description in a machine-readable format",
+		func(output string, unmarshal func([]byte, interface{}) error) {
+			var out bytes.Buffer
+			Expect(writeDescription(&out, expected, output)).To(Succeed())
+
+			var description projectDescription
+			Expect(unmarshal(out.Bytes(), &description)).To(Succeed())
+			Expect(description).To(Equal(expected))
+		},
+		Entry("as YAML", outputYAML, func(b []byte, v interface{}) error { return yaml.Unmarshal(b, v) }),
+		Entry("as JSON", outputJSON, json.Unmarshal),
+	)
+
+	It("should fail for unknown output formats", func() {
+		Expect(writeDescription(&bytes.Buffer{}, expected, "xml")).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/internal/diff"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+)
+
+const (
+	dryRunFlag = "dry-run"
+
+	// dryRunContextLines is the number of context lines shown around each change.
+	dryRunContextLines = 3
+)
+
+// dryRunOverlay is a copy-on-write overlay of a filesystem that keeps every change in memory.
+type dryRunOverlay struct {
+	// base is the underlying filesystem, which is never modified.
+	base afero.Fs
+	// layer is the in-memory filesystem where changes are stored.
+	layer afero.Fs
+	// removed is the set of files of the base filesystem that have been removed.
+	removed map[string]struct{}
+}
+
+// newDryRunOverlay creates a dryRunOverlay on top of fs.
+func newDryRunOverlay(fs machinery.Filesystem) *dryRunOverlay {
+	return &dryRunOverlay{
+		base:    fs.FS,
+		layer:   afero.NewMemMapFs(),
+		removed: make(map[string]struct{}),
+	}
+}
+
+// filesystem returns the overlay filesystem that should be used for scaffolding.
+func (o *dryRunOverlay) filesystem() machinery.Filesystem {
+	return machinery.Filesystem{FS: dryRunFs{
+		Fs:      afero.NewCopyOnWriteFs(afero.NewReadOnlyFs(o.base), o.layer),
+		overlay: o,
+	}}
+}
+
+// dryRunFs is the copy-on-write filesystem of a dryRunOverlay.
+// Files of the base filesystem can not be removed from it, so their removal is recorded instead,
+// and they do not exist for this filesystem until they are created again.
+type dryRunFs struct {
+	afero.Fs
+
+	overlay *dryRunOverlay
+}
+
+// isRemoved returns true if name is a file of the base filesystem that has been removed.
+func (fs dryRunFs) isRemoved(name string) bool {
+	_, removed := fs.overlay.removed[filepath.Clean(name)]
+	return removed
+}
+
+// Create implements afero.Fs
+func (fs dryRunFs) Create(name string) (afero.File, error) {
+	f, err := fs.Fs.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	delete(fs.overlay.removed, filepath.Clean(name))
+	return f, nil
+}
+
+// Open implements afero.Fs
+func (fs dryRunFs) Open(name string) (afero.File, error) {
+	if fs.isRemoved(name) {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return fs.Fs.Open(name)
+}
+
+// OpenFile implements afero.Fs
+func (fs dryRunFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if fs.isRemoved(name) {
+		if flag&os.O_CREATE == 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		// The file is created again, so the contents of the base filesystem must not be kept
+		flag |= os.O_TRUNC
+	}
+	f, err := fs.Fs.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	if flag&os.O_CREATE != 0 {
+		delete(fs.overlay.removed, filepath.Clean(name))
+	}
+	return f, nil
+}
+
+// Stat implements afero.Fs
+func (fs dryRunFs) Stat(name string) (os.FileInfo, error) {
+	if fs.isRemoved(name) {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return fs.Fs.Stat(name)
+}
+
+// Remove implements afero.Fs
+func (fs dryRunFs) Remove(name string) error {
+	if fs.isRemoved(name) {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+
+	inBase, err := afero.Exists(fs.overlay.base, name)
+	if err != nil {
+		return err
+	}
+	if !inBase {
+		return fs.overlay.layer.Remove(name)
+	}
+
+	if err := fs.overlay.layer.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	fs.overlay.removed[filepath.Clean(name)] = struct{}{}
+	return nil
+}
+
+// writeDiff writes a unified diff of every file created, modified or removed in the overlay.
+func (o *dryRunOverlay) writeDiff(w io.Writer) error {
+	base, layer := o.base, o.layer
+
+	paths := make([]string, 0)
+	err := afero.Walk(layer, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// The scaffolding state is not part of the project, so it is not shown
+		if info.IsDir() && isStatePath(path) {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to list the changed files: %w", err)
+	}
+	for path := range o.removed {
+		if isStatePath(path) {
+			continue
+		}
+		if exists, _ := afero.Exists(layer, path); !exists {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := 0
+	for _, path := range paths {
+		newName := "b/" + path
+		newContent, err := afero.ReadFile(layer, path)
+		if os.IsNotExist(err) {
+			newName = "/dev/null"
+		} else if err != nil {
+			return fmt.Errorf("unable to read %q: %w", path, err)
+		}
+
+		oldName := "a/" + path
+		oldContent, err := afero.ReadFile(base, path)
+		if os.IsNotExist(err) {
+			oldName = "/dev/null"
+		} else if err != nil {
+			return fmt.Errorf("unable to read %q: %w", path, err)
+		}
+
+		if unified := diff.Unified(oldName, newName, string(oldContent), string(newContent),
+			dryRunContextLines); unified != "" {
+			if _, err := io.WriteString(w, unified); err != nil {
+				return err
+			}
+			changes++
+		}
+	}
+
+	if changes == 0 {
+		_, err = fmt.Fprintln(w, "No changes would be made to the project.")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+)
+
+var _ = Describe("dryRunOverlay", func() {
+	var (
+		base    afero.Fs
+		overlay *dryRunOverlay
+		fs      machinery.Filesystem
+	)
+
+	BeforeEach(func() {
+		base = afero.NewMemMapFs()
+		Expect(afero.WriteFile(base, "PROJECT", []byte("domain: example.com\n"), 0600)).To(Succeed())
+
+		overlay = newDryRunOverlay(machinery.Filesystem{FS: base})
+		fs = overlay.filesystem()
+	})
+
+	It("should not modify the underlying filesystem", func() {
+		Expect(afero.WriteFile(fs.FS, "PROJECT", []byte("domain: other.com\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs.FS, "main.go", []byte("package main\n"), 0600)).To(Succeed())
+
+		content, err := afero.ReadFile(base, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("domain: example.com\n"))
+		exists, err := afero.Exists(base, "main.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should print a diff of the created and modified files", func() {
+		Expect(afero.WriteFile(fs.FS, "PROJECT", []byte("domain: other.com\n"), 0600)).To(Succeed())
+		Expect(fs.FS.MkdirAll("config", 0700)).To(Succeed())
+		Expect(afero.WriteFile(fs.FS, "config/file.yaml", []byte("key: value\n"), 0600)).To(Succeed())
+
+		out := &bytes.Buffer{}
+		Expect(overlay.writeDiff(out)).To(Succeed())
+		Expect(out.String()).To(Equal(`--- a/PROJECT
++++ b/PROJECT
+@@ -1 +1 @@
+-domain: example.com
++domain: other.com
+--- /dev/null
++++ b/config/file.yaml
+@@ -0,0 +1 @@
++key: value
+`))
+	})
+
+	It("should print a diff of the removed files without removing them", func() {
+		Expect(fs.FS.Remove("PROJECT")).To(Succeed())
+
+		exists, err := afero.Exists(base, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+
+		out := &bytes.Buffer{}
+		Expect(overlay.writeDiff(out)).To(Succeed())
+		Expect(out.String()).To(Equal(`--- a/PROJECT
++++ /dev/null
+@@ -1 +0,0 @@
+-domain: example.com
+`))
+	})
+
+	It("should not find the removed files", func() {
+		Expect(fs.FS.Remove("PROJECT")).To(Succeed())
+
+		_, err := fs.FS.Stat("PROJECT")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		_, err = fs.FS.Open("PROJECT")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		_, err = fs.FS.OpenFile("PROJECT", os.O_RDWR, 0600)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+		Expect(os.IsNotExist(fs.FS.Remove("PROJECT"))).To(BeTrue())
+		exists, err := afero.Exists(fs.FS, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should create the removed files again without their previous contents", func() {
+		Expect(fs.FS.Remove("PROJECT")).To(Succeed())
+		f, err := fs.FS.OpenFile("PROJECT", os.O_CREATE|os.O_WRONLY, 0600)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		content, err := afero.ReadFile(fs.FS, "PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(BeEmpty())
+
+		Expect(fs.FS.Remove("PROJECT")).To(Succeed())
+		f, err = fs.FS.Create("PROJECT")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.WriteString("domain: other.com\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+
+		out := &bytes.Buffer{}
+		Expect(overlay.writeDiff(out)).To(Succeed())
+		Expect(out.String()).To(Equal(`--- a/PROJECT
++++ b/PROJECT
+@@ -1 +1 @@
+-domain: example.com
++domain: other.com
+`))
+	})
+
+	It("should not show the changes to the scaffolding state", func() {
+		pristine := filepath.Join(machinery.StateDirectory, "pristine", "main.go")
+		Expect(base.MkdirAll(filepath.Dir(pristine), 0700)).To(Succeed())
+		Expect(afero.WriteFile(base, pristine, []byte("package main\n"), 0600)).To(Succeed())
+		Expect(fs.FS.Remove(pristine)).To(Succeed())
+		Expect(afero.WriteFile(fs.FS, filepath.Join(machinery.StateDirectory, "new"), []byte("a"), 0600)).To(Succeed())
+
+		out := &bytes.Buffer{}
+		Expect(overlay.writeDiff(out)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("No changes"))
+	})
+
+	It("should report that there are no changes", func() {
+		Expect(afero.WriteFile(fs.FS, "PROJECT", []byte("domain: example.com\n"), 0600)).To(Succeed())
+
+		out := &bytes.Buffer{}
+		Expect(overlay.writeDiff(out)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("No changes"))
+	})
+})
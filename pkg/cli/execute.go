@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+)
+
+// Execute builds a CLI with the provided options and runs the subcommand described by args, e.g.,
+// []string{"create", "api", "--group", "crew", "--version", "v1", "--kind", "Captain"}, without relying
+// on os.Args. It allows tools that embed the CLI to run subcommands programmatically.
+//
+// The filesystem, working directory, environment, standard streams and command runner can be injected with
+// WithFilesystem, WithWorkingDir, WithEnv, WithStdin, WithStdout, WithStderr and WithRunner. They are not set
+// for the process, but handed to the plugins of each execution through its own util.Environment, together with
+// the dry-run, non-interactive and yes modes, so executions can run concurrently.
+//
+// The returned Result describes the execution of the plugin hooks, and is nil for subcommands that do not
+// run them, e.g., help or version, or if they were not reached. It is returned even if the execution fails.
+func Execute(ctx context.Context, args []string, options ...Option) (*Result, error) {
+	c, err := New(append(options, withArgs(args), withResult())...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.run(ctx)
+	return c.result, err
+}
+
+// environment returns the environment plugins are executed in, as set by options.
+func (c CLI) environment() *util.Environment {
+	env := make([]string, 0, len(c.env))
+	for key, value := range c.env {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+
+	return &util.Environment{
+		Dir:    c.workingDir,
+		Env:    env,
+		Stdin:  c.stdin,
+		Stdout: c.stdout,
+		Stderr: c.stderr,
+		Runner: c.runner,
+	}
+}
+
+// getenv returns the value of the environment variable named by key, looking it up in the environment
+// set by options before the one of the process.
+func (c CLI) getenv(key string) string {
+	if value, isSet := c.env[key]; isSet {
+		return value
+	}
+	return os.Getenv(key)
+}
+
+// filesystem returns the filesystem of the CLI, where relative paths are resolved from the working directory
+// set by options, if any.
+func (c CLI) filesystem() machinery.Filesystem {
+	fs := c.fs
+	if c.workingDir != "" {
+		fs.FS = workingDirFs{Fs: c.fs.FS, dir: c.workingDir}
+	}
+	return fs
+}
+
+// absPath returns the absolute representation of path, resolving it from the working directory of the CLI.
+func (c CLI) absPath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+	wd, err := c.getwd()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(wd, path), nil
+}
+
+// getwd returns the working directory of the CLI, which defaults to the one of the process.
+func (c CLI) getwd() (string, error) {
+	if c.workingDir != "" {
+		return c.workingDir, nil
+	}
+	return os.Getwd()
+}
+
+// workingDirFs is a filesystem that resolves relative paths from a working directory.
+type workingDirFs struct {
+	afero.Fs
+
+	dir string
+}
+
+func (fs workingDirFs) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(fs.dir, name)
+}
+
+// Create implements afero.Fs
+func (fs workingDirFs) Create(name string) (afero.File, error) {
+	return fs.Fs.Create(fs.path(name))
+}
+
+// Mkdir implements afero.Fs
+func (fs workingDirFs) Mkdir(name string, perm os.FileMode) error {
+	return fs.Fs.Mkdir(fs.path(name), perm)
+}
+
+// MkdirAll implements afero.Fs
+func (fs workingDirFs) MkdirAll(path string, perm os.FileMode) error {
+	return fs.Fs.MkdirAll(fs.path(path), perm)
+}
+
+// Open implements afero.Fs
+func (fs workingDirFs) Open(name string) (afero.File, error) {
+	return fs.Fs.Open(fs.path(name))
+}
+
+// OpenFile implements afero.Fs
+func (fs workingDirFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	return fs.Fs.OpenFile(fs.path(name), flag, perm)
+}
+
+// Remove implements afero.Fs
+func (fs workingDirFs) Remove(name string) error {
+	return fs.Fs.Remove(fs.path(name))
+}
+
+// RemoveAll implements afero.Fs
+func (fs workingDirFs) RemoveAll(path string) error {
+	return fs.Fs.RemoveAll(fs.path(path))
+}
+
+// Rename implements afero.Fs
+func (fs workingDirFs) Rename(oldname, newname string) error {
+	return fs.Fs.Rename(fs.path(oldname), fs.path(newname))
+}
+
+// Stat implements afero.Fs
+func (fs workingDirFs) Stat(name string) (os.FileInfo, error) {
+	return fs.Fs.Stat(fs.path(name))
+}
+
+// Chmod implements afero.Fs
+func (fs workingDirFs) Chmod(name string, mode os.FileMode) error {
+	return fs.Fs.Chmod(fs.path(name), mode)
+}
+
+// Chown implements afero.Fs
+func (fs workingDirFs) Chown(name string, uid, gid int) error {
+	return fs.Fs.Chown(fs.path(name), uid, gid)
+}
+
+// Chtimes implements afero.Fs
+func (fs workingDirFs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return fs.Fs.Chtimes(fs.path(name), atime, mtime)
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+)
+
+var _ = Describe("Execute", func() {
+	var (
+		projectVersion = config.Version{Number: 3}
+
+		fs   afero.Fs
+		docs *mockSubcommand
+		p    plugin.Plugin
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		Expect(afero.WriteFile(fs, "PROJECT", []byte(`domain: example.com
+layout:
+- custom.example.com/v1
+projectName: test
+repo: example.com/test
+version: "3"
+`), 0600)).To(Succeed())
+
+		docs = &mockSubcommand{}
+		p = newMockCustomPlugin("custom.example.com", "v1", []plugin.CustomSubcommand{
+			{Path: "generate docs", Short: "Generate the docs", Subcommand: docs},
+		}, projectVersion)
+	})
+
+	It("should run the subcommand described by the arguments and return its result", func() {
+		result, err := Execute(context.Background(), []string{"generate", "docs", "--flag", "value"},
+			WithPlugins(p),
+			WithDefaultProjectVersion(projectVersion),
+			WithFilesystem(fs),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(docs.flag).To(Equal("value"))
+		Expect(docs.hooks).To(Equal([]string{"InjectConfig", "PreScaffold", "Scaffold", "PostScaffold"}))
+		Expect(result).NotTo(BeNil())
+		Expect(result.Plugins).To(Equal([]string{"custom.example.com/v1"}))
+		Expect(result.Error).To(BeEmpty())
+	})
+
+	It("should return the result of failed executions", func() {
+		Expect(fs.Remove("PROJECT")).To(Succeed())
+
+		result, err := Execute(context.Background(), []string{"generate", "docs"},
+			WithPlugins(p),
+			WithDefaultPlugins(projectVersion, p),
+			WithDefaultProjectVersion(projectVersion),
+			WithFilesystem(fs),
+			WithStderr(new(bytes.Buffer)),
+		)
+		Expect(err).To(HaveOccurred())
+		Expect(result).NotTo(BeNil())
+		Expect(result.Error).To(Equal(err.Error()))
+		Expect(docs.hooks).To(BeEmpty())
+	})
+
+	Context("with context-aware hooks", func() {
+		var withContext *mockContextSubcommand
+
+		BeforeEach(func() {
+			withContext = &mockContextSubcommand{}
+			p = newMockCustomPlugin("custom.example.com", "v1", []plugin.CustomSubcommand{
+				{Path: "generate docs", Short: "Generate the docs", Subcommand: withContext},
+			}, projectVersion)
+		})
+
+		It("should prefer them over the regular hooks", func() {
+			_, err := Execute(context.Background(), []string{"generate", "docs"},
+				WithPlugins(p),
+				WithDefaultProjectVersion(projectVersion),
+				WithFilesystem(fs),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(withContext.hooks).To(Equal([]string{
+				"InjectConfig", "PreScaffoldWithContext", "ScaffoldWithContext", "PostScaffoldWithContext",
+			}))
+		})
+
+		It("should not run any other hook once the execution is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			withContext.onScaffold = cancel
+
+			result, err := Execute(ctx, []string{"generate", "docs"},
+				WithPlugins(p),
+				WithDefaultProjectVersion(projectVersion),
+				WithFilesystem(fs),
+				WithStderr(new(bytes.Buffer)),
+			)
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			Expect(result).NotTo(BeNil())
+			Expect(result.Error).To(Equal(err.Error()))
+			Expect(withContext.hooks).To(Equal([]string{
+				"InjectConfig", "PreScaffoldWithContext", "ScaffoldWithContext",
+			}))
+		})
+	})
+
+	Context("with commands run by plugins", func() {
+		var r *recordingRunner
+
+		BeforeEach(func() {
+			r = &recordingRunner{}
+			p = newMockCustomPlugin("custom.example.com", "v1", []plugin.CustomSubcommand{
+				{Path: "generate docs", Short: "Generate the docs", Subcommand: &mockCommandSubcommand{}},
+			}, projectVersion)
+		})
+
+		It("should run them in the executions that follow a dry-run one", func() {
+			_, err := Execute(context.Background(), []string{"generate", "docs", "--" + dryRunFlag},
+				WithPlugins(p),
+				WithDefaultProjectVersion(projectVersion),
+				WithFilesystem(fs),
+				WithRunner(r),
+				WithStdout(new(bytes.Buffer)),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.commands).To(BeEmpty())
+
+			_, err = Execute(context.Background(), []string{"generate", "docs"},
+				WithPlugins(p),
+				WithDefaultProjectVersion(projectVersion),
+				WithFilesystem(fs),
+				WithRunner(r),
+				WithStdout(new(bytes.Buffer)),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.commands).To(Equal([]string{"make generate"}))
+		})
+
+		It("should not share the runner and the dry-run mode with concurrent executions", func() {
+			dryRunRunner := &recordingRunner{}
+			errs := make(chan error, 2)
+			go func() {
+				_, err := Execute(context.Background(), []string{"generate", "docs", "--" + dryRunFlag},
+					WithPlugins(p),
+					WithDefaultProjectVersion(projectVersion),
+					WithFilesystem(fs),
+					WithRunner(dryRunRunner),
+					WithStdout(new(bytes.Buffer)),
+				)
+				errs <- err
+			}()
+			go func() {
+				_, err := Execute(context.Background(), []string{"generate", "docs"},
+					WithPlugins(p),
+					WithDefaultProjectVersion(projectVersion),
+					WithFilesystem(fs),
+					WithRunner(r),
+					WithStdout(new(bytes.Buffer)),
+				)
+				errs <- err
+			}()
+			Expect(<-errs).To(Succeed())
+			Expect(<-errs).To(Succeed())
+
+			Expect(dryRunRunner.commands).To(BeEmpty())
+			Expect(r.commands).To(Equal([]string{"make generate"}))
+		})
+	})
+
+	Context("with a working directory, environment and standard streams", func() {
+		var withEnvironment *mockEnvironmentSubcommand
+
+		BeforeEach(func() {
+			withEnvironment = &mockEnvironmentSubcommand{}
+			p = newMockCustomPlugin("custom.example.com", "v1", []plugin.CustomSubcommand{
+				{Path: "generate docs", Short: "Generate the docs", Subcommand: withEnvironment},
+			}, projectVersion)
+
+			Expect(fs.MkdirAll("/project", 0755)).To(Succeed())
+			Expect(fs.Rename("PROJECT", "/project/PROJECT")).To(Succeed())
+		})
+
+		It("should execute the plugins in them without modifying the ones of the process", func() {
+			wd, err := os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			r := &recordingRunner{}
+			stdout := new(bytes.Buffer)
+
+			_, err = Execute(context.Background(), []string{"generate", "docs"},
+				WithPlugins(p),
+				WithDefaultProjectVersion(projectVersion),
+				WithFilesystem(fs),
+				WithWorkingDir("/project"),
+				WithEnv(map[string]string{"KUBEBUILDER_TEST_DOCS": "md"}),
+				WithStdin(strings.NewReader("y\n")),
+				WithStdout(stdout),
+				WithRunner(r),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(withEnvironment.confirmed).To(BeTrue())
+			Expect(afero.ReadFile(fs, "/project/docs.md")).To(Equal([]byte("docs")))
+			Expect(r.commands).To(Equal([]string{"make docs"}))
+			Expect(r.dir).To(Equal("/project"))
+			Expect(r.env).To(Equal([]string{"KUBEBUILDER_TEST_DOCS=md"}))
+			Expect(stdout.String()).To(ContainSubstring("Generate the docs? [y/n]\n"))
+			Expect(stdout.String()).To(ContainSubstring("Publish the docs:\n$ make docs\n"))
+
+			Expect(os.Getwd()).To(Equal(wd))
+			Expect(os.LookupEnv("KUBEBUILDER_TEST_DOCS")).To(BeEmpty())
+		})
+
+		It("should fail if the working directory does not exist in the filesystem", func() {
+			_, err := Execute(context.Background(), []string{"generate", "docs"},
+				WithPlugins(p),
+				WithDefaultProjectVersion(projectVersion),
+				WithFilesystem(fs),
+				WithWorkingDir("/missing"),
+			)
+			Expect(err).To(MatchError(ContainSubstring("invalid working directory")))
+		})
+	})
+
+	It("should write to the provided standard output", func() {
+		stdout := new(bytes.Buffer)
+		result, err := Execute(context.Background(), []string{"version"},
+			WithVersion("v1.2.3"),
+			WithPlugins(p),
+			WithDefaultProjectVersion(projectVersion),
+			WithFilesystem(fs),
+			WithStdout(stdout),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(stdout.String()).To(Equal("v1.2.3\n"))
+	})
+})
+
+// mockCommandSubcommand runs a command in the post-scaffold hook.
+type mockCommandSubcommand struct {
+	mockSubcommand
+}
+
+func (s *mockCommandSubcommand) PostScaffoldWithContext(ctx context.Context) error {
+	return util.RunCmdWithContext(ctx, "Generate code", "make", "generate")
+}
+
+// mockEnvironmentSubcommand asks for confirmation before scaffolding a file and running a command.
+type mockEnvironmentSubcommand struct {
+	mockSubcommand
+	env       *util.Environment
+	confirmed bool
+}
+
+func (s *mockEnvironmentSubcommand) InjectEnvironment(env *util.Environment) error {
+	s.env = env
+	return nil
+}
+
+func (s *mockEnvironmentSubcommand) Scaffold(fs machinery.Filesystem) (err error) {
+	if s.confirmed, err = s.env.Confirm("Generate the docs?", "force"); err != nil || !s.confirmed {
+		return err
+	}
+	if err := afero.WriteFile(fs.FS, "docs.md", []byte("docs"), 0600); err != nil {
+		return err
+	}
+	return fs.Environment.RunCmd(context.Background(), "Publish the docs", "make", "docs")
+}
+
+// recordingRunner records the commands instead of running them, and the working directory and environment
+// variables of the last one.
+type recordingRunner struct {
+	commands []string
+	dir      string
+	env      []string
+}
+
+func (r *recordingRunner) Run(_ context.Context, cmd util.Command) error {
+	r.commands = append(r.commands, cmd.String())
+	r.dir, r.env = cmd.Dir, cmd.Env
+	return nil
+}
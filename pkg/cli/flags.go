@@ -0,0 +1,249 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+const (
+	// pluginFlagAnnotation is the annotation of the flags of a plugin, whose values are the plugin key and prefix.
+	pluginFlagAnnotation = "kubebuilder.io/plugin"
+
+	// flagUsagesTemplateFunc is the name of the template function that prints the flags grouped by plugin.
+	flagUsagesTemplateFunc = "flagUsagesByPlugin"
+)
+
+func init() {
+	cobra.AddTemplateFunc(flagUsagesTemplateFunc, flagUsagesByPlugin)
+}
+
+// pluginFlags are the flags bound by a plugin.
+type pluginFlags struct {
+	key    string
+	prefix string
+	flags  *pflag.FlagSet
+	shared map[string]bool
+}
+
+// bindPluginFlags executes the bind flags hook of each subcommand on a separate flag set and adds their flags
+// to the command prefixed by the short name of the plugin, e.g. `--go.force`. Flags are also added without prefix
+// if no other plugin binds them or if all the plugins that bind them share them (see plugin.SharesFlags).
+// Flags that are bound by several plugins but not shared can not be provided without prefix.
+func bindPluginFlags(cmd *cobra.Command, subcommands []keySubcommandTuple) {
+	all := make([]pluginFlags, 0, len(subcommands))
+	for _, tuple := range subcommands {
+		subcommand, hasFlags := tuple.subcommand.(plugin.HasFlags)
+		if !hasFlags {
+			continue
+		}
+
+		p := pluginFlags{
+			key:    tuple.key,
+			flags:  pflag.NewFlagSet(tuple.key, pflag.ContinueOnError),
+			shared: make(map[string]bool),
+		}
+		subcommand.BindFlags(p.flags)
+		if subcommand, sharesFlags := tuple.subcommand.(plugin.SharesFlags); sharesFlags {
+			for _, name := range subcommand.SharedFlags() {
+				p.shared[name] = true
+			}
+		}
+		all = append(all, p)
+	}
+	setFlagPrefixes(all)
+
+	// Flags without prefix by name, in the order they were bound
+	names := make([]string, 0)
+	unprefixed := make(map[string][]*pflag.Flag)
+	owners := make(map[string][]pluginFlags)
+	for _, p := range all {
+		p.flags.VisitAll(func(flag *pflag.Flag) {
+			if _, exists := unprefixed[flag.Name]; !exists {
+				names = append(names, flag.Name)
+			}
+			unprefixed[flag.Name] = append(unprefixed[flag.Name], flag)
+			owners[flag.Name] = append(owners[flag.Name], p)
+		})
+	}
+
+	// Flags of the CLI take precedence over the ones without prefix
+	aliased := make(map[string]bool, len(names))
+	for _, name := range names {
+		if cmd.Flags().Lookup(name) != nil {
+			continue
+		}
+
+		flags := unprefixed[name]
+		switch {
+		case len(flags) == 1:
+			alias := copyFlag(flags[0], name, flags...)
+			alias.Annotations = map[string][]string{pluginFlagAnnotation: {owners[name][0].key, owners[name][0].prefix}}
+			cmd.Flags().AddFlag(alias)
+			aliased[name] = true
+		case isShared(name, flags, owners[name]):
+			cmd.Flags().AddFlag(copyFlag(flags[0], name, flags...))
+		default:
+			alias := copyFlag(flags[0], name)
+			alias.Shorthand = ""
+			alias.Hidden = true
+			alias.Value = ambiguousFlagValue{Value: flags[0].Value, name: name, owners: owners[name]}
+			cmd.Flags().AddFlag(alias)
+		}
+	}
+
+	for _, p := range all {
+		p.flags.VisitAll(func(flag *pflag.Flag) {
+			scoped := copyFlag(flag, p.prefix+"."+flag.Name, flag)
+			scoped.Shorthand = ""
+			scoped.Annotations = map[string][]string{pluginFlagAnnotation: {p.key, p.prefix}}
+			// The prefixed version is not shown in the help of flags that can be provided without prefix
+			scoped.Hidden = flag.Hidden || aliased[flag.Name]
+			cmd.Flags().AddFlag(scoped)
+		})
+	}
+}
+
+// setFlagPrefixes sets the prefix of the flags of each plugin to the short name of the plugin,
+// or to its full name if several plugins have the same short name.
+func setFlagPrefixes(all []pluginFlags) {
+	count := make(map[string]int, len(all))
+	for i := range all {
+		name, _ := plugin.SplitKey(all[i].key)
+		all[i].prefix = plugin.GetShortName(name)
+		count[all[i].prefix]++
+	}
+	for i := range all {
+		if count[all[i].prefix] > 1 {
+			all[i].prefix, _ = plugin.SplitKey(all[i].key)
+		}
+	}
+}
+
+// isShared checks if all the plugins that bind a flag share it and bind it with the same type.
+func isShared(name string, flags []*pflag.Flag, owners []pluginFlags) bool {
+	for i, flag := range flags {
+		if !owners[i].shared[name] || flag.Value.Type() != flags[0].Value.Type() {
+			return false
+		}
+	}
+	return true
+}
+
+// copyFlag returns a copy of flag with the provided name that sets the value of the targets.
+func copyFlag(flag *pflag.Flag, name string, targets ...*pflag.Flag) *pflag.Flag {
+	return &pflag.Flag{
+		Name:                name,
+		Shorthand:           flag.Shorthand,
+		Usage:               flag.Usage,
+		Value:               sharedFlagValue{Value: flag.Value, targets: targets},
+		DefValue:            flag.DefValue,
+		NoOptDefVal:         flag.NoOptDefVal,
+		Deprecated:          flag.Deprecated,
+		Hidden:              flag.Hidden,
+		ShorthandDeprecated: flag.ShorthandDeprecated,
+	}
+}
+
+// sharedFlagValue is a pflag.Value that sets the value of several flags, flagging them as changed.
+// The embedded value is only used to obtain the type and the string representation.
+type sharedFlagValue struct {
+	pflag.Value
+
+	targets []*pflag.Flag
+}
+
+// Set implements pflag.Value
+func (v sharedFlagValue) Set(value string) error {
+	for _, target := range v.targets {
+		if err := target.Value.Set(value); err != nil {
+			return err
+		}
+		target.Changed = true
+	}
+	return nil
+}
+
+// ambiguousFlagValue is a pflag.Value that fails to be set as several plugins bind the flag without sharing it.
+type ambiguousFlagValue struct {
+	pflag.Value
+
+	name   string
+	owners []pluginFlags
+}
+
+// Set implements pflag.Value
+func (v ambiguousFlagValue) Set(string) error {
+	alternatives := make([]string, 0, len(v.owners))
+	for _, owner := range v.owners {
+		alternatives = append(alternatives, fmt.Sprintf("--%s.%s", owner.prefix, v.name))
+	}
+	return fmt.Errorf("several plugins provide this flag, use one of %s instead", strings.Join(alternatives, ", "))
+}
+
+// groupFlagUsages replaces the flag usages of the command in its usage template by the ones grouped by plugin.
+func groupFlagUsages(cmd *cobra.Command) {
+	const localFlagUsages = "{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}"
+	template := cmd.UsageTemplate()
+	if strings.Contains(template, localFlagUsages) {
+		cmd.SetUsageTemplate(strings.Replace(template, localFlagUsages,
+			"{{"+flagUsagesTemplateFunc+" . | trimTrailingWhitespaces}}", 1))
+	}
+}
+
+// flagUsagesByPlugin returns the usages of the local flags of the command, with the flags of each plugin
+// in a separate section after the rest.
+func flagUsagesByPlugin(cmd *cobra.Command) string {
+	common := pflag.NewFlagSet("common", pflag.ContinueOnError)
+	groups := make(map[string]*pflag.FlagSet)
+	prefixes := make(map[string]string)
+	keys := make([]string, 0)
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		values := flag.Annotations[pluginFlagAnnotation]
+		if len(values) != 2 {
+			common.AddFlag(flag)
+			return
+		}
+
+		key := values[0]
+		if _, exists := groups[key]; !exists {
+			groups[key] = pflag.NewFlagSet(key, pflag.ContinueOnError)
+			prefixes[key] = values[1]
+			keys = append(keys, key)
+		}
+		groups[key].AddFlag(flag)
+	})
+	sort.Strings(keys)
+
+	usages := new(strings.Builder)
+	_, _ = usages.WriteString(common.FlagUsages())
+	for _, key := range keys {
+		if !groups[key].HasAvailableFlags() {
+			continue
+		}
+		_, _ = fmt.Fprintf(usages, "\nFlags of plugin %q (also accepted with the \"--%s.\" prefix):\n%s",
+			key, prefixes[key], groups[key].FlagUsages())
+	}
+	return usages.String()
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+)
+
+// flagsSubcommand binds a set of flags, sharing some of them.
+type flagsSubcommand struct {
+	force  bool
+	plural string
+	make   bool
+	shared []string
+
+	flags *pflag.FlagSet
+}
+
+func (s *flagsSubcommand) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&s.force, "force", false, "force")
+	fs.StringVar(&s.plural, "plural", "", "plural")
+	fs.BoolVar(&s.make, "make", true, "make")
+	s.flags = fs
+}
+
+func (s *flagsSubcommand) SharedFlags() []string { return s.shared }
+
+func (s *flagsSubcommand) Scaffold(machinery.Filesystem) error { return nil }
+
+var _ = Describe("bindPluginFlags", func() {
+	var (
+		cmd       *cobra.Command
+		goSub     *flagsSubcommand
+		kustomize *flagsSubcommand
+		onlyGo    bool
+	)
+
+	BeforeEach(func() {
+		cmd = &cobra.Command{Use: "api"}
+		goSub = &flagsSubcommand{shared: []string{"force"}}
+		kustomize = &flagsSubcommand{shared: []string{"force"}}
+		onlyGo = false
+	})
+
+	bind := func() {
+		subcommands := []keySubcommandTuple{{key: "go.kubebuilder.io/v3", subcommand: goSub}}
+		if !onlyGo {
+			subcommands = append(subcommands, keySubcommandTuple{
+				key: "kustomize.common.kubebuilder.io/v1", subcommand: kustomize,
+			})
+		}
+		bindPluginFlags(cmd, subcommands)
+	}
+
+	It("should set the flags prefixed by the short name of the plugin", func() {
+		bind()
+
+		Expect(cmd.ParseFlags([]string{"--go.plural", "mice", "--kustomize.force"})).To(Succeed())
+		Expect(goSub.plural).To(Equal("mice"))
+		Expect(goSub.flags.Changed("plural")).To(BeTrue())
+		Expect(goSub.force).To(BeFalse())
+		Expect(kustomize.plural).To(BeEmpty())
+		Expect(kustomize.force).To(BeTrue())
+		Expect(kustomize.flags.Changed("force")).To(BeTrue())
+	})
+
+	It("should set shared flags without prefix for every plugin", func() {
+		bind()
+
+		Expect(cmd.ParseFlags([]string{"--force"})).To(Succeed())
+		Expect(goSub.force).To(BeTrue())
+		Expect(kustomize.force).To(BeTrue())
+		Expect(goSub.flags.Changed("force")).To(BeTrue())
+		Expect(kustomize.flags.Changed("force")).To(BeTrue())
+	})
+
+	It("should set flags of a single plugin without prefix", func() {
+		onlyGo = true
+		bind()
+
+		Expect(cmd.ParseFlags([]string{"--plural", "mice", "--make=false"})).To(Succeed())
+		Expect(goSub.plural).To(Equal("mice"))
+		Expect(goSub.make).To(BeFalse())
+		Expect(goSub.flags.Changed("make")).To(BeTrue())
+	})
+
+	It("should fail for flags of several plugins that are not shared", func() {
+		bind()
+
+		err := cmd.ParseFlags([]string{"--plural", "mice"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("--go.plural, --kustomize.plural"))
+		Expect(goSub.plural).To(BeEmpty())
+		Expect(kustomize.plural).To(BeEmpty())
+	})
+
+	It("should not share flags unless every plugin shares them", func() {
+		kustomize.shared = nil
+		bind()
+
+		Expect(cmd.ParseFlags([]string{"--force"})).NotTo(Succeed())
+	})
+
+	It("should not override the flags of the CLI", func() {
+		var plural string
+		cmd.Flags().StringVar(&plural, "plural", "", "plural")
+		onlyGo = true
+		bind()
+
+		Expect(cmd.ParseFlags([]string{"--plural", "mice", "--go.plural", "geese"})).To(Succeed())
+		Expect(plural).To(Equal("mice"))
+		Expect(goSub.plural).To(Equal("geese"))
+	})
+
+	It("should group the flags by plugin in the help", func() {
+		cmd.Flags().String("group", "", "resource Group")
+		bind()
+		groupFlagUsages(cmd)
+
+		usages := flagUsagesByPlugin(cmd)
+		Expect(usages).To(ContainSubstring("--force"))
+		Expect(usages).To(ContainSubstring("--group"))
+		Expect(usages).To(ContainSubstring(`Flags of plugin "go.kubebuilder.io/v3" ` +
+			`(also accepted with the "--go." prefix):`))
+		Expect(usages).To(ContainSubstring("--go.plural"))
+		Expect(usages).To(ContainSubstring(`Flags of plugin "kustomize.common.kubebuilder.io/v1"`))
+		Expect(usages).To(ContainSubstring("--kustomize.plural"))
+		Expect(usages).NotTo(ContainSubstring("--plural"))
+		Expect(cmd.UsageTemplate()).To(ContainSubstring(flagUsagesTemplateFunc))
+	})
+})
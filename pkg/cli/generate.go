@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	yamlstore "sigs.k8s.io/kubebuilder/v3/pkg/config/store/yaml"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+const (
+	generateErrorMsg = "failed to generate project"
+
+	inputDirFlag  = "input-dir"
+	outputDirFlag = "output-dir"
+)
+
+func (c *CLI) newGenerateCmd() *cobra.Command {
+	var inputDir, outputDir string
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Re-scaffold a project from its PROJECT file",
+		Long: `Re-scaffold a project from its PROJECT file into a new directory.
+
+The project is initialized with the domain, repository, project name, multigroup and component config settings
+recorded in the PROJECT file, and every resource is created again with its recorded API, controller and webhooks.
+The latest versions of the plugins in the project layout are used, so the output directory contains a clean
+baseline scaffolded with the current plugins that can be compared with the original project.
+`,
+		Example: fmt.Sprintf(`  # Re-scaffold the project in the current directory into a new directory
+  %[1]s alpha generate --%[2]s ../upgraded-project
+
+  # Print the commands that would be run without running them
+  %[1]s alpha generate --%[3]s path/to/project --%[2]s path/to/output --%[4]s
+`, c.commandName, outputDirFlag, inputDirFlag, dryRunFlag),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+			if err := c.generate(commandContext(cmd), inputDir, outputDir, dryRun); err != nil {
+				return fmt.Errorf("%s: %w", generateErrorMsg, err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&inputDir, inputDirFlag, ".", "directory containing the PROJECT file of the project")
+	cmd.Flags().StringVar(&outputDir, outputDirFlag, "", "directory where the project is re-scaffolded")
+	_ = cmd.MarkFlagRequired(outputDirFlag)
+
+	return cmd
+}
+
+// generate re-scaffolds the project in inputDir into outputDir by replaying its commands in-process, each of them
+// with a CLI built with the same options and run from outputDir. In dry-run mode, the commands are only printed.
+func (c *CLI) generate(ctx context.Context, inputDir, outputDir string, dryRun bool) error {
+	fs := c.filesystem()
+	store := newStore(fs, filepath.Join(inputDir, c.projectFileName()))
+	if err := store.Load(); err != nil {
+		return err
+	}
+
+	// The output directory must not contain a project already
+	if _, err := fs.FS.Stat(filepath.Join(outputDir, yamlstore.DefaultPath)); err == nil {
+		return fmt.Errorf("%q already contains a %s file", outputDir, yamlstore.DefaultPath)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("unable to check the output directory %q: %w", outputDir, err)
+	}
+
+	commands, err := generateCommands(store.Config())
+	if err != nil {
+		return err
+	}
+
+	// Commands are run from the output directory, as the plugins scaffold and run their commands in the working
+	// directory
+	workingDir, err := c.absPath(outputDir)
+	if err != nil {
+		return fmt.Errorf("invalid output directory %q: %w", outputDir, err)
+	}
+	if !dryRun {
+		if err := fs.FS.MkdirAll(workingDir, 0755); err != nil {
+			return fmt.Errorf("unable to create the output directory %q: %w", outputDir, err)
+		}
+	}
+
+	for _, args := range commands {
+		subcommand := args[:1]
+		for i, arg := range args {
+			if strings.HasPrefix(arg, "-") {
+				subcommand = args[:i]
+				break
+			}
+		}
+		fmt.Fprintf(c.stdout, "Running %[1]s %[2]s:\n$ %[1]s %[3]s\n",
+			c.commandName, strings.Join(subcommand, " "), strings.Join(args, " "))
+		if dryRun {
+			continue
+		}
+
+		replay, err := New(append(c.options, WithWorkingDir(workingDir), withArgs(args))...)
+		if err != nil {
+			return fmt.Errorf("unable to create the CLI to run %q: %w", strings.Join(args, " "), err)
+		}
+		if err := replay.run(ctx); err != nil {
+			return fmt.Errorf("unable to run %q: %w", strings.Join(args, " "), err)
+		}
+	}
+
+	return nil
+}
+
+// generateCommands returns the arguments of the commands that re-scaffold the project described by cfg
+// with the latest versions of the plugins in its layout.
+func generateCommands(cfg config.Config) ([][]string, error) {
+	latest := plugin.VersionRange{Latest: true}.String()
+	pluginKeys := make([]string, 0, len(cfg.GetPluginChain()))
+	for _, key := range cfg.GetPluginChain() {
+		name, _ := plugin.SplitKey(key)
+		pluginKeys = append(pluginKeys, path.Join(name, latest))
+	}
+
+	initArgs := []string{"init",
+		"--" + projectVersionFlag, cfg.GetVersion().String(),
+		"--" + pluginsFlag, strings.Join(pluginKeys, ","),
+		"--domain", cfg.GetDomain(),
+		"--repo", cfg.GetRepository(),
+	}
+	if cfg.GetProjectName() != "" {
+		initArgs = append(initArgs, "--project-name", cfg.GetProjectName())
+	}
+	if cfg.IsComponentConfig() {
+		initArgs = append(initArgs, "--component-config")
+	}
+	commands := [][]string{initArgs}
+
+	if cfg.IsMultiGroup() {
+		commands = append(commands, []string{"edit", "--multigroup"})
+	}
+
+	resources, err := cfg.GetResources()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the resources of the project: %w", err)
+	}
+	for _, res := range resources {
+		gvkArgs := []string{"--version", res.Version, "--kind", res.Kind}
+		if res.Group != "" {
+			gvkArgs = append([]string{"--group", res.Group}, gvkArgs...)
+		}
+		if !res.IsRegularPlural() {
+			gvkArgs = append(gvkArgs, "--plural", res.Plural)
+		}
+
+		// External resources are always created, as their API types are imported from their module instead
+		if res.HasAPI() || res.HasController() || res.IsExternal() {
+			apiArgs := append([]string{"create", "api"}, gvkArgs...)
+			apiArgs = append(apiArgs,
+				"--resource="+strconv.FormatBool(res.HasAPI()),
+				"--controller="+strconv.FormatBool(res.HasController()),
+			)
+			if res.IsExternal() {
+				apiArgs = append(apiArgs, "--module", res.GetModule())
+			}
+			if res.HasAPI() {
+				apiArgs = append(apiArgs, apiFlags(res.API)...)
+			}
+			if res.GetOwner() != "" {
+				apiArgs = append(apiArgs, "--owner", res.GetOwner())
+			}
+			commands = append(commands, apiArgs)
+		}
+
+		if res.HasDefaultingWebhook() || res.HasValidationWebhook() || res.HasConversionWebhook() {
+			webhookArgs := append([]string{"create", "webhook"}, gvkArgs...)
+			if res.Webhooks.WebhookVersion != "" {
+				webhookArgs = append(webhookArgs, "--webhook-version", res.Webhooks.WebhookVersion)
+			}
+			if res.HasDefaultingWebhook() {
+				webhookArgs = append(webhookArgs, "--defaulting")
+			}
+			if res.HasValidationWebhook() {
+				webhookArgs = append(webhookArgs, "--programmatic-validation")
+			}
+			if res.HasConversionWebhook() {
+				webhookArgs = append(webhookArgs, "--conversion")
+			}
+			commands = append(commands, webhookArgs)
+		}
+	}
+
+	return commands, nil
+}
+
+// apiFlags returns the flags of the create api command that scaffold the provided API.
+func apiFlags(api *resource.API) []string {
+	flags := make([]string, 0)
+	if api.CRDVersion != "" {
+		flags = append(flags, "--crd-version", api.CRDVersion)
+	}
+	if !api.Namespaced {
+		flags = append(flags, "--namespaced=false")
+	}
+	if shortNames := api.GetShortNames(); len(shortNames) != 0 {
+		flags = append(flags, "--short-names", strings.Join(shortNames, ","))
+	}
+	if categories := api.GetCategories(); len(categories) != 0 {
+		flags = append(flags, "--categories", strings.Join(categories, ","))
+	}
+	subresources := make([]string, 0, 2)
+	if api.HasStatusSubresource() {
+		subresources = append(subresources, "status")
+	}
+	if api.HasScaleSubresource() {
+		subresources = append(subresources, "scale")
+	}
+	if len(subresources) != 0 {
+		flags = append(flags, "--subresources", strings.Join(subresources, ","))
+	}
+	if api.IsStorageVersion() {
+		flags = append(flags, "--storage-version")
+	}
+	return flags
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	cfgv4 "sigs.k8s.io/kubebuilder/v3/pkg/config/v4"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+var _ = Describe("generateCommands", func() {
+	It("should replay the project initialization and every resource with the latest plugins", func() {
+		cfg := cfgv3.New()
+		Expect(cfg.SetPluginChain([]string{"go.kubebuilder.io/v3"})).To(Succeed())
+		Expect(cfg.SetDomain("example.com")).To(Succeed())
+		Expect(cfg.SetRepository("github.com/example/project")).To(Succeed())
+		Expect(cfg.SetProjectName("project")).To(Succeed())
+		Expect(cfg.SetMultiGroup()).To(Succeed())
+		Expect(cfg.SetComponentConfig()).To(Succeed())
+		Expect(cfg.AddResource(resource.Resource{
+			GVK:        resource.GVK{Group: "crew", Domain: "example.com", Version: "v1", Kind: "Captain"},
+			Plural:     "captains",
+			API:        &resource.API{CRDVersion: "v1", Namespaced: true},
+			Controller: true,
+			Webhooks:   &resource.Webhooks{WebhookVersion: "v1", Defaulting: true, Conversion: true},
+		})).To(Succeed())
+		Expect(cfg.AddResource(resource.Resource{
+			GVK:    resource.GVK{Group: "crew", Domain: "example.com", Version: "v1", Kind: "Admiral"},
+			Plural: "admirales",
+			API:    &resource.API{CRDVersion: "v1"},
+		})).To(Succeed())
+		Expect(cfg.AddResource(resource.Resource{
+			GVK:        resource.GVK{Group: "apps", Domain: "k8s.io", Version: "v1", Kind: "Deployment"},
+			Plural:     "deployments",
+			Controller: true,
+		})).To(Succeed())
+
+		Expect(generateCommands(cfg)).To(Equal([][]string{
+			{"init", "--project-version", "3", "--plugins", "go.kubebuilder.io/latest", "--domain", "example.com",
+				"--repo", "github.com/example/project", "--project-name", "project", "--component-config"},
+			{"edit", "--multigroup"},
+			{"create", "api", "--group", "crew", "--version", "v1", "--kind", "Captain",
+				"--resource=true", "--controller=true", "--crd-version", "v1"},
+			{"create", "webhook", "--group", "crew", "--version", "v1", "--kind", "Captain",
+				"--webhook-version", "v1", "--defaulting", "--conversion"},
+			{"create", "api", "--group", "crew", "--version", "v1", "--kind", "Admiral", "--plural", "admirales",
+				"--resource=true", "--controller=false", "--crd-version", "v1", "--namespaced=false"},
+			{"create", "api", "--group", "apps", "--version", "v1", "--kind", "Deployment",
+				"--resource=false", "--controller=true"},
+		}))
+	})
+
+	It("should replay the project version 4 resource fields", func() {
+		cfg := cfgv4.New()
+		Expect(cfg.SetPluginChain([]string{"go.kubebuilder.io/v3"})).To(Succeed())
+		Expect(cfg.SetDomain("example.com")).To(Succeed())
+		Expect(cfg.SetRepository("github.com/example/project")).To(Succeed())
+		Expect(cfg.AddResource(resource.Resource{
+			GVK:    resource.GVK{Group: "crew", Domain: "example.com", Version: "v1", Kind: "Captain"},
+			Plural: "captains",
+			API: &resource.API{
+				CRDVersion:     "v1",
+				Namespaced:     true,
+				ShortNames:     []string{"cpt", "cap"},
+				Categories:     []string{"all"},
+				Subresources:   &resource.Subresources{Status: true, Scale: true},
+				StorageVersion: true,
+			},
+			Owner: "crew-team",
+		})).To(Succeed())
+		Expect(cfg.AddResource(resource.Resource{
+			GVK:      resource.GVK{Group: "ship", Domain: "example.com", Version: "v1", Kind: "Frigate"},
+			Plural:   "frigates",
+			Path:     "github.com/example/apis/api/v1",
+			Module:   "github.com/example/apis",
+			Webhooks: &resource.Webhooks{WebhookVersion: "v1", Defaulting: true},
+		})).To(Succeed())
+
+		Expect(generateCommands(cfg)).To(Equal([][]string{
+			{"init", "--project-version", "4", "--plugins", "go.kubebuilder.io/latest", "--domain", "example.com",
+				"--repo", "github.com/example/project"},
+			{"create", "api", "--group", "crew", "--version", "v1", "--kind", "Captain",
+				"--resource=true", "--controller=false", "--crd-version", "v1", "--short-names", "cpt,cap",
+				"--categories", "all", "--subresources", "status,scale", "--storage-version", "--owner", "crew-team"},
+			{"create", "api", "--group", "ship", "--version", "v1", "--kind", "Frigate",
+				"--resource=false", "--controller=false", "--module", "github.com/example/apis"},
+			{"create", "webhook", "--group", "ship", "--version", "v1", "--kind", "Frigate",
+				"--webhook-version", "v1", "--defaulting"},
+		}))
+	})
+	It("should fail if the resources of the project can not be listed", func() {
+		_, err := generateCommands(failingResourcesConfig{cfgv3.New()})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// failingResourcesConfig is a config.Config that fails to list its resources.
+type failingResourcesConfig struct {
+	config.Config
+}
+
+func (failingResourcesConfig) GetResources() ([]resource.Resource, error) {
+	return nil, errors.New("unable to list resources")
+}
+
+var _ = Describe("generate", func() {
+	var c *CLI
+
+	BeforeEach(func() {
+		c = &CLI{commandName: "kubebuilder", fs: machinery.Filesystem{FS: afero.NewMemMapFs()}}
+		Expect(afero.WriteFile(c.fs.FS, "input/PROJECT", []byte(`domain: example.com
+layout:
+- go.kubebuilder.io/v3
+projectName: project
+repo: github.com/example/project
+version: "3"
+`), 0600)).To(Succeed())
+	})
+
+	It("should fail if the input directory does not contain a project", func() {
+		Expect(c.generate(context.Background(), "output", "other", true)).NotTo(Succeed())
+	})
+
+	It("should fail if the output directory already contains a project", func() {
+		Expect(afero.WriteFile(c.fs.FS, "output/PROJECT", []byte{}, 0600)).To(Succeed())
+		Expect(c.generate(context.Background(), "input", "output", true)).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("alpha generate", func() {
+	var (
+		fs     afero.Fs
+		p      plugin.Plugin
+		stdout *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		Expect(afero.WriteFile(fs, "/project/input/PROJECT", []byte(`domain: example.com
+layout:
+- custom.example.com/v1
+projectName: project
+repo: github.com/example/project
+version: "3"
+`), 0600)).To(Succeed())
+
+		p = mockInitPlugin{
+			mockPlugin: newMockPlugin("custom.example.com", "v1", cfgv3.Version).(mockPlugin),
+			subcommand: &mockInitSubcommand{},
+		}
+		stdout = new(bytes.Buffer)
+	})
+
+	execute := func(args ...string) error {
+		_, err := Execute(context.Background(), append([]string{alphaCommand, "generate"}, args...),
+			WithPlugins(p),
+			WithDefaultProjectVersion(cfgv3.Version),
+			WithFilesystem(fs),
+			WithWorkingDir("/project"),
+			WithStdout(stdout),
+		)
+		return err
+	}
+
+	It("should replay the commands of the project in the output directory", func() {
+		wd, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(execute("--input-dir", "input", "--output-dir", "output")).To(Succeed())
+
+		Expect(afero.ReadFile(fs, "/project/output/README.md")).To(Equal([]byte("project")))
+		Expect(afero.Exists(fs, "/project/output/PROJECT")).To(BeTrue())
+		Expect(stdout.String()).To(ContainSubstring("Running kubebuilder init:\n" +
+			"$ kubebuilder init --project-version 3 --plugins custom.example.com/latest --domain example.com " +
+			"--repo github.com/example/project --project-name project"))
+		Expect(os.Getwd()).To(Equal(wd))
+	})
+
+	It("should only print the commands in dry-run mode", func() {
+		Expect(execute("--input-dir", "input", "--output-dir", "output", "--"+dryRunFlag)).To(Succeed())
+
+		Expect(afero.Exists(fs, "/project/output")).To(BeFalse())
+		Expect(stdout.String()).To(ContainSubstring(
+			"$ kubebuilder init --project-version 3 --plugins custom.example.com/latest"))
+	})
+})
+
+// mockInitPlugin is a plugin that only initializes projects.
+type mockInitPlugin struct {
+	mockPlugin
+	subcommand plugin.InitSubcommand
+}
+
+func (p mockInitPlugin) GetInitSubcommand() plugin.InitSubcommand { return p.subcommand }
+
+// mockInitSubcommand initializes a project with the flags replayed by generate, scaffolding a README.md file.
+type mockInitSubcommand struct {
+	mockSubcommand
+	domain, repo, projectName string
+}
+
+func (s *mockInitSubcommand) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.domain, "domain", "", "")
+	fs.StringVar(&s.repo, "repo", "", "")
+	fs.StringVar(&s.projectName, "project-name", "", "")
+}
+
+func (s *mockInitSubcommand) InjectConfig(c config.Config) error {
+	if err := c.SetDomain(s.domain); err != nil {
+		return err
+	}
+	if err := c.SetRepository(s.repo); err != nil {
+		return err
+	}
+	return c.SetProjectName(s.projectName)
+}
+
+func (s *mockInitSubcommand) Scaffold(fs machinery.Filesystem) error {
+	return afero.WriteFile(fs.FS, "README.md", []byte(s.projectName), 0600)
+}
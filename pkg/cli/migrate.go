@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+const (
+	migrateErrorMsg = "failed to migrate project"
+
+	migrateToFlag = "to"
+)
+
+func (c *CLI) newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the project to other plugins",
+		Long: `Migrate the project to other plugins, e.g. to a newer version of the plugins it was scaffolded with.
+
+The project configuration is converted to the project version supported by the plugins the project is migrated to,
+and its plugin chain is replaced by their keys. These plugins regenerate the scaffolded files that changed,
+and list the files that could not be migrated automatically so that they can be reviewed.
+`,
+		Example: fmt.Sprintf(`  # Migrate the project to the plugins used to initialize new projects
+  %[1]s alpha migrate
+
+  # Migrate the project to the go/v3 plugin
+  %[1]s alpha migrate --%[2]s go/v3
+
+  # Migrate the project to project version 4 and its default plugins
+  %[1]s alpha migrate --%[3]s 4
+`, c.commandName, migrateToFlag, projectVersionFlag),
+		RunE: errCmdFunc(
+			fmt.Errorf("project must be initialized"),
+		),
+	}
+	cmd.Flags().StringSlice(migrateToFlag, nil,
+		"plugin keys to migrate the project to, by default the ones used to initialize new projects")
+	cmd.Flags().String(projectVersionFlag, "",
+		"project version to migrate the project to, by default the one supported by the plugins it is migrated to")
+
+	// In case no plugin was resolved, instead of failing the construction of the CLI, fail the execution of
+	// this subcommand. This allows the use of subcommands that do not require resolved plugins like help.
+	if len(c.resolvedPlugins) == 0 {
+		cmdErr(cmd, noResolvedPluginError{})
+		return cmd
+	}
+
+	targetKeys, targetVersion, err := c.getMigrationTargets()
+	if err != nil {
+		cmdErr(cmd, err)
+		return cmd
+	}
+	targetPlugins, targetVersion, err := c.resolvePluginKeys(targetKeys, targetVersion)
+	if err != nil {
+		cmdErr(cmd, fmt.Errorf("unable to resolve the plugins to migrate to: %w", err))
+		return cmd
+	}
+
+	// Plugins may migrate projects scaffolded with a bundle or with any of the plugins it wraps.
+	previousKeys := make([]string, 0, len(c.resolvedPlugins))
+	for _, p := range c.resolvedPlugins {
+		previousKeys = appendKey(previousKeys, plugin.KeyFor(p))
+	}
+	for _, p := range c.unbundledPlugins() {
+		previousKeys = appendKey(previousKeys, plugin.KeyFor(p))
+	}
+	targetKeys = make([]string, 0, len(targetPlugins))
+	for _, p := range targetPlugins {
+		targetKeys = append(targetKeys, plugin.KeyFor(p))
+	}
+
+	// Obtain the plugin keys and subcommands from the plugins that can migrate from any of the previous plugins.
+	subcommands := make([]keySubcommandTuple, 0, len(targetPlugins))
+	for _, p := range unbundlePlugins(targetPlugins) {
+		migrator, isMigrator := p.(plugin.Migrator)
+		if !isMigrator {
+			continue
+		}
+		for _, previousKey := range previousKeys {
+			if subcommand := migrator.MigrateFrom(previousKey); subcommand != nil {
+				subcommands = append(subcommands, keySubcommandTuple{key: plugin.KeyFor(p), subcommand: subcommand})
+				break
+			}
+		}
+	}
+
+	// Verify that there is at least one remaining plugin, unless the project keeps its plugins and
+	// only its project version changes, which just requires converting the project configuration.
+	if len(subcommands) == 0 && !(c.samePlugins(targetPlugins) && targetVersion.Compare(c.projectVersion) != 0) {
+		cmdErr(cmd, fmt.Errorf("none of the plugins %s can migrate a project scaffolded with %s",
+			strings.Join(targetKeys, ", "), strings.Join(previousKeys, ", ")))
+		return cmd
+	}
+
+	c.applyHooks(cmd, executionHooksFactory{
+		subcommands:     subcommands,
+		errorMessage:    migrateErrorMsg,
+		projectVersion:  targetVersion,
+		pluginChain:     targetKeys,
+		resolvedPlugins: targetKeys,
+		migrateFrom:     previousKeys,
+	}, false)
+
+	return cmd
+}
+
+// getMigrationTargets obtains the keys of the plugins and the project version to migrate the project to from flags.
+// If not provided, the plugin keys default to the default plugins of the provided project version, if any, or else to
+// those of the default project version, and the project version is left empty so that it is resolved from the plugins.
+func (c CLI) getMigrationTargets() ([]string, config.Version, error) {
+	var projectVersion config.Version
+
+	// Partially parse the command line arguments, as the plugins need to be known to build the command
+	fs := pflag.NewFlagSet("migrate", pflag.ContinueOnError)
+	fs.StringSlice(migrateToFlag, nil, "")
+	fs.String(projectVersionFlag, "", "")
+	fs.BoolP("help", "h", false, "")
+	fs.ParseErrorsWhitelist = pflag.ParseErrorsWhitelist{UnknownFlags: true}
+	if err := fs.Parse(c.arguments()); err != nil {
+		return nil, projectVersion, err
+	}
+
+	if projectVersionStr, err := fs.GetString(projectVersionFlag); err != nil {
+		return nil, projectVersion, err
+	} else if projectVersionStr != "" {
+		if err := projectVersion.Parse(projectVersionStr); err != nil {
+			return nil, projectVersion, fmt.Errorf("invalid project version flag: %w", err)
+		}
+	}
+
+	pluginKeys, err := fs.GetStringSlice(migrateToFlag)
+	if err != nil {
+		return nil, projectVersion, err
+	}
+	if len(pluginKeys) == 0 {
+		if pluginKeys = c.defaultPlugins[projectVersion]; len(pluginKeys) == 0 {
+			pluginKeys = c.defaultPlugins[c.defaultProjectVersion]
+		}
+		if len(pluginKeys) == 0 {
+			return nil, projectVersion,
+				fmt.Errorf("no default plugins to migrate to, provide them with the --%s flag", migrateToFlag)
+		}
+		return pluginKeys, projectVersion, nil
+	}
+
+	// Remove leading and trailing spaces and validate the plugin keys
+	for i, key := range pluginKeys {
+		pluginKeys[i] = strings.TrimSpace(key)
+		if err := plugin.ValidateKey(pluginKeys[i]); err != nil {
+			return nil, projectVersion, fmt.Errorf("invalid plugin %q found in flags: %w", pluginKeys[i], err)
+		}
+	}
+	return pluginKeys, projectVersion, nil
+}
+
+// samePlugins returns true if the provided plugins are the ones the project was scaffolded with.
+func (c CLI) samePlugins(plugins []plugin.Plugin) bool {
+	if len(plugins) != len(c.resolvedPlugins) {
+		return false
+	}
+	for i, p := range plugins {
+		if plugin.KeyFor(p) != plugin.KeyFor(c.resolvedPlugins[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendKey appends the key to the provided keys if not already present.
+func appendKey(keys []string, key string) []string {
+	for _, k := range keys {
+		if k == key {
+			return keys
+		}
+	}
+	return append(keys, key)
+}
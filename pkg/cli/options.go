@@ -17,14 +17,27 @@ limitations under the License.
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/external"
 )
 
+// externalPluginsPathEnvVar is the environment variable that overrides the external plugins root directory.
+const externalPluginsPathEnvVar = "EXTERNAL_PLUGINS_PATH"
+
 // Option is a function used as arguments to New in order to configure the resulting CLI.
 type Option func(*CLI) error
 
@@ -139,3 +152,216 @@ func WithCompletion() Option {
 		return nil
 	}
 }
+
+// WithFilesystem is an Option that sets the filesystem the CLI reads the project configuration from
+// and scaffolds files to. It defaults to the OS filesystem.
+func WithFilesystem(fs afero.Fs) Option {
+	return func(c *CLI) error {
+		if fs == nil {
+			return errors.New("invalid nil filesystem")
+		}
+		c.fs = machinery.Filesystem{FS: fs}
+		return nil
+	}
+}
+
+// WithWorkingDir is an Option that sets the directory the CLI is run from, which must exist in the filesystem.
+//
+// The working directory of the process is not changed: relative paths are resolved from it by the filesystem
+// of the CLI, and it is the working directory of the commands run by plugins.
+func WithWorkingDir(dir string) Option {
+	return func(c *CLI) error {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("invalid working directory %q: %w", dir, err)
+		}
+		c.workingDir = absDir
+		return nil
+	}
+}
+
+// WithEnv is an Option that sets environment variables for the CLI, e.g. EXTERNAL_PLUGINS_PATH, and for the
+// commands run by plugins. They are set on top of the environment of the process, which is not modified.
+func WithEnv(env map[string]string) Option {
+	return func(c *CLI) error {
+		if c.env == nil {
+			c.env = make(map[string]string, len(env))
+		}
+		for key, value := range env {
+			c.env[key] = value
+		}
+		return nil
+	}
+}
+
+// WithStdin is an Option that sets the standard input of the CLI, where the answers to prompts are read from.
+// Unlike the standard input of the process, it does not need to be attached to a terminal.
+func WithStdin(r io.Reader) Option {
+	return func(c *CLI) error {
+		c.stdin = r
+		return nil
+	}
+}
+
+// WithStdout is an Option that sets the standard output of the CLI.
+// Messages printed by plugins and the output of the commands they run are also written to it.
+func WithStdout(w io.Writer) Option {
+	return func(c *CLI) error {
+		c.stdout = w
+		return nil
+	}
+}
+
+// WithStderr is an Option that sets the standard error of the CLI.
+// The errors of the commands run by plugins are also written to it.
+func WithStderr(w io.Writer) Option {
+	return func(c *CLI) error {
+		c.stderr = w
+		return nil
+	}
+}
+
+// WithRunner is an Option that sets the Runner of the commands run by plugins, e.g. `go mod tidy` or `make`.
+// It allows to stub, record or substitute them. They are run as processes by default.
+func WithRunner(r util.Runner) Option {
+	return func(c *CLI) error {
+		if r == nil {
+			return errors.New("invalid nil runner")
+		}
+		c.runner = r
+		return nil
+	}
+}
+
+// withArgs is an Option that sets the command line arguments, without the command name.
+func withArgs(args []string) Option {
+	return func(c *CLI) error {
+		c.args = append([]string{}, args...)
+		return nil
+	}
+}
+
+// withResult is an Option that makes the CLI collect the result of the executed subcommand.
+func withResult() Option {
+	return func(c *CLI) error {
+		c.collectResult = true
+		return nil
+	}
+}
+
+// getPluginsRoot returns the directory where external plugins are discovered.
+//
+// It defaults to $XDG_CONFIG_HOME/kubebuilder/plugins on Linux and
+// $HOME/Library/Application Support/kubebuilder/plugins on OSX, and can be
+// overridden with the EXTERNAL_PLUGINS_PATH environment variable, which is looked up with getenv.
+// It is a variable so that tests can override it.
+var getPluginsRoot = func(getenv func(string) string) (string, error) {
+	if pluginsRoot := getenv(externalPluginsPathEnvVar); pluginsRoot != "" {
+		return pluginsRoot, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "kubebuilder", "plugins"), nil
+}
+
+// discoverExternalPlugins finds the external plugins placed in the plugins root directory.
+//
+// Every plugin executable is expected at ${root}/${name}/${version}/${name}. The executable may
+// also be named after the plugin short name, i.e., the name before the first dot. The command line
+// arguments, including the command name, are used to build the arguments sent to the plugins.
+// The project versions supported by each plugin are obtained with a metadata request to its executable,
+// which is run in the environment carried by ctx.
+// Plugins that cannot be loaded, e.g. because their executable is not executable, their version
+// is invalid or they do not answer the metadata request, are skipped with a warning written to stderr
+// so that they do not break the CLI.
+func discoverExternalPlugins(
+	ctx context.Context, fs afero.Fs, args []string, getenv func(string) string, stderr io.Writer,
+) ([]plugin.Plugin, error) {
+	pluginsRoot, err := getPluginsRoot(getenv)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the external plugins root: %w", err)
+	}
+
+	pluginInfos, err := afero.ReadDir(fs, pluginsRoot)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read the external plugins root %q: %w", pluginsRoot, err)
+	}
+
+	warn := func(format string, a ...interface{}) {
+		fmt.Fprintf(stderr, "Warning: skipping external plugin: "+format+"\n", a...)
+	}
+
+	var plugins []plugin.Plugin
+	for _, pluginInfo := range pluginInfos {
+		if !pluginInfo.IsDir() {
+			continue
+		}
+
+		pluginDir := filepath.Join(pluginsRoot, pluginInfo.Name())
+		versionInfos, err := afero.ReadDir(fs, pluginDir)
+		if err != nil {
+			warn("unable to read directory %q: %v", pluginDir, err)
+			continue
+		}
+
+		for _, versionInfo := range versionInfos {
+			if !versionInfo.IsDir() {
+				continue
+			}
+
+			versionDir := filepath.Join(pluginDir, versionInfo.Name())
+			fileInfos, err := afero.ReadDir(fs, versionDir)
+			if err != nil {
+				warn("unable to read directory %q: %v", versionDir, err)
+				continue
+			}
+
+			for _, fileInfo := range fileInfos {
+				if fileInfo.IsDir() || (fileInfo.Name() != pluginInfo.Name() &&
+					fileInfo.Name() != plugin.GetShortName(pluginInfo.Name())) {
+					continue
+				}
+
+				path := filepath.Join(versionDir, fileInfo.Name())
+				if fileInfo.Mode()&0111 == 0 {
+					warn("%q is not executable", path)
+					break
+				}
+
+				p := external.Plugin{
+					PName: pluginInfo.Name(),
+					Path:  path,
+					Args:  parseExternalPluginArgs(args),
+				}
+				if err := p.PVersion.Parse(versionInfo.Name()); err != nil {
+					warn("invalid version %q for %q: %v", versionInfo.Name(), pluginInfo.Name(), err)
+					break
+				}
+				if p.PSupportedProjectVersions, err = external.GetSupportedProjectVersions(ctx, path); err != nil {
+					warn("unable to get the supported project versions of %q: %v", path, err)
+					break
+				}
+				plugins = append(plugins, p)
+				break
+			}
+		}
+	}
+
+	return plugins, nil
+}
+
+// parseExternalPluginArgs returns the arguments that will be sent to external plugins, i.e.,
+// every command line argument starting from the first flag.
+func parseExternalPluginArgs(args []string) []string {
+	for i, arg := range args {
+		if i != 0 && strings.HasPrefix(arg, "-") {
+			return append([]string(nil), args[i:]...)
+		}
+	}
+	return []string{}
+}
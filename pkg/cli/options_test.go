@@ -17,14 +17,25 @@ limitations under the License.
 package cli
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/stage"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/external"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 var _ = Describe("CLI options", func() {
@@ -255,4 +266,204 @@ var _ = Describe("CLI options", func() {
 		})
 	})
 
+	Context("WithFilesystem", func() {
+		It("should use the OS filesystem by default", func() {
+			c, err = newCLI()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).NotTo(BeNil())
+			Expect(c.fs.FS).To(BeAssignableToTypeOf(afero.NewOsFs()))
+		})
+
+		It("should read the project configuration from the provided filesystem", func() {
+			dir, err := ioutil.TempDir("", "kubebuilder-filesystem")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.RemoveAll(dir)
+			Expect(ioutil.WriteFile(filepath.Join(dir, "PROJECT"), []byte(`domain: example.com
+layout:
+- plugin/v1
+version: "3"
+`), 0600)).To(Succeed())
+
+			c, err = New(WithFilesystem(afero.NewBasePathFs(afero.NewOsFs(), dir)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).NotTo(BeNil())
+			Expect(c.pluginKeys).To(Equal([]string{"plugin/v1"}))
+			Expect(c.projectVersion).To(Equal(config.Version{Number: 3}))
+		})
+
+		It("should return an error for a nil filesystem", func() {
+			_, err = newCLI(WithFilesystem(nil))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("WithWorkingDir", func() {
+		It("should store the absolute path of the working directory", func() {
+			c, err = newCLI(WithWorkingDir(".."))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).NotTo(BeNil())
+			wd, _ := os.Getwd()
+			Expect(c.workingDir).To(Equal(filepath.Dir(wd)))
+		})
+
+		It("should return an error for a working directory that does not exist", func() {
+			_, err = newCLI(WithWorkingDir("nonexistent"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("WithEnv", func() {
+		It("should merge the provided environment variables", func() {
+			c, err = newCLI(WithEnv(map[string]string{"A": "1", "B": "2"}), WithEnv(map[string]string{"B": "3"}))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).NotTo(BeNil())
+			Expect(c.env).To(Equal(map[string]string{"A": "1", "B": "3"}))
+		})
+	})
+
+	Context("WithRunner", func() {
+		It("should use the provided runner", func() {
+			r := util.ExecRunner{Dir: "/tmp"}
+			c, err = newCLI(WithRunner(r))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c).NotTo(BeNil())
+			Expect(c.runner).To(Equal(r))
+		})
+
+		It("should return an error for a nil runner", func() {
+			_, err = newCLI(WithRunner(nil))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("discoverExternalPlugins", func() {
+		const pluginsRoot = "/plugins"
+
+		var (
+			ctx                 context.Context
+			runner              *metadataRunner
+			fs                  afero.Fs
+			stderr              *bytes.Buffer
+			originalGetRoot     func(func(string) string) (string, error)
+			writeExternalPlugin = func(name, version, file string, perm os.FileMode) {
+				dir := filepath.Join(pluginsRoot, name, version)
+				Expect(fs.MkdirAll(dir, 0700)).To(Succeed())
+				Expect(afero.WriteFile(fs, filepath.Join(dir, file), []byte("#!/bin/sh"), perm)).To(Succeed())
+			}
+		)
+
+		BeforeEach(func() {
+			runner = &metadataRunner{versions: map[string][]string{}}
+			ctx = util.WithEnvironment(context.Background(), &util.Environment{Runner: runner})
+			fs = afero.NewMemMapFs()
+			stderr = new(bytes.Buffer)
+			originalGetRoot = getPluginsRoot
+			getPluginsRoot = func(func(string) string) (string, error) { return pluginsRoot, nil }
+		})
+
+		AfterEach(func() {
+			getPluginsRoot = originalGetRoot
+		})
+
+		It("should not fail if the plugins root does not exist", func() {
+			plugins, err := discoverExternalPlugins(ctx, fs, os.Args, os.Getenv, stderr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plugins).To(BeEmpty())
+		})
+
+		It("should find executables named after the plugin name or short name", func() {
+			writeExternalPlugin("myplugin.example.com", "v1", "myplugin.example.com", 0700)
+			writeExternalPlugin("other.example.com", "v2-alpha", "other", 0700)
+			writeExternalPlugin("ignored.example.com", "v1", "another-file", 0700)
+
+			plugins, err := discoverExternalPlugins(ctx, fs, os.Args, os.Getenv, stderr)
+			Expect(err).NotTo(HaveOccurred())
+			keys := make([]string, 0, len(plugins))
+			for _, p := range plugins {
+				Expect(plugin.Validate(p)).To(Succeed())
+				keys = append(keys, plugin.KeyFor(p))
+			}
+			Expect(keys).To(ConsistOf("myplugin.example.com/v1", "other.example.com/v2-alpha"))
+		})
+
+		It("should skip the plugin with a warning if the plugin file is not executable", func() {
+			writeExternalPlugin("myplugin.example.com", "v1", "myplugin.example.com", 0600)
+			writeExternalPlugin("other.example.com", "v1", "other.example.com", 0700)
+
+			plugins, err := discoverExternalPlugins(ctx, fs, os.Args, os.Getenv, stderr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plugins).To(HaveLen(1))
+			Expect(plugin.KeyFor(plugins[0])).To(Equal("other.example.com/v1"))
+			Expect(stderr.String()).To(ContainSubstring("is not executable"))
+		})
+
+		It("should skip the plugin with a warning if the plugin version is invalid", func() {
+			writeExternalPlugin("myplugin.example.com", "version1", "myplugin.example.com", 0700)
+			writeExternalPlugin("myplugin.example.com", "v1", "myplugin.example.com", 0700)
+
+			plugins, err := discoverExternalPlugins(ctx, fs, os.Args, os.Getenv, stderr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plugins).To(HaveLen(1))
+			Expect(plugin.KeyFor(plugins[0])).To(Equal("myplugin.example.com/v1"))
+			Expect(stderr.String()).To(ContainSubstring(`invalid version "version1"`))
+		})
+
+		It("should get the supported project versions from the plugins", func() {
+			writeExternalPlugin("myplugin.example.com", "v1", "myplugin.example.com", 0700)
+			runner.versions[filepath.Join(pluginsRoot, "myplugin.example.com", "v1", "myplugin.example.com")] =
+				[]string{"2", "3"}
+
+			plugins, err := discoverExternalPlugins(ctx, fs, os.Args, os.Getenv, stderr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plugins).To(HaveLen(1))
+			Expect(plugins[0].SupportedProjectVersions()).To(Equal([]config.Version{{Number: 2}, {Number: 3}}))
+		})
+
+		DescribeTable("should skip the plugin with a warning if its supported project versions are not valid",
+			func(versions []string) {
+				writeExternalPlugin("myplugin.example.com", "v1", "myplugin.example.com", 0700)
+				writeExternalPlugin("other.example.com", "v1", "other.example.com", 0700)
+				runner.versions[filepath.Join(pluginsRoot, "myplugin.example.com", "v1", "myplugin.example.com")] =
+					versions
+
+				plugins, err := discoverExternalPlugins(ctx, fs, os.Args, os.Getenv, stderr)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plugins).To(HaveLen(1))
+				Expect(plugin.KeyFor(plugins[0])).To(Equal("other.example.com/v1"))
+				Expect(stderr.String()).To(ContainSubstring("unable to get the supported project versions"))
+			},
+			Entry("if the plugin does not answer the metadata request", nil),
+			Entry("if the plugin does not support any project version", []string{}),
+			Entry("if the plugin supports an invalid project version", []string{"three"}),
+		)
+	})
+
+	DescribeTable("parseExternalPluginArgs",
+		func(args, expected []string) {
+			Expect(parseExternalPluginArgs(args)).To(Equal(expected))
+		},
+		Entry("for no flags", []string{"kubebuilder", "init"}, []string{}),
+		Entry("for flags", []string{"kubebuilder", "create", "api", "--group", "crew", "-h"},
+			[]string{"--group", "crew", "-h"}),
+	)
 })
+
+// metadataRunner answers the metadata requests sent to the external plugins with the project versions
+// of their path, or with "3" if it is not set. Requests to plugins whose versions are nil fail.
+type metadataRunner struct {
+	versions map[string][]string
+}
+
+func (r *metadataRunner) Run(_ context.Context, cmd util.Command) error {
+	versions, found := r.versions[cmd.Name]
+	if !found {
+		versions = []string{"3"}
+	} else if versions == nil {
+		return errors.New("unknown command")
+	}
+	return json.NewEncoder(cmd.Stdout).Encode(external.PluginResponse{
+		APIVersion:               "v1alpha1",
+		Command:                  "metadata",
+		SupportedProjectVersions: versions,
+	})
+}
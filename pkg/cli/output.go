@@ -0,0 +1,265 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+const (
+	outputFlag = "output"
+
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// Result is the machine-readable result of a subcommand execution.
+// It is printed as JSON with the output flag, and returned by Execute.
+type Result struct {
+	// Plugins are the keys of the resolved plugins.
+	Plugins []string `json:"plugins"`
+	// Resource is the resource the subcommand was executed for, if any.
+	Resource *resource.GVK `json:"resource,omitempty"`
+	// DryRun is true if the changes were not applied to the project.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Files are the files that were created, updated, skipped or deleted.
+	Files []FileResult `json:"files"`
+	// Commands are the commands that were run.
+	Commands []CommandResult `json:"commands"`
+	// Skipped are the plugins whose remaining hooks were skipped.
+	Skipped []SkipResult `json:"skipped,omitempty"`
+	// FilesToReview are the files that need to be reviewed manually.
+	FilesToReview []ReviewResult `json:"filesToReview,omitempty"`
+	// Error is the error that made the subcommand fail, if any.
+	Error string `json:"error,omitempty"`
+	// RolledBack is true if the changes were rolled back because the subcommand failed.
+	RolledBack bool `json:"rolledBack,omitempty"`
+}
+
+// FileResult describes the operation performed on a file.
+type FileResult struct {
+	// Path is the path of the file relative to the project root.
+	Path string `json:"path"`
+	// Operation is one of "created", "updated", "skipped" or "deleted".
+	Operation string `json:"operation"`
+	// IfExistsAction is the behavior that was configured in case the file existed.
+	IfExistsAction string `json:"ifExistsAction,omitempty"`
+}
+
+// CommandResult describes a command that was run.
+type CommandResult struct {
+	// Description is the message that was printed before running the command.
+	Description string `json:"description"`
+	// Command is the executable and arguments of the command.
+	Command []string `json:"command"`
+	// Error is the error returned by the command, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// SkipResult describes why the remaining hooks of a plugin were skipped.
+type SkipResult struct {
+	// Plugin is the key of the plugin whose remaining hooks were skipped.
+	Plugin string `json:"plugin"`
+	// Reason is the reason provided by the plugin.
+	Reason string `json:"reason"`
+}
+
+// ReviewResult describes why a file needs to be reviewed manually.
+type ReviewResult struct {
+	// Path is the path of the file relative to the project root.
+	Path string `json:"path"`
+	// Reason is why the file needs to be reviewed.
+	Reason string `json:"reason"`
+}
+
+// newReport creates a report for the provided plugin keys.
+func newReport(plugins []string, dryRun bool) *Result {
+	return &Result{
+		Plugins:  plugins,
+		DryRun:   dryRun,
+		Files:    make([]FileResult, 0),
+		Commands: make([]CommandResult, 0),
+	}
+}
+
+// addFile records the operation performed on a file by a template.
+// Files are only reported once, so a file that is created and then updated is reported as created.
+func (r *Result) addFile(f machinery.FileReport) {
+	file := FileResult{Path: filepath.Clean(f.Path), Operation: string(f.Operation)}
+	// The behavior in case the file existed is meaningless for deleted files
+	if f.Operation != machinery.FileDeleted {
+		file.IfExistsAction = f.IfExistsAction.String()
+	}
+	r.setFile(file)
+}
+
+// addFileOperation records an operation performed on a file through the filesystem, e.g. a file removed
+// by a plugin or written by an external plugin. The behavior in case the file existed is unknown, so it is
+// only set if the file is also reported by a template.
+func (r *Result) addFileOperation(path string, operation machinery.FileOperation) {
+	file := FileResult{Path: filepath.Clean(path), Operation: string(operation)}
+	for _, previous := range r.Files {
+		if previous.Path == file.Path && operation != machinery.FileDeleted {
+			file.IfExistsAction = previous.IfExistsAction
+		}
+	}
+	r.setFile(file)
+}
+
+// setFile records file, replacing the previous record of the same file.
+func (r *Result) setFile(file FileResult) {
+	for i, previous := range r.Files {
+		if previous.Path != file.Path {
+			continue
+		}
+		if previous.Operation == string(machinery.FileCreated) && file.Operation != string(machinery.FileDeleted) {
+			file.Operation = previous.Operation
+		}
+		r.Files[i] = file
+		return
+	}
+	r.Files = append(r.Files, file)
+}
+
+// addCommand records a command that was run.
+func (r *Result) addCommand(msg string, args []string, err error) {
+	command := CommandResult{Description: msg, Command: args}
+	if err != nil {
+		command.Error = err.Error()
+	}
+	r.Commands = append(r.Commands, command)
+}
+
+// addSkip records why the remaining hooks of a plugin were skipped.
+func (r *Result) addSkip(key, reason string) {
+	r.Skipped = append(r.Skipped, SkipResult{Plugin: key, Reason: reason})
+}
+
+// addFileToReview records a file that needs to be reviewed manually.
+func (r *Result) addFileToReview(file plugin.FileToReview) {
+	r.FilesToReview = append(r.FilesToReview, ReviewResult{Path: file.Path, Reason: file.Reason})
+}
+
+// write encodes the report as indented JSON, sorting the files by path.
+func (r Result) write(w io.Writer) error {
+	sort.SliceStable(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// reportingFs is a filesystem that records the files written and removed through it in a report,
+// so that the changes made without templates are also reported.
+type reportingFs struct {
+	afero.Fs
+
+	report *Result
+}
+
+// record records the operation performed on a file, unless it belongs to the scaffolding state,
+// which is not part of the project.
+func (fs reportingFs) record(name string, operation machinery.FileOperation) {
+	if isStatePath(name) {
+		return
+	}
+	fs.report.addFileOperation(name, operation)
+}
+
+// recordWrite records that a file is going to be written, as created or updated depending on whether it exists.
+func (fs reportingFs) recordWrite(name string) {
+	switch info, err := fs.Fs.Stat(name); {
+	case err == nil && info.IsDir():
+	case err == nil:
+		fs.record(name, machinery.FileUpdated)
+	case os.IsNotExist(err):
+		fs.record(name, machinery.FileCreated)
+	}
+}
+
+// recordRemoval records every file under the provided path as deleted.
+func (fs reportingFs) recordRemoval(name string) {
+	_ = afero.Walk(fs.Fs, name, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			fs.record(path, machinery.FileDeleted)
+		}
+		return nil
+	})
+}
+
+// Create implements afero.Fs
+func (fs reportingFs) Create(name string) (afero.File, error) {
+	fs.recordWrite(name)
+	return fs.Fs.Create(name)
+}
+
+// OpenFile implements afero.Fs
+func (fs reportingFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		fs.recordWrite(name)
+	}
+	return fs.Fs.OpenFile(name, flag, perm)
+}
+
+// Remove implements afero.Fs
+func (fs reportingFs) Remove(name string) error {
+	if info, err := fs.Fs.Stat(name); err == nil && !info.IsDir() {
+		fs.record(name, machinery.FileDeleted)
+	}
+	return fs.Fs.Remove(name)
+}
+
+// RemoveAll implements afero.Fs
+func (fs reportingFs) RemoveAll(path string) error {
+	fs.recordRemoval(path)
+	return fs.Fs.RemoveAll(path)
+}
+
+// Rename implements afero.Fs
+func (fs reportingFs) Rename(oldname, newname string) error {
+	_ = afero.Walk(fs.Fs, oldname, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(oldname, path)
+		if err != nil {
+			return nil
+		}
+		fs.record(path, machinery.FileDeleted)
+		fs.recordWrite(filepath.Join(newname, rel))
+		return nil
+	})
+	return fs.Fs.Rename(oldname, newname)
+}
+
+// isStatePath checks if path belongs to the scaffolding state directory, which is not part of the project.
+func isStatePath(path string) bool {
+	path = filepath.Clean(path)
+	return path == machinery.StateDirectory ||
+		strings.HasPrefix(path, machinery.StateDirectory+string(filepath.Separator))
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/spf13/afero"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+)
+
+var _ = Describe("report", func() {
+	var r *Result
+
+	BeforeEach(func() {
+		r = newReport([]string{"go.kubebuilder.io/v3"}, false)
+	})
+
+	It("should report each file once", func() {
+		r.addFile(machinery.FileReport{Path: "main.go", Operation: machinery.FileCreated,
+			IfExistsAction: machinery.Error})
+		r.addFile(machinery.FileReport{Path: "main.go", Operation: machinery.FileUpdated,
+			IfExistsAction: machinery.OverwriteFile})
+		r.addFile(machinery.FileReport{Path: "PROJECT", Operation: machinery.FileUpdated,
+			IfExistsAction: machinery.OverwriteFile})
+		r.addFile(machinery.FileReport{Path: "old.go", Operation: machinery.FileDeleted})
+
+		Expect(r.Files).To(Equal([]FileResult{
+			{Path: "main.go", Operation: "created", IfExistsAction: "overwrite"},
+			{Path: "PROJECT", Operation: "updated", IfExistsAction: "overwrite"},
+			{Path: "old.go", Operation: "deleted"},
+		}))
+	})
+
+	It("should report the files written and removed through the filesystem", func() {
+		base := afero.NewMemMapFs()
+		Expect(afero.WriteFile(base, "main.go", []byte("package main"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(base, "old.go", []byte("package main"), 0600)).To(Succeed())
+		fs := reportingFs{Fs: base, report: r}
+
+		Expect(afero.WriteFile(fs, "main.go", []byte("package main\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs, "new.go", []byte("package main"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs, ".kubebuilder/pristine/new.go", []byte("package main"), 0600)).To(Succeed())
+		Expect(fs.Remove("old.go")).To(Succeed())
+		r.addFile(machinery.FileReport{Path: "new.go", Operation: machinery.FileCreated,
+			IfExistsAction: machinery.Error})
+
+		Expect(r.Files).To(Equal([]FileResult{
+			{Path: "main.go", Operation: "updated"},
+			{Path: "new.go", Operation: "created", IfExistsAction: "error"},
+			{Path: "old.go", Operation: "deleted"},
+		}))
+	})
+
+	It("should write the report as JSON", func() {
+		r.Resource = &resource.GVK{Group: "crew", Domain: "example.com", Version: "v1", Kind: "Captain"}
+		r.addFile(machinery.FileReport{Path: "main.go", Operation: machinery.FileSkipped})
+		r.addCommand("Update dependencies", []string{"go", "mod", "tidy"}, errors.New("exit status 1"))
+		r.addSkip("go.kubebuilder.io/v3", "nothing to do")
+
+		buf := new(bytes.Buffer)
+		Expect(r.write(buf)).To(Succeed())
+
+		var decoded map[string]interface{}
+		Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(map[string]interface{}{
+			"plugins": []interface{}{"go.kubebuilder.io/v3"},
+			"resource": map[string]interface{}{
+				"group": "crew", "domain": "example.com", "version": "v1", "kind": "Captain",
+			},
+			"files": []interface{}{
+				map[string]interface{}{"path": "main.go", "operation": "skipped", "ifExistsAction": "skip"},
+			},
+			"commands": []interface{}{
+				map[string]interface{}{
+					"description": "Update dependencies",
+					"command":     []interface{}{"go", "mod", "tidy"},
+					"error":       "exit status 1",
+				},
+			},
+			"skipped": []interface{}{
+				map[string]interface{}{"plugin": "go.kubebuilder.io/v3", "reason": "nothing to do"},
+			},
+		}))
+	})
+})
@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/config/store"
+	jsonstore "sigs.k8s.io/kubebuilder/v3/pkg/config/store/json"
+	yamlstore "sigs.k8s.io/kubebuilder/v3/pkg/config/store/yaml"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+)
+
+const (
+	projectFileFlag = "project-file"
+
+	jsonExtension = ".json"
+)
+
+// projectFileNames are the names of the project configuration files searched for, in order of preference.
+var projectFileNames = []string{yamlstore.DefaultPath, jsonstore.DefaultPath}
+
+// projectFileStore is a store.Store that loads and saves the project configuration from a fixed path.
+type projectFileStore struct {
+	store.Store
+
+	path string
+}
+
+// Load implements store.Store
+func (s projectFileStore) Load() error {
+	return s.LoadFrom(s.path)
+}
+
+// Save implements store.Store
+func (s projectFileStore) Save() error {
+	return s.SaveTo(s.path)
+}
+
+// newStore returns the store.Store for the project configuration file at path, whose format is selected by its
+// extension: JSON for ".json" files, and YAML otherwise.
+func newStore(fs machinery.Filesystem, path string) store.Store {
+	if filepath.Ext(path) == jsonExtension {
+		return projectFileStore{Store: jsonstore.New(fs), path: path}
+	}
+	return projectFileStore{Store: yamlstore.New(fs), path: path}
+}
+
+// marshalConfig returns the content of the project configuration file at path for the provided configuration.
+func marshalConfig(cfg config.Config, path string) ([]byte, error) {
+	if filepath.Ext(path) == jsonExtension {
+		return jsonstore.Marshal(cfg)
+	}
+	return cfg.MarshalYAML()
+}
+
+// projectFileName returns the path of the project configuration file, relative to the working directory.
+func (c CLI) projectFileName() string {
+	if c.projectFile == "" {
+		return yamlstore.DefaultPath
+	}
+	return c.projectFile
+}
+
+// resolveProjectFile obtains the project configuration file from the project file flag or, if not provided, by
+// searching the current directory and then its parents, except for the init subcommand. If the file is found in
+// another directory, it becomes the working directory the CLI is run from.
+func (c *CLI) resolveProjectFile() error {
+	// Partially parse the command line arguments
+	fs := pflag.NewFlagSet("project-file", pflag.ContinueOnError)
+	// The global flags are copied with their own values, as parsing them twice would append to slice flags,
+	// e.g. --plugins, while they still have to be known in order to find the positional arguments
+	c.cmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Value.Type() == "bool" {
+			fs.BoolP(flag.Name, flag.Shorthand, false, flag.Usage)
+		} else {
+			fs.StringP(flag.Name, flag.Shorthand, "", flag.Usage)
+		}
+	})
+	fs.BoolP("help", "h", false, "")
+	fs.ParseErrorsWhitelist = pflag.ParseErrorsWhitelist{UnknownFlags: true}
+	if err := fs.Parse(c.arguments()); err != nil {
+		return err
+	}
+
+	projectFile, err := fs.GetString(projectFileFlag)
+	if err != nil {
+		return err
+	}
+	if projectFile != "" {
+		dir, name := filepath.Split(projectFile)
+		if name == "" {
+			return fmt.Errorf("invalid project file %q, it must be a file", projectFile)
+		}
+		c.projectFile = name
+		if dir == "" {
+			return nil
+		}
+		absDir, err := c.absPath(dir)
+		if err != nil {
+			return fmt.Errorf("invalid project file directory %q: %w", dir, err)
+		}
+		c.workingDir = absDir
+		return nil
+	}
+
+	// The project configuration file in the current directory takes precedence
+	for _, name := range projectFileNames {
+		if exists, err := afero.Exists(c.filesystem().FS, name); err != nil {
+			return err
+		} else if exists {
+			c.projectFile = name
+			return nil
+		}
+	}
+
+	// New projects are initialized in the current directory, even if it is part of another project
+	if args := fs.Args(); len(args) != 0 && args[0] == "init" {
+		return nil
+	}
+
+	wd, err := c.getwd()
+	if err != nil {
+		return fmt.Errorf("unable to get the working directory: %w", err)
+	}
+	if path, found := findProjectFile(c.fs.FS, filepath.Dir(wd)); found {
+		c.workingDir, c.projectFile = filepath.Split(path)
+		c.workingDir = filepath.Clean(c.workingDir)
+	}
+	return nil
+}
+
+// findProjectFile searches for a project configuration file in dir and its parents,
+// returning its absolute path and whether it was found.
+func findProjectFile(fs afero.Fs, dir string) (string, bool) {
+	for {
+		for _, name := range projectFileNames {
+			path := filepath.Join(dir, name)
+			if info, err := fs.Stat(path); err == nil && !info.IsDir() {
+				return path, true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	jsonstore "sigs.k8s.io/kubebuilder/v3/pkg/config/store/json"
+	yamlstore "sigs.k8s.io/kubebuilder/v3/pkg/config/store/yaml"
+	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+)
+
+var _ = Describe("newStore", func() {
+	var fs machinery.Filesystem
+
+	BeforeEach(func() {
+		fs = machinery.Filesystem{FS: afero.NewMemMapFs()}
+	})
+
+	DescribeTable("should save the project configuration in the format of the file extension",
+		func(path, content string) {
+			s := newStore(fs, path)
+			Expect(s.New(cfgv3.Version)).To(Succeed())
+			Expect(s.Save()).To(Succeed())
+
+			saved, err := afero.ReadFile(fs.FS, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(saved)).To(Equal(content))
+
+			Expect(newStore(fs, path).Load()).To(Succeed())
+		},
+		Entry("for YAML files", "PROJECT", "version: \"3\"\n"),
+		Entry("for other YAML files", "config/kubebuilder.yaml", "version: \"3\"\n"),
+		Entry("for JSON files", "config/PROJECT.json", "{\n  \"version\": \"3\"\n}\n"),
+	)
+})
+
+var _ = Describe("findProjectFile", func() {
+	var (
+		fs   afero.Fs
+		root = filepath.Join(string(filepath.Separator), "repo")
+	)
+
+	BeforeEach(func() {
+		fs = afero.NewMemMapFs()
+		Expect(fs.MkdirAll(filepath.Join(root, "operators", "foo", "api"), 0755)).To(Succeed())
+	})
+
+	It("should find the project configuration file in the closest parent", func() {
+		Expect(afero.WriteFile(fs, filepath.Join(root, yamlstore.DefaultPath), []byte{}, 0644)).To(Succeed())
+		Expect(afero.WriteFile(fs, filepath.Join(root, "operators", "foo", jsonstore.DefaultPath), []byte{}, 0644)).
+			To(Succeed())
+
+		path, found := findProjectFile(fs, filepath.Join(root, "operators", "foo", "api"))
+		Expect(found).To(BeTrue())
+		Expect(path).To(Equal(filepath.Join(root, "operators", "foo", jsonstore.DefaultPath)))
+	})
+
+	It("should ignore directories named as project configuration files", func() {
+		Expect(fs.MkdirAll(filepath.Join(root, "operators", yamlstore.DefaultPath), 0755)).To(Succeed())
+
+		_, found := findProjectFile(fs, filepath.Join(root, "operators", "foo"))
+		Expect(found).To(BeFalse())
+	})
+
+	It("should not find anything if there is no project configuration file", func() {
+		_, found := findProjectFile(fs, filepath.Join(root, "operators", "foo", "api"))
+		Expect(found).To(BeFalse())
+	})
+})
+
+var _ = Describe("resolveProjectFile", func() {
+	var c *CLI
+
+	BeforeEach(func() {
+		c = &CLI{fs: machinery.Filesystem{FS: afero.NewMemMapFs()}}
+		c.cmd = c.newRootCmd()
+	})
+
+	It("should use the project file flag", func() {
+		c.args = []string{"create", "api", "--" + projectFileFlag, "kubebuilder.json"}
+		Expect(c.resolveProjectFile()).To(Succeed())
+		Expect(c.projectFileName()).To(Equal("kubebuilder.json"))
+		Expect(c.workingDir).To(BeEmpty())
+	})
+
+	It("should use the directory of the project file flag as working directory", func() {
+		c.args = []string{"create", "api", "--" + projectFileFlag, filepath.Join("operators", "foo", "PROJECT")}
+		Expect(c.resolveProjectFile()).To(Succeed())
+		Expect(c.projectFileName()).To(Equal("PROJECT"))
+
+		wd, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.workingDir).To(Equal(filepath.Join(wd, "operators", "foo")))
+	})
+
+	It("should not set the global flags", func() {
+		c.args = []string{"init", "--" + pluginsFlag, "go/v3"}
+		Expect(c.resolveProjectFile()).To(Succeed())
+		Expect(c.cmd.PersistentFlags().GetStringSlice(pluginsFlag)).To(BeEmpty())
+	})
+
+	It("should fail if the project file flag is a directory", func() {
+		c.args = []string{"--" + projectFileFlag, "operators" + string(filepath.Separator)}
+		Expect(c.resolveProjectFile()).NotTo(Succeed())
+	})
+
+	It("should find the project configuration file in the current directory", func() {
+		Expect(afero.WriteFile(c.fs.FS, jsonstore.DefaultPath, []byte{}, 0644)).To(Succeed())
+
+		c.args = []string{"create", "api"}
+		Expect(c.resolveProjectFile()).To(Succeed())
+		Expect(c.projectFileName()).To(Equal(jsonstore.DefaultPath))
+		Expect(c.workingDir).To(BeEmpty())
+	})
+
+	It("should search the parent directories except to initialize projects", func() {
+		wd, err := os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(afero.WriteFile(c.fs.FS, filepath.Join(filepath.Dir(wd), yamlstore.DefaultPath), []byte{}, 0644)).
+			To(Succeed())
+
+		c.args = []string{"init"}
+		Expect(c.resolveProjectFile()).To(Succeed())
+		Expect(c.projectFileName()).To(Equal(yamlstore.DefaultPath))
+		Expect(c.workingDir).To(BeEmpty())
+
+		c.args = []string{"create", "api"}
+		Expect(c.resolveProjectFile()).To(Succeed())
+		Expect(c.projectFileName()).To(Equal(yamlstore.DefaultPath))
+		Expect(c.workingDir).To(Equal(filepath.Dir(wd)))
+	})
+})
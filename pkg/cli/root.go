@@ -41,6 +41,14 @@ func (c CLI) newRootCmd() *cobra.Command {
 
 	// Global flags for all subcommands.
 	cmd.PersistentFlags().StringSlice(pluginsFlag, nil, "plugin keys to be used for this subcommand execution")
+	cmd.PersistentFlags().Bool(dryRunFlag, false,
+		"run the subcommand without modifying the project and print a diff of the changes it would make")
+	cmd.PersistentFlags().Bool(nonInteractiveFlag, false,
+		"fail instead of prompting for input, questions need to be answered through their flags")
+	cmd.PersistentFlags().Bool(yesFlag, false, "answer yes to every confirmation prompt")
+	cmd.PersistentFlags().String(projectFileFlag, "",
+		"path of the project configuration file, searched for in the current directory and its parents by default; "+
+			"its directory is used as the project directory and \".json\" files are stored as JSON")
 
 	// Register --project-version on the root command so that it shows up in help.
 	cmd.Flags().String(projectVersionFlag, c.defaultProjectVersion.String(), "project version")
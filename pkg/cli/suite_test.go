@@ -17,12 +17,19 @@ limitations under the License.
 package cli
 
 import (
+	"context"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/pflag"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
 )
 
@@ -33,8 +40,13 @@ func TestCLI(t *testing.T) {
 
 // Test plugin types and constructors.
 var (
-	_ plugin.Plugin = mockPlugin{}
-	_ plugin.Plugin = mockDeprecatedPlugin{}
+	_ plugin.Plugin   = mockPlugin{}
+	_ plugin.Plugin   = mockDeprecatedPlugin{}
+	_ plugin.Custom   = mockCustomPlugin{}
+	_ plugin.Migrator = mockMigratorPlugin{}
+
+	_ plugin.HasConfigSchema    = mockConfigSchemaPlugin{}
+	_ plugin.HasScaffoldedFiles = mockScaffoldedFilesPlugin{}
 )
 
 type mockPlugin struct { //nolint:maligned
@@ -68,3 +80,120 @@ func newMockDeprecatedPlugin(name, version, deprecation string, projVers ...conf
 }
 
 func (p mockDeprecatedPlugin) DeprecationWarning() string { return p.deprecation }
+
+type mockCustomPlugin struct {
+	mockPlugin
+	subcommands []plugin.CustomSubcommand
+}
+
+func newMockCustomPlugin(name, version string, subcommands []plugin.CustomSubcommand,
+	projVers ...config.Version) plugin.Plugin {
+	return mockCustomPlugin{
+		mockPlugin:  newMockPlugin(name, version, projVers...).(mockPlugin),
+		subcommands: subcommands,
+	}
+}
+
+func (p mockCustomPlugin) GetCustomSubcommands() []plugin.CustomSubcommand { return p.subcommands }
+
+type mockMigratorPlugin struct {
+	mockPlugin
+	previousKey string
+	subcommand  plugin.MigrateSubcommand
+}
+
+func newMockMigratorPlugin(name, version, previousKey string, subcommand plugin.MigrateSubcommand,
+	projVers ...config.Version) plugin.Plugin {
+	return mockMigratorPlugin{
+		mockPlugin:  newMockPlugin(name, version, projVers...).(mockPlugin),
+		previousKey: previousKey,
+		subcommand:  subcommand,
+	}
+}
+
+func (p mockMigratorPlugin) MigrateFrom(previousKey string) plugin.MigrateSubcommand {
+	if previousKey != p.previousKey {
+		return nil
+	}
+	return p.subcommand
+}
+
+type mockConfigSchemaPlugin struct {
+	mockPlugin
+	schema config.Schema
+}
+
+func newMockConfigSchemaPlugin(name, version string, schema config.Schema, projVers ...config.Version) plugin.Plugin {
+	return mockConfigSchemaPlugin{
+		mockPlugin: newMockPlugin(name, version, projVers...).(mockPlugin),
+		schema:     schema,
+	}
+}
+
+func (p mockConfigSchemaPlugin) ConfigSchema() config.Schema { return p.schema }
+
+// mockScaffoldedFilesPlugin scaffolds a controller for every resource with a controller.
+type mockScaffoldedFilesPlugin struct {
+	mockPlugin
+}
+
+func (mockScaffoldedFilesPlugin) ScaffoldedFiles(res resource.Resource, _ bool) []string {
+	if !res.HasController() {
+		return nil
+	}
+	return []string{filepath.Join("controllers", strings.ToLower(res.Kind)+"_controller.go")}
+}
+
+// mockSubcommand records the hooks that were called and the values that were injected.
+type mockSubcommand struct {
+	flag   string
+	config config.Config
+	hooks  []string
+}
+
+func (s *mockSubcommand) BindFlags(fs *pflag.FlagSet) { fs.StringVar(&s.flag, "flag", "", "") }
+
+func (s *mockSubcommand) InjectConfig(c config.Config) error {
+	s.config = c
+	s.hooks = append(s.hooks, "InjectConfig")
+	return nil
+}
+
+func (s *mockSubcommand) PreScaffold(machinery.Filesystem) error {
+	s.hooks = append(s.hooks, "PreScaffold")
+	return nil
+}
+
+func (s *mockSubcommand) Scaffold(machinery.Filesystem) error {
+	s.hooks = append(s.hooks, "Scaffold")
+	return nil
+}
+
+func (s *mockSubcommand) PostScaffold() error {
+	s.hooks = append(s.hooks, "PostScaffold")
+	return nil
+}
+
+// mockContextSubcommand records the context-aware hooks that were called, calling onScaffold when scaffolding.
+type mockContextSubcommand struct {
+	mockSubcommand
+	onScaffold func()
+}
+
+func (s *mockContextSubcommand) PreScaffoldWithContext(context.Context, machinery.Filesystem) error {
+	s.hooks = append(s.hooks, "PreScaffoldWithContext")
+	return nil
+}
+
+func (s *mockContextSubcommand) ScaffoldWithContext(context.Context, machinery.Filesystem) error {
+	s.hooks = append(s.hooks, "ScaffoldWithContext")
+	if s.onScaffold != nil {
+		s.onScaffold()
+	}
+	return nil
+}
+
+func (s *mockContextSubcommand) PostScaffoldWithContext(context.Context) error {
+	s.hooks = append(s.hooks, "PostScaffoldWithContext")
+	return nil
+}
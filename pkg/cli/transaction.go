@@ -0,0 +1,265 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+)
+
+// transaction journals the changes made through a filesystem so that they can be rolled back.
+// Files are only journaled before their first modification, so rolling back restores the state they had
+// when the transaction started. Changes made by external commands, e.g. `go mod tidy`, are not journaled.
+type transaction struct {
+	// fs is the underlying filesystem.
+	fs afero.Fs
+	// files are the original states of the files modified during the transaction by their cleaned path.
+	files map[string]originalFile
+	// dirs are the directories created during the transaction, parents first.
+	dirs []string
+}
+
+// originalFile is the state of a file before it was modified.
+type originalFile struct {
+	// exists is false if the file was created during the transaction.
+	exists  bool
+	content []byte
+	mode    os.FileMode
+}
+
+// newTransaction starts a transaction on top of fs.
+func newTransaction(fs machinery.Filesystem) *transaction {
+	return &transaction{
+		fs:    fs.FS,
+		files: make(map[string]originalFile),
+	}
+}
+
+// filesystem returns the filesystem that should be used for scaffolding in order to journal the changes.
+func (t *transaction) filesystem(fs machinery.Filesystem) machinery.Filesystem {
+	return machinery.Filesystem{
+		FS:          transactionFs{Fs: t.fs, transaction: t},
+		Reporter:    fs.Reporter,
+		Environment: fs.Environment,
+	}
+}
+
+// journalFile records the original state of a file if it was not already recorded.
+func (t *transaction) journalFile(name string) error {
+	name = filepath.Clean(name)
+	if _, journaled := t.files[name]; journaled {
+		return nil
+	}
+
+	info, err := t.fs.Stat(name)
+	switch {
+	case os.IsNotExist(err):
+		t.files[name] = originalFile{}
+		return nil
+	case err != nil:
+		return err
+	case info.IsDir():
+		// Directories are journaled by journalDirs and their contents by journalTree
+		return nil
+	}
+
+	content, err := afero.ReadFile(t.fs, name)
+	if err != nil {
+		return err
+	}
+	t.files[name] = originalFile{exists: true, content: content, mode: info.Mode()}
+	return nil
+}
+
+// journalTree records the original state of every file under the provided path.
+func (t *transaction) journalTree(name string) error {
+	return afero.Walk(t.fs, name, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		return t.journalFile(path)
+	})
+}
+
+// journalDirs records the provided directory and its parents that do not exist yet.
+func (t *transaction) journalDirs(name string) error {
+	missing := make([]string, 0)
+	for dir := filepath.Clean(name); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		exists, err := afero.DirExists(t.fs, dir)
+		if err != nil {
+			return err
+		}
+		if exists {
+			break
+		}
+		missing = append(missing, dir)
+	}
+
+	for i := len(missing) - 1; i >= 0; i-- {
+		t.dirs = append(t.dirs, missing[i])
+	}
+	return nil
+}
+
+// empty checks if no change has been journaled.
+func (t *transaction) empty() bool {
+	return len(t.files) == 0 && len(t.dirs) == 0
+}
+
+// commit discards the journal, keeping the changes.
+func (t *transaction) commit() {
+	t.files = make(map[string]originalFile)
+	t.dirs = nil
+}
+
+// rollback restores the original state of every journaled file and removes the directories created
+// during the transaction, unless they contain files that were not journaled.
+func (t *transaction) rollback() error {
+	paths := make([]string, 0, len(t.files))
+	for path := range t.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		original := t.files[path]
+		if !original.exists {
+			if err := t.fs.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("unable to remove %q: %w", path, err)
+			}
+			continue
+		}
+
+		if err := t.fs.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("unable to restore %q: %w", path, err)
+		}
+		if err := afero.WriteFile(t.fs, path, original.content, original.mode); err != nil {
+			return fmt.Errorf("unable to restore %q: %w", path, err)
+		}
+	}
+
+	for i := len(t.dirs) - 1; i >= 0; i-- {
+		// Directories that are not empty contain files created by other means, which are kept
+		if isDir, _ := afero.IsDir(t.fs, t.dirs[i]); !isDir {
+			continue
+		}
+		if empty, err := afero.IsEmpty(t.fs, t.dirs[i]); err == nil && empty {
+			if err := t.fs.Remove(t.dirs[i]); err != nil {
+				return fmt.Errorf("unable to remove %q: %w", t.dirs[i], err)
+			}
+		}
+	}
+
+	t.commit()
+	return nil
+}
+
+// transactionFs is the filesystem of a transaction, which journals files before modifying them.
+type transactionFs struct {
+	afero.Fs
+
+	transaction *transaction
+}
+
+// Create implements afero.Fs
+func (fs transactionFs) Create(name string) (afero.File, error) {
+	if err := fs.transaction.journalFile(name); err != nil {
+		return nil, err
+	}
+	return fs.Fs.Create(name)
+}
+
+// Mkdir implements afero.Fs
+func (fs transactionFs) Mkdir(name string, perm os.FileMode) error {
+	if err := fs.transaction.journalDirs(name); err != nil {
+		return err
+	}
+	return fs.Fs.Mkdir(name, perm)
+}
+
+// MkdirAll implements afero.Fs
+func (fs transactionFs) MkdirAll(path string, perm os.FileMode) error {
+	if err := fs.transaction.journalDirs(path); err != nil {
+		return err
+	}
+	return fs.Fs.MkdirAll(path, perm)
+}
+
+// OpenFile implements afero.Fs
+func (fs transactionFs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		if err := fs.transaction.journalFile(name); err != nil {
+			return nil, err
+		}
+	}
+	return fs.Fs.OpenFile(name, flag, perm)
+}
+
+// Remove implements afero.Fs
+func (fs transactionFs) Remove(name string) error {
+	if err := fs.transaction.journalFile(name); err != nil {
+		return err
+	}
+	return fs.Fs.Remove(name)
+}
+
+// RemoveAll implements afero.Fs
+func (fs transactionFs) RemoveAll(path string) error {
+	if err := fs.transaction.journalTree(path); err != nil {
+		return err
+	}
+	return fs.Fs.RemoveAll(path)
+}
+
+// Rename implements afero.Fs
+func (fs transactionFs) Rename(oldname, newname string) error {
+	// Every file that is moved is journaled both in its original and in its new location
+	if err := afero.Walk(fs.Fs, oldname, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(oldname, path)
+		if err != nil {
+			return err
+		}
+		if err := fs.transaction.journalFile(path); err != nil {
+			return err
+		}
+		return fs.transaction.journalFile(filepath.Join(newname, rel))
+	}); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	dir := filepath.Dir(newname)
+	if isDir, _ := afero.IsDir(fs.Fs, oldname); isDir {
+		dir = newname
+	}
+	if err := fs.transaction.journalDirs(dir); err != nil {
+		return err
+	}
+	return fs.Fs.Rename(oldname, newname)
+}
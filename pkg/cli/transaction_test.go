@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+)
+
+var _ = Describe("transaction", func() {
+	var (
+		base afero.Fs
+		tx   *transaction
+		fs   machinery.Filesystem
+	)
+
+	BeforeEach(func() {
+		base = afero.NewMemMapFs()
+		Expect(afero.WriteFile(base, "PROJECT", []byte("domain: example.com\n"), 0600)).To(Succeed())
+		Expect(base.MkdirAll("config/crd", 0700)).To(Succeed())
+		Expect(afero.WriteFile(base, "config/crd/kustomization.yaml", []byte("resources: []\n"), 0644)).To(Succeed())
+
+		tx = newTransaction(machinery.Filesystem{FS: base})
+		fs = tx.filesystem(machinery.Filesystem{FS: base})
+	})
+
+	expectContent := func(path, content string) {
+		actual, err := afero.ReadFile(base, path)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, string(actual)).To(Equal(content))
+	}
+	expectMissing := func(path string) {
+		exists, err := afero.Exists(base, path)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, exists).To(BeFalse())
+	}
+
+	It("should apply the changes to the underlying filesystem", func() {
+		Expect(afero.WriteFile(fs.FS, "PROJECT", []byte("domain: other.com\n"), 0600)).To(Succeed())
+
+		expectContent("PROJECT", "domain: other.com\n")
+		Expect(tx.empty()).To(BeFalse())
+	})
+
+	It("should not journal reads", func() {
+		Expect(afero.ReadFile(fs.FS, "PROJECT")).To(Equal([]byte("domain: example.com\n")))
+
+		Expect(tx.empty()).To(BeTrue())
+	})
+
+	It("should restore modified and removed files", func() {
+		Expect(afero.WriteFile(fs.FS, "PROJECT", []byte("domain: other.com\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs.FS, "PROJECT", []byte("domain: another.com\n"), 0600)).To(Succeed())
+		Expect(fs.FS.RemoveAll("config")).To(Succeed())
+
+		Expect(tx.rollback()).To(Succeed())
+
+		expectContent("PROJECT", "domain: example.com\n")
+		expectContent("config/crd/kustomization.yaml", "resources: []\n")
+		info, err := base.Stat("config/crd/kustomization.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode()).To(Equal(os.FileMode(0644)))
+	})
+
+	It("should remove created files and directories", func() {
+		Expect(fs.FS.MkdirAll("api/v1", 0700)).To(Succeed())
+		Expect(afero.WriteFile(fs.FS, "api/v1/types.go", []byte("package v1\n"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs.FS, "config/crd/patch.yaml", []byte("{}\n"), 0600)).To(Succeed())
+
+		Expect(tx.rollback()).To(Succeed())
+
+		expectMissing("api")
+		expectMissing("config/crd/patch.yaml")
+		expectContent("config/crd/kustomization.yaml", "resources: []\n")
+	})
+
+	It("should keep created directories that contain files that were not journaled", func() {
+		Expect(fs.FS.MkdirAll("bin", 0700)).To(Succeed())
+		Expect(afero.WriteFile(base, "bin/manager", []byte("binary"), 0600)).To(Succeed())
+
+		Expect(tx.rollback()).To(Succeed())
+
+		expectContent("bin/manager", "binary")
+	})
+
+	It("should restore renamed files", func() {
+		Expect(fs.FS.Rename("PROJECT", "PROJECT.bak")).To(Succeed())
+		Expect(fs.FS.MkdirAll("config/bases", 0700)).To(Succeed())
+		Expect(fs.FS.Rename("config/crd/kustomization.yaml", "config/bases/kustomization.yaml")).To(Succeed())
+
+		Expect(tx.rollback()).To(Succeed())
+
+		expectContent("PROJECT", "domain: example.com\n")
+		expectContent("config/crd/kustomization.yaml", "resources: []\n")
+		expectMissing("PROJECT.bak")
+		expectMissing("config/bases")
+	})
+
+	It("should keep the changes once committed", func() {
+		Expect(afero.WriteFile(fs.FS, "PROJECT", []byte("domain: other.com\n"), 0600)).To(Succeed())
+
+		tx.commit()
+		Expect(tx.empty()).To(BeTrue())
+		Expect(tx.rollback()).To(Succeed())
+
+		expectContent("PROJECT", "domain: other.com\n")
+	})
+})
@@ -28,8 +28,8 @@ func (c CLI) newVersionCmd() *cobra.Command {
 		Short:   fmt.Sprintf("Print the %s version", c.commandName),
 		Long:    fmt.Sprintf("Print the %s version", c.commandName),
 		Example: fmt.Sprintf("%s version", c.commandName),
-		RunE: func(_ *cobra.Command, _ []string) error {
-			fmt.Println(c.version)
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			fmt.Fprintln(cmd.OutOrStdout(), c.version)
 			return nil
 		},
 	}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Convert copies the fields of the src project configuration into dst, which may be of a different version.
+// Fields that dst does not support are only an error if they are set in src. The plugin chain is not copied,
+// as it needs to be set to the plugins the project is converted for, and the plugin configurations are only
+// copied for the provided plugin keys, as they can not be listed.
+func Convert(src, dst Config, pluginKeys ...string) error {
+	if domain := src.GetDomain(); domain != "" {
+		if err := dst.SetDomain(domain); err != nil {
+			return fmt.Errorf("unable to convert the domain: %w", err)
+		}
+	}
+	if repository := src.GetRepository(); repository != "" {
+		if err := dst.SetRepository(repository); err != nil {
+			return fmt.Errorf("unable to convert the repository: %w", err)
+		}
+	}
+	if projectName := src.GetProjectName(); projectName != "" {
+		if err := dst.SetProjectName(projectName); err != nil {
+			return fmt.Errorf("unable to convert the project name: %w", err)
+		}
+	}
+
+	if src.IsMultiGroup() {
+		if err := dst.SetMultiGroup(); err != nil {
+			return fmt.Errorf("unable to convert the multi-group layout: %w", err)
+		}
+	}
+	if src.IsComponentConfig() {
+		if err := dst.SetComponentConfig(); err != nil {
+			return fmt.Errorf("unable to convert the component config: %w", err)
+		}
+	}
+
+	resources, err := src.GetResources()
+	if err != nil {
+		return fmt.Errorf("unable to convert the resources: %w", err)
+	}
+	for _, res := range resources {
+		if err := dst.UpdateResource(res); err != nil {
+			return fmt.Errorf("unable to convert resource %v: %w", res.GVK, err)
+		}
+	}
+
+	for _, key := range pluginKeys {
+		var pluginConfig map[string]interface{}
+		err := src.DecodePluginConfig(key, &pluginConfig)
+		switch {
+		case err == nil:
+		case errors.As(err, &PluginKeyNotFoundError{}), errors.As(err, &UnsupportedFieldError{}):
+			continue
+		default:
+			return fmt.Errorf("unable to convert the configuration of plugin %q: %w", key, err)
+		}
+
+		if err := dst.EncodePluginConfig(key, pluginConfig); err != nil {
+			return fmt.Errorf("unable to convert the configuration of plugin %q: %w", key, err)
+		}
+	}
+
+	return nil
+}
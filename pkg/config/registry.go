@@ -16,10 +16,22 @@ limitations under the License.
 
 package config
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 var (
-	registry = make(map[Version]func() Config)
+	registry   = make(map[Version]func() Config)
+	schemas    = make(map[Version][]byte)
+	converters = make(map[conversion]func(Config) (Config, error))
 )
 
+// conversion identifies a converter by the versions it converts from and to
+type conversion struct {
+	from, to Version
+}
+
 // Register allows implementations of Config to register themselves so that they can be created with New
 func Register(version Version, constructor func() Config) {
 	registry[version] = constructor
@@ -39,3 +51,47 @@ func New(version Version) (Config, error) {
 
 	return nil, UnsupportedVersionError{Version: version}
 }
+
+// RegisterSchema allows implementations of Config to register the JSON Schema of their project configuration files
+// so that they can be validated
+func RegisterSchema(version Version, schema []byte) {
+	schemas[version] = schema
+}
+
+// GetSchema returns a copy of the JSON Schema previously registered for the version through RegisterSchema
+func GetSchema(version Version) (Schema, error) {
+	raw, exists := schemas[version]
+	if !exists {
+		return nil, UnsupportedVersionError{Version: version}
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("invalid schema for version %s: %w", version, err)
+	}
+	return schema, nil
+}
+
+// RegisterConverter allows implementations of Config to register a function that converts project configurations
+// of version from into their own version, for conversions that need more than what Convert offers
+func RegisterConverter(from, to Version, converter func(Config) (Config, error)) {
+	converters[conversion{from: from, to: to}] = converter
+}
+
+// ConvertTo returns a new Config of the provided version with the fields of src. If a converter was registered
+// through RegisterConverter for these versions it is used, otherwise the fields are copied with Convert, which
+// only copies the plugin configurations of the provided plugin keys
+func ConvertTo(src Config, version Version, pluginKeys ...string) (Config, error) {
+	if converter, exists := converters[conversion{from: src.GetVersion(), to: version}]; exists {
+		return converter(src)
+	}
+
+	dst, err := New(version)
+	if err != nil {
+		return nil, err
+	}
+	if err := Convert(src, dst, pluginKeys...); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
@@ -29,6 +29,8 @@ var _ = Describe("registry", func() {
 
 	AfterEach(func() {
 		registry = make(map[Version]func() Config)
+		schemas = make(map[Version][]byte)
+		converters = make(map[conversion]func(Config) (Config, error))
 	})
 
 	Context("Register", func() {
@@ -63,4 +65,72 @@ var _ = Describe("registry", func() {
 		})
 	})
 
+	Context("RegisterSchema", func() {
+		It("should register new schemas", func() {
+			RegisterSchema(version, []byte(`{"type": "object"}`))
+			Expect(schemas).To(HaveKey(version))
+		})
+	})
+
+	Context("GetSchema", func() {
+		It("should return a copy of the registered schemas", func() {
+			RegisterSchema(version, []byte(`{"type": "object"}`))
+			schema, err := GetSchema(version)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schema).To(Equal(Schema{"type": "object"}))
+
+			schema["type"] = "array"
+			Expect(GetSchema(version)).To(Equal(Schema{"type": "object"}))
+		})
+
+		It("should fail for unregistered schemas", func() {
+			_, err := GetSchema(version)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail for invalid schemas", func() {
+			RegisterSchema(version, []byte(`{`))
+			_, err := GetSchema(version)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("RegisterConverter", func() {
+		It("should register new converters", func() {
+			RegisterConverter(version, Version{Number: 1}, func(Config) (Config, error) { return nil, nil })
+			Expect(converters).To(HaveKey(conversion{from: version, to: Version{Number: 1}}))
+		})
+	})
+
+	Context("ConvertTo", func() {
+		var (
+			target = Version{Number: 1}
+			src    = versionedConfig{version: version}
+		)
+
+		It("should use the registered converters", func() {
+			dst := versionedConfig{version: target}
+			RegisterConverter(version, target, func(c Config) (Config, error) {
+				Expect(c).To(Equal(src))
+				return dst, nil
+			})
+			Expect(ConvertTo(src, target)).To(Equal(dst))
+		})
+
+		It("should fail for unregistered versions without converters", func() {
+			_, err := ConvertTo(src, target)
+			Expect(err).To(MatchError(UnsupportedVersionError{Version: target}))
+		})
+	})
 })
+
+// versionedConfig is a Config that only implements GetVersion
+type versionedConfig struct {
+	Config
+	version Version
+}
+
+// GetVersion implements Config
+func (c versionedConfig) GetVersion() Version {
+	return c.version
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Schema is a JSON Schema (https://json-schema.org) describing a project configuration file or a part of it.
+//
+// Validate supports the subset of the specification used by the registered schemas: the "type", "enum", "pattern",
+// "properties", "required", "additionalProperties" and "items" keywords.
+type Schema map[string]interface{}
+
+// Problem is an issue found in a project configuration file.
+type Problem struct {
+	// Line is the line of the file where the problem was found, or zero if unknown.
+	Line int
+	// Field is the path to the field with the problem, e.g. "resources[0].kind", or empty for the whole file.
+	Field string
+	// Message describes the problem.
+	Message string
+}
+
+// String implements fmt.Stringer
+func (p Problem) String() string {
+	msg := p.Message
+	if p.Field != "" {
+		msg = fmt.Sprintf("%s: %s", p.Field, msg)
+	}
+	if p.Line != 0 {
+		msg = fmt.Sprintf("line %d: %s", p.Line, msg)
+	}
+	return msg
+}
+
+// SetPluginSchema sets the schema of the configuration that the plugin with the provided key stores in the plugins
+// field. It returns an error if the schema does not describe a plugins field.
+func (s Schema) SetPluginSchema(pluginKey string, pluginSchema Schema) error {
+	properties, _ := asSchema(s["properties"])
+	plugins, _ := asSchema(properties["plugins"])
+	if plugins == nil {
+		return fmt.Errorf("the schema does not support plugin configurations")
+	}
+
+	pluginProperties, _ := asSchema(plugins["properties"])
+	if pluginProperties == nil {
+		pluginProperties = Schema{}
+		plugins["properties"] = pluginProperties
+	}
+	pluginProperties[pluginKey] = pluginSchema
+	return nil
+}
+
+// Validate validates the YAML node, usually a document, against the schema and returns every problem found.
+func (s Schema) Validate(node *kyaml.Node) []Problem {
+	if node.Kind == kyaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return []Problem{{Line: node.Line, Message: "empty document"}}
+		}
+		node = node.Content[0]
+	}
+	return s.validate(node, "")
+}
+
+func (s Schema) validate(node *kyaml.Node, field string) []Problem {
+	if node.Kind == kyaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	problem := func(format string, args ...interface{}) []Problem {
+		return []Problem{{Line: node.Line, Field: field, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	// Check the type, which determines the rest of the validation
+	nodeType := typeOf(node)
+	if types := stringsOf(s["type"]); len(types) != 0 && !matchesType(nodeType, types) {
+		return problem("must be of type %s, found %s", strings.Join(types, " or "), nodeType)
+	}
+
+	if enum, ok := s["enum"].([]interface{}); ok {
+		valid := false
+		for _, value := range enum {
+			if node.Kind == kyaml.ScalarNode && node.Value == fmt.Sprint(value) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return problem("must be one of %v, found %q", enum, node.Value)
+		}
+	}
+
+	if pattern, ok := s["pattern"].(string); ok && nodeType == "string" {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(node.Value) {
+			return problem("must match %s, found %q", pattern, node.Value)
+		}
+	}
+
+	var problems []Problem
+	switch node.Kind {
+	case kyaml.MappingNode:
+		properties, _ := asSchema(s["properties"])
+		present := make(map[string]bool, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			present[key.Value] = true
+			keyField := key.Value
+			if field != "" {
+				keyField = field + "." + key.Value
+			}
+
+			if propertySchema, found := asSchema(properties[key.Value]); found {
+				problems = append(problems, propertySchema.validate(value, keyField)...)
+				continue
+			}
+			switch additional := s["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					problems = append(problems, Problem{Line: key.Line, Field: keyField, Message: "unknown field"})
+				}
+			default:
+				if additionalSchema, found := asSchema(additional); found {
+					problems = append(problems, additionalSchema.validate(value, keyField)...)
+				}
+			}
+		}
+		for _, required := range stringsOf(s["required"]) {
+			if !present[required] {
+				problems = append(problems, problem("missing required field %q", required)...)
+			}
+		}
+	case kyaml.SequenceNode:
+		if itemSchema, found := asSchema(s["items"]); found {
+			for i, item := range node.Content {
+				problems = append(problems, itemSchema.validate(item, fmt.Sprintf("%s[%d]", field, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+// asSchema returns the value as a Schema, and whether it is one.
+func asSchema(value interface{}) (Schema, bool) {
+	switch s := value.(type) {
+	case Schema:
+		return s, true
+	case map[string]interface{}:
+		return s, true
+	default:
+		return nil, false
+	}
+}
+
+// stringsOf returns the value as a list of strings, either if it is a string or a list of them.
+func stringsOf(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// yaml11Booleans are the plain scalars that YAML 1.1, which project configuration files are unmarshalled with,
+// resolves to booleans on top of the ones of YAML 1.2
+var yaml11Booleans = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true, "on": true, "On": true, "ON": true,
+	"n": true, "N": true, "no": true, "No": true, "NO": true, "off": true, "Off": true, "OFF": true,
+}
+
+// typeOf returns the JSON Schema type of the YAML node.
+func typeOf(node *kyaml.Node) string {
+	switch node.Kind {
+	case kyaml.MappingNode:
+		return "object"
+	case kyaml.SequenceNode:
+		return "array"
+	case kyaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!bool":
+			return "boolean"
+		case "!!int":
+			return "integer"
+		case "!!float":
+			return "number"
+		case "!!null":
+			return "null"
+		default:
+			if node.Style == 0 && yaml11Booleans[node.Value] {
+				return "boolean"
+			}
+			return "string"
+		}
+	default:
+		return "unknown"
+	}
+}
+
+// matchesType returns true if the type is one of the provided ones, taking into account that integers are numbers.
+func matchesType(nodeType string, types []string) bool {
+	for _, t := range types {
+		if t == nodeType || (t == "number" && nodeType == "integer") {
+			return true
+		}
+	}
+	return false
+}
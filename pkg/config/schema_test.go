@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+var _ = Describe("Schema", func() {
+	schema := Schema{
+		"type":                 "object",
+		"required":             []interface{}{"version"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"version": map[string]interface{}{"type": "string", "enum": []interface{}{"3"}},
+			"domain":  map[string]interface{}{"type": "string", "pattern": "^[a-z.]+$"},
+			"layout": map[string]interface{}{
+				"type":  []interface{}{"string", "array"},
+				"items": map[string]interface{}{"type": "string"},
+			},
+			"multigroup": map[string]interface{}{"type": "boolean"},
+			"plugins": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "object"},
+			},
+		},
+	}
+
+	Context("Validate", func() {
+		validate := func(content string) []Problem {
+			doc, err := kyaml.Parse(content)
+			Expect(err).NotTo(HaveOccurred())
+			return schema.Validate(doc.Document())
+		}
+
+		DescribeTable("should not report problems for valid documents",
+			func(content string) { Expect(validate(content)).To(BeEmpty()) },
+			Entry("with the required fields", `version: "3"`),
+			Entry("with a string instead of a list", "version: \"3\"\nlayout: go.kubebuilder.io/v3"),
+			Entry("with a list", "version: \"3\"\nlayout:\n- go.kubebuilder.io/v3"),
+			Entry("with a YAML 1.1 boolean", "version: \"3\"\nmultigroup: yes"),
+			Entry("with additional properties", "version: \"3\"\nplugins:\n  go.kubebuilder.io/v3: {}"),
+		)
+
+		DescribeTable("should report problems with their line and field",
+			func(content string, problem Problem) { Expect(validate(content)).To(Equal([]Problem{problem})) },
+			Entry("for missing required fields", "domain: example",
+				Problem{Line: 1, Message: `missing required field "version"`}),
+			Entry("for unknown fields", "version: \"3\"\nrepo: example",
+				Problem{Line: 2, Field: "repo", Message: "unknown field"}),
+			Entry("for values not in the enum", `version: "2"`,
+				Problem{Line: 1, Field: "version", Message: `must be one of [3], found "2"`}),
+			Entry("for values that do not match the pattern", "version: \"3\"\ndomain: EXAMPLE",
+				Problem{Line: 2, Field: "domain", Message: `must match ^[a-z.]+$, found "EXAMPLE"`}),
+			Entry("for values of other types", "version: \"3\"\nmultigroup: \"true\"",
+				Problem{Line: 2, Field: "multigroup", Message: "must be of type boolean, found string"}),
+			Entry("for items of other types", "version: \"3\"\nlayout:\n- go.kubebuilder.io/v3\n- 3",
+				Problem{Line: 4, Field: "layout[1]", Message: "must be of type string, found integer"}),
+			Entry("for additional properties of other types", "version: \"3\"\nplugins:\n  go.kubebuilder.io/v3: []",
+				Problem{Line: 3, Field: "plugins.go.kubebuilder.io/v3",
+					Message: "must be of type object, found array"}),
+		)
+
+		It("should report every problem", func() {
+			Expect(validate("domain: EXAMPLE\nmultigroup: 1")).To(HaveLen(3))
+		})
+	})
+
+	Context("SetPluginSchema", func() {
+		It("should set the schema of the plugin configuration", func() {
+			s := Schema{"properties": map[string]interface{}{"plugins": map[string]interface{}{"type": "object"}}}
+			Expect(s.SetPluginSchema("go.kubebuilder.io/v3", Schema{"type": "object"})).To(Succeed())
+			Expect(s).To(Equal(Schema{"properties": map[string]interface{}{"plugins": map[string]interface{}{
+				"type":       "object",
+				"properties": Schema{"go.kubebuilder.io/v3": Schema{"type": "object"}},
+			}}}))
+		})
+
+		It("should fail if the schema does not support plugin configurations", func() {
+			Expect(Schema{}.SetPluginSchema("go.kubebuilder.io/v3", Schema{})).NotTo(Succeed())
+		})
+	})
+
+	Context("Problem", func() {
+		DescribeTable("should be printed with its line and field",
+			func(problem Problem, expected string) { Expect(problem.String()).To(Equal(expected)) },
+			Entry("with line and field", Problem{Line: 2, Field: "domain", Message: "invalid"},
+				"line 2: domain: invalid"),
+			Entry("without field", Problem{Line: 1, Message: "invalid"}, "line 1: invalid"),
+			Entry("without line", Problem{Message: "invalid"}, "invalid"),
+		)
+	})
+})
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package json
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/config/store"
+	cfgv2 "sigs.k8s.io/kubebuilder/v3/pkg/config/v2"
+	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+)
+
+func TestConfigStoreJSON(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Config Store JSON Suite")
+}
+
+var _ = Describe("New", func() {
+	It("should return a new empty store", func() {
+		s := New(machinery.Filesystem{FS: afero.NewMemMapFs()})
+		Expect(s.Config()).To(BeNil())
+
+		js, ok := s.(*jsonStore)
+		Expect(ok).To(BeTrue())
+		Expect(js.fs).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("jsonStore", func() {
+	const (
+		v2File = `{
+  "version": "2"
+}
+`
+		v3File = `{
+  "domain": "example.com",
+  "layout": [
+    "go.kubebuilder.io/v3"
+  ],
+  "repo": "github.com/example/project",
+  "resources": [
+    {
+      "api": {
+        "crdVersion": "v1"
+      },
+      "domain": "example.com",
+      "group": "crew",
+      "kind": "Captain",
+      "version": "v1"
+    }
+  ],
+  "version": "3"
+}
+`
+		unversionedFile        = `{"version": null}`
+		nonexistentVersionFile = `{"version": "1-alpha"}`         // v1-alpha never existed
+		wrongFile              = `{"version": "2", "layout": ""}` // layout field does not exist in v2
+		yamlFile               = `version: "2"`
+	)
+
+	var (
+		s *jsonStore
+
+		path = DefaultPath + "2"
+	)
+
+	BeforeEach(func() {
+		s = New(machinery.Filesystem{FS: afero.NewMemMapFs()}).(*jsonStore)
+	})
+
+	Context("New", func() {
+		It("should initialize a new Config backend for the provided version", func() {
+			Expect(s.New(cfgv2.Version)).To(Succeed())
+			Expect(s.mustNotExist).To(BeTrue())
+			Expect(s.Config()).NotTo(BeNil())
+			Expect(s.Config().GetVersion().Compare(cfgv2.Version)).To(Equal(0))
+		})
+
+		It("should fail for an unregistered config version", func() {
+			Expect(s.New(config.Version{})).NotTo(Succeed())
+		})
+	})
+
+	Context("Load", func() {
+		It("should load the Config from an existing file at the default path", func() {
+			Expect(afero.WriteFile(s.fs, DefaultPath, []byte(v2File), os.ModePerm)).To(Succeed())
+
+			Expect(s.Load()).To(Succeed())
+			Expect(s.mustNotExist).To(BeFalse())
+			Expect(s.Config()).NotTo(BeNil())
+			Expect(s.Config().GetVersion().Compare(cfgv2.Version)).To(Equal(0))
+		})
+
+		It("should fail if no file exists at the default path", func() {
+			err := s.Load()
+			Expect(err).To(HaveOccurred())
+			Expect(errors.As(err, &store.LoadError{})).To(BeTrue())
+		})
+	})
+
+	Context("LoadFrom", func() {
+		It("should load the Config from an existing file from the specified path", func() {
+			Expect(afero.WriteFile(s.fs, path, []byte(v3File), os.ModePerm)).To(Succeed())
+
+			Expect(s.LoadFrom(path)).To(Succeed())
+			Expect(s.Config().GetVersion().Compare(cfgv3.Version)).To(Equal(0))
+			Expect(s.Config().GetDomain()).To(Equal("example.com"))
+			Expect(s.Config().GetPluginChain()).To(Equal([]string{"go.kubebuilder.io/v3"}))
+			Expect(s.Config().HasResource(resource.GVK{
+				Group: "crew", Domain: "example.com", Version: "v1", Kind: "Captain",
+			})).To(BeTrue())
+		})
+
+		It("should fail if no file exists at the specified path", func() {
+			err := s.LoadFrom(path)
+			Expect(err).To(HaveOccurred())
+			Expect(errors.As(err, &store.LoadError{})).To(BeTrue())
+		})
+
+		DescribeTable("should fail for invalid files",
+			func(content string) {
+				Expect(afero.WriteFile(s.fs, path, []byte(content), os.ModePerm)).To(Succeed())
+
+				err := s.LoadFrom(path)
+				Expect(err).To(HaveOccurred())
+				Expect(errors.As(err, &store.LoadError{})).To(BeTrue())
+			},
+			Entry("if unable to identify the version", unversionedFile),
+			Entry("if unable to create a Config for the version", nonexistentVersionFile),
+			Entry("if unable to unmarshal the file", wrongFile),
+			Entry("if the file is not JSON", yamlFile),
+		)
+	})
+
+	Context("Save", func() {
+		It("should succeed for a valid config", func() {
+			s.cfg = cfgv2.New()
+			Expect(s.Save()).To(Succeed())
+
+			cfgBytes, err := afero.ReadFile(s.fs, DefaultPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cfgBytes)).To(Equal(v2File))
+		})
+
+		It("should fail for an empty config", func() {
+			err := s.Save()
+			Expect(err).To(HaveOccurred())
+			Expect(errors.As(err, &store.SaveError{})).To(BeTrue())
+		})
+	})
+
+	Context("SaveTo", func() {
+		It("should write the same document that was loaded", func() {
+			Expect(afero.WriteFile(s.fs, path, []byte(v3File), os.ModePerm)).To(Succeed())
+			Expect(s.LoadFrom(path)).To(Succeed())
+			Expect(s.SaveTo(path)).To(Succeed())
+
+			cfgBytes, err := afero.ReadFile(s.fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cfgBytes)).To(Equal(v3File))
+		})
+
+		It("should fail for a pre-existent file that must not exist", func() {
+			s.cfg = cfgv2.New()
+			s.mustNotExist = true
+			Expect(afero.WriteFile(s.fs, path, []byte(v2File), os.ModePerm)).To(Succeed())
+
+			err := s.SaveTo(path)
+			Expect(err).To(HaveOccurred())
+			Expect(errors.As(err, &store.SaveError{})).To(BeTrue())
+		})
+	})
+})
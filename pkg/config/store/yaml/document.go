@@ -0,0 +1,259 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yaml
+
+import (
+	"strings"
+
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// identityKeys are the fields that identify the items of a sequence, e.g. resources, across saves
+var identityKeys = []string{"domain", "group", "version", "kind"}
+
+// mergeDocument updates doc so that it holds the data of content, keeping the comments, the key order and the style
+// of the nodes that are still present, and returns the updated document.
+// The sequences nested in mappings are indented under their keys only if they were in the loaded document.
+func mergeDocument(doc *kyaml.RNode, content []byte) ([]byte, error) {
+	indented := indentsSequences(doc.YNode())
+
+	updated, err := kyaml.Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	mergeNode(doc.YNode(), updated.YNode())
+
+	out, err := doc.String()
+	if err != nil {
+		return nil, err
+	}
+	if !indented {
+		out = compactSequences(out)
+	}
+	return []byte(out), nil
+}
+
+// indentsSequences checks if the first block sequence nested in a mapping is indented under its key.
+// Documents without such sequences are considered compact, which is the style of the files written by default.
+func indentsSequences(node *kyaml.Node) bool {
+	indented, _ := findSequenceIndentation(node)
+	return indented
+}
+
+// findSequenceIndentation walks node looking for a non-empty block sequence nested in a mapping,
+// and reports if one was found and if its items are indented under its key.
+func findSequenceIndentation(node *kyaml.Node) (indented, found bool) {
+	if node.Kind == kyaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if value.Kind == kyaml.SequenceNode && value.Style&kyaml.FlowStyle == 0 && len(value.Content) != 0 {
+				// The column of the items is the one of their content, after the "- " indicator
+				return value.Content[0].Column-len("- ") > key.Column, true
+			}
+		}
+	}
+	for _, child := range node.Content {
+		if indented, found = findSequenceIndentation(child); found {
+			return indented, found
+		}
+	}
+	return false, false
+}
+
+// compactSequences removes the indentation that the encoder adds to the block sequences nested in mappings,
+// e.g. "layout:\n  - go.kubebuilder.io/v3" becomes "layout:\n- go.kubebuilder.io/v3".
+func compactSequences(out string) string {
+	// sequence is a sequence whose lines, more indented than its key, are shifted to the left
+	type sequence struct {
+		keyIndent, shift int
+	}
+	var sequences []sequence
+	// blockScalarIndent is the indentation of the key of the block scalar being read, or -1
+	blockScalarIndent := -1
+
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		if trimmed == "" {
+			continue
+		}
+
+		// The content of block scalars and the comments do not close sequences
+		inBlockScalar := blockScalarIndent >= 0 && indent > blockScalarIndent
+		if !inBlockScalar && !strings.HasPrefix(trimmed, "#") {
+			blockScalarIndent = -1
+			for len(sequences) != 0 && indent <= sequences[len(sequences)-1].keyIndent {
+				sequences = sequences[:len(sequences)-1]
+			}
+		}
+		shift := 0
+		if len(sequences) != 0 {
+			shift = sequences[len(sequences)-1].shift
+		}
+		if shift > indent {
+			shift = indent
+		}
+		lines[i] = line[shift:]
+		if inBlockScalar || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// Sequence items are followed by the first key of their mapping, if any
+		keyIndent, key := indent, trimmed
+		for strings.HasPrefix(key, "- ") {
+			keyIndent, key = keyIndent+2, key[2:]
+		}
+		if index := strings.Index(key, " #"); index != -1 {
+			key = strings.TrimRight(key[:index], " ")
+		}
+		switch {
+		case strings.HasSuffix(key, "|") || strings.HasSuffix(key, "|-") || strings.HasSuffix(key, "|+") ||
+			strings.HasSuffix(key, ">") || strings.HasSuffix(key, ">-") || strings.HasSuffix(key, ">+"):
+			blockScalarIndent = keyIndent
+		case strings.HasSuffix(key, ":"):
+			if nextIndent, ok := sequenceIndent(lines[i+1:]); ok && nextIndent > keyIndent {
+				sequences = append(sequences, sequence{keyIndent: keyIndent, shift: shift + nextIndent - keyIndent})
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sequenceIndent returns the indentation of the first line that is not empty nor a comment,
+// if it is the item of a sequence.
+func sequenceIndent(lines []string) (int, bool) {
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		return len(line) - len(trimmed), strings.HasPrefix(trimmed, "- ") || trimmed == "-"
+	}
+	return 0, false
+}
+
+// mergeNode updates dst in place so that it holds the same data as src.
+func mergeNode(dst, src *kyaml.Node) {
+	if dst.Kind != src.Kind {
+		replaceNode(dst, src)
+		return
+	}
+
+	switch dst.Kind {
+	case kyaml.MappingNode:
+		mergeMapping(dst, src)
+	case kyaml.SequenceNode:
+		mergeSequence(dst, src)
+	case kyaml.ScalarNode:
+		if dst.Value != src.Value || dst.Tag != src.Tag {
+			replaceNode(dst, src)
+		}
+	default:
+		replaceNode(dst, src)
+	}
+}
+
+// mergeMapping keeps the keys of dst that are present in src, in their original order, drops the rest,
+// and appends the keys that are only present in src.
+func mergeMapping(dst, src *kyaml.Node) {
+	srcValues := make(map[string]*kyaml.Node, len(src.Content)/2)
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		srcValues[src.Content[i].Value] = src.Content[i+1]
+	}
+
+	content := make([]*kyaml.Node, 0, len(src.Content))
+	kept := make(map[string]bool, len(srcValues))
+	for i := 0; i+1 < len(dst.Content); i += 2 {
+		key, value := dst.Content[i], dst.Content[i+1]
+		srcValue, found := srcValues[key.Value]
+		if !found {
+			continue
+		}
+		mergeNode(value, srcValue)
+		content = append(content, key, value)
+		kept[key.Value] = true
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		if !kept[src.Content[i].Value] {
+			content = append(content, src.Content[i], src.Content[i+1])
+		}
+	}
+
+	dst.Content = content
+}
+
+// mergeSequence matches the items of src with the items of dst by identity, or by position for items
+// without one, and keeps the order of src.
+func mergeSequence(dst, src *kyaml.Node) {
+	used := make([]bool, len(dst.Content))
+	content := make([]*kyaml.Node, 0, len(src.Content))
+	for i, srcItem := range src.Content {
+		item := srcItem
+		for j, dstItem := range dst.Content {
+			if used[j] || dstItem.Kind != srcItem.Kind {
+				continue
+			}
+			if id := identity(srcItem); id == identity(dstItem) && (id != "" || i == j) {
+				used[j] = true
+				mergeNode(dstItem, srcItem)
+				item = dstItem
+				break
+			}
+		}
+		content = append(content, item)
+	}
+
+	dst.Content = content
+}
+
+// identity returns the value of a scalar, or the values of the identity keys of a mapping.
+// It returns an empty string for nodes without identity.
+func identity(node *kyaml.Node) string {
+	switch node.Kind {
+	case kyaml.ScalarNode:
+		return node.Value
+	case kyaml.MappingNode:
+		values := make([]string, 0, len(identityKeys))
+		for _, identityKey := range identityKeys {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == identityKey && node.Content[i+1].Kind == kyaml.ScalarNode {
+					values = append(values, identityKey+"="+node.Content[i+1].Value)
+				}
+			}
+		}
+		return strings.Join(values, ",")
+	default:
+		return ""
+	}
+}
+
+// replaceNode replaces dst with src, keeping the comments of dst.
+func replaceNode(dst, src *kyaml.Node) {
+	headComment, lineComment, footComment := dst.HeadComment, dst.LineComment, dst.FootComment
+	*dst = *src
+	if headComment != "" {
+		dst.HeadComment = headComment
+	}
+	if lineComment != "" {
+		dst.LineComment = lineComment
+	}
+	if footComment != "" {
+		dst.FootComment = footComment
+	}
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package yaml
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 
 	"github.com/spf13/afero"
+	kyaml "sigs.k8s.io/kustomize/kyaml/yaml"
 	"sigs.k8s.io/yaml"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
@@ -42,6 +44,9 @@ type yamlStore struct {
 	mustNotExist bool
 
 	cfg config.Config
+	// doc is the loaded document, kept to preserve its comments, key order and style when saving it.
+	// It is nil for new configs and for documents with the same content the config would be marshalled to.
+	doc *kyaml.RNode
 }
 
 // New creates a new configuration that will be stored at the provided path
@@ -57,6 +62,7 @@ func (s *yamlStore) New(version config.Version) error {
 	}
 
 	s.cfg = cfg
+	s.doc = nil
 	s.mustNotExist = true
 	return nil
 }
@@ -98,7 +104,16 @@ func (s *yamlStore) LoadFrom(path string) error {
 		return store.LoadError{Err: fmt.Errorf("unable to unmarshal config at %q: %w", path, err)}
 	}
 
+	// Keep the document if it was customized, e.g. with comments, so that saving it does not discard them
+	var doc *kyaml.RNode
+	if canonical, err := cfg.MarshalYAML(); err != nil || !bytes.Equal(canonical, in) {
+		if doc, err = kyaml.Parse(string(in)); err != nil {
+			return store.LoadError{Err: fmt.Errorf("unable to parse config at %q: %w", path, err)}
+		}
+	}
+
 	s.cfg = cfg
+	s.doc = doc
 	return nil
 }
 
@@ -133,6 +148,13 @@ func (s yamlStore) SaveTo(path string) error {
 		return store.SaveError{Err: fmt.Errorf("unable to marshal to YAML: %w", err)}
 	}
 
+	// Apply only the changes to the loaded document
+	if s.doc != nil {
+		if content, err = mergeDocument(s.doc, content); err != nil {
+			return store.SaveError{Err: fmt.Errorf("unable to update the loaded YAML document: %w", err)}
+		}
+	}
+
 	// Write the marshalled configuration
 	err = afero.WriteFile(s.fs, path, content, 0600)
 	if err != nil {
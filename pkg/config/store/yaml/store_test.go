@@ -28,7 +28,9 @@ import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	"sigs.k8s.io/kubebuilder/v3/pkg/config/store"
 	cfgv2 "sigs.k8s.io/kubebuilder/v3/pkg/config/v2"
+	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
 	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 )
 
 func TestConfigStoreYaml(t *testing.T) {
@@ -243,4 +245,154 @@ layout: ""
 			Expect(errors.As(err, &store.SaveError{})).To(BeTrue())
 		})
 	})
+
+	Context("round-trips", func() {
+		const commentedFile = `# Team conventions are recorded in this file
+version: "3"
+# Owned by the platform team
+domain: example.com
+repo: github.com/example/project # module path
+layout:
+- go.kubebuilder.io/v3
+projectName: project
+resources:
+# The crew API
+- group: crew
+  kind: Captain
+  version: v1
+  domain: example.com
+  api:
+    crdVersion: v1 # served since 1.0
+`
+
+		var (
+			captain = resource.GVK{Group: "crew", Domain: "example.com", Version: "v1", Kind: "Captain"}
+			admiral = resource.GVK{Group: "fleet", Domain: "example.com", Version: "v1", Kind: "Admiral"}
+		)
+
+		BeforeEach(func() {
+			Expect(afero.WriteFile(s.fs, path, []byte(commentedFile), os.ModePerm)).To(Succeed())
+			Expect(s.LoadFrom(path)).To(Succeed())
+		})
+
+		It("should keep the comments and the key order when adding fields", func() {
+			Expect(s.cfg.AddResource(resource.Resource{GVK: admiral, Controller: true})).To(Succeed())
+			Expect(s.SaveTo(path)).To(Succeed())
+
+			cfgBytes, err := afero.ReadFile(s.fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cfgBytes)).To(Equal(`# Team conventions are recorded in this file
+version: "3"
+# Owned by the platform team
+domain: example.com
+repo: github.com/example/project # module path
+layout:
+- go.kubebuilder.io/v3
+projectName: project
+resources:
+# The crew API
+- group: crew
+  kind: Captain
+  version: v1
+  domain: example.com
+  api:
+    crdVersion: v1 # served since 1.0
+- controller: true
+  domain: example.com
+  group: fleet
+  kind: Admiral
+  version: v1
+`))
+		})
+
+		It("should keep the comments when updating and removing fields", func() {
+			res, err := s.cfg.GetResource(captain)
+			Expect(err).NotTo(HaveOccurred())
+			res.API.Namespaced = true
+			res.Controller = true
+			Expect(s.cfg.UpdateResource(res)).To(Succeed())
+			Expect(s.cfg.SetRepository("github.com/example/other")).To(Succeed())
+			Expect(s.SaveTo(path)).To(Succeed())
+
+			cfgBytes, err := afero.ReadFile(s.fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cfgBytes)).To(Equal(`# Team conventions are recorded in this file
+version: "3"
+# Owned by the platform team
+domain: example.com
+repo: github.com/example/other # module path
+layout:
+- go.kubebuilder.io/v3
+projectName: project
+resources:
+# The crew API
+- group: crew
+  kind: Captain
+  version: v1
+  domain: example.com
+  api:
+    crdVersion: v1 # served since 1.0
+    namespaced: true
+  controller: true
+`))
+
+			Expect(s.cfg.RemoveResource(captain)).To(Succeed())
+			Expect(s.SaveTo(path)).To(Succeed())
+
+			cfgBytes, err = afero.ReadFile(s.fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cfgBytes)).NotTo(ContainSubstring("Captain"))
+			Expect(string(cfgBytes)).To(HavePrefix(`# Team conventions are recorded in this file
+version: "3"
+# Owned by the platform team
+`))
+		})
+
+		It("should keep the indentation of the sequences of customized files", func() {
+			const indentedFile = `# Sequences are indented in this file
+version: "3"
+domain: example.com
+layout:
+  - go.kubebuilder.io/v3
+projectName: project
+resources:
+  - group: crew
+    kind: Captain
+    version: v1
+    domain: example.com
+`
+			Expect(afero.WriteFile(s.fs, path, []byte(indentedFile), os.ModePerm)).To(Succeed())
+			Expect(s.LoadFrom(path)).To(Succeed())
+
+			Expect(s.cfg.AddResource(resource.Resource{GVK: admiral})).To(Succeed())
+			Expect(s.SaveTo(path)).To(Succeed())
+
+			cfgBytes, err := afero.ReadFile(s.fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(cfgBytes)).To(Equal(indentedFile + `  - domain: example.com
+    group: fleet
+    kind: Admiral
+    version: v1
+`))
+		})
+
+		It("should marshal documents without customizations as before", func() {
+			cfg := cfgv3.New()
+			Expect(cfg.SetDomain("example.com")).To(Succeed())
+			content, err := cfg.MarshalYAML()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(afero.WriteFile(s.fs, path, content, os.ModePerm)).To(Succeed())
+			Expect(s.LoadFrom(path)).To(Succeed())
+			Expect(s.doc).To(BeNil())
+
+			Expect(s.cfg.AddResource(resource.Resource{GVK: admiral})).To(Succeed())
+			Expect(s.SaveTo(path)).To(Succeed())
+
+			expected, err := s.cfg.MarshalYAML()
+			Expect(err).NotTo(HaveOccurred())
+			cfgBytes, err := afero.ReadFile(s.fs, path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfgBytes).To(Equal(expected))
+		})
+	})
 })
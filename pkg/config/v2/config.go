@@ -17,6 +17,7 @@ limitations under the License.
 package v2
 
 import (
+	_ "embed" // for the JSON Schema
 	"strings"
 
 	"sigs.k8s.io/yaml"
@@ -48,8 +49,14 @@ func New() config.Config {
 	return &cfg{Version: Version}
 }
 
+// schema is the JSON Schema of the project configuration files of this version
+//
+//go:embed schema.json
+var schema []byte
+
 func init() {
 	config.Register(Version, New)
+	config.RegisterSchema(Version, schema)
 }
 
 // GetVersion implements config.Config
@@ -207,6 +214,20 @@ func (c *cfg) UpdateResource(res resource.Resource) error {
 	return c.AddResource(res)
 }
 
+// RemoveResource implements config.Config
+func (c *cfg) RemoveResource(gvk resource.GVK) error {
+	gvk.Domain = "" // Version 2 does not include domain per resource
+
+	for i, trackedGVK := range c.Gvks {
+		if gvk.IsEqualTo(trackedGVK) {
+			c.Gvks = append(c.Gvks[:i], c.Gvks[i+1:]...)
+			return nil
+		}
+	}
+
+	return config.ResourceNotFoundError{GVK: gvk}
+}
+
 // HasGroup implements config.Config
 func (c cfg) HasGroup(group string) bool {
 	// Return true if the target group is found in the tracked resources
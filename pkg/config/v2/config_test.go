@@ -211,6 +211,19 @@ var _ = Describe("cfg", func() {
 			Expect(len(c.Gvks)).To(Equal(l))
 		})
 
+		It("RemoveResource should fail for a non-existent resource", func() {
+			Expect(c.RemoveResource(res.GVK)).NotTo(Succeed())
+		})
+
+		It("RemoveResource should remove an existent resource", func() {
+			other := res.GVK
+			other.Kind = "OtherKind"
+			c.Gvks = append(c.Gvks, res.GVK, other)
+
+			Expect(c.RemoveResource(res.GVK)).To(Succeed())
+			Expect(c.Gvks).To(Equal([]resource.GVK{other}))
+		})
+
 		It("HasGroup should return false with no tracked resources", func() {
 			Expect(c.HasGroup(res.Group)).To(BeFalse())
 		})
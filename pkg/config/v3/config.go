@@ -17,6 +17,7 @@ limitations under the License.
 package v3
 
 import (
+	_ "embed" // for the JSON Schema
 	"fmt"
 	"strings"
 
@@ -84,8 +85,14 @@ func New() config.Config {
 	return &cfg{Version: Version}
 }
 
+// schema is the JSON Schema of the project configuration files of this version
+//
+//go:embed schema.json
+var schema []byte
+
 func init() {
 	config.Register(Version, New)
+	config.RegisterSchema(Version, schema)
 }
 
 // GetVersion implements config.Config
@@ -224,6 +231,10 @@ func (c cfg) GetResources() ([]resource.Resource, error) {
 
 // AddResource implements config.Config
 func (c *cfg) AddResource(res resource.Resource) error {
+	if field := unsupportedResourceField(res); field != "" {
+		return config.UnsupportedFieldError{Version: Version, Field: field}
+	}
+
 	// As res is passed by value it is already a shallow copy, but we need to make a deep copy
 	res = res.Copy()
 
@@ -240,6 +251,10 @@ func (c *cfg) AddResource(res resource.Resource) error {
 
 // UpdateResource implements config.Config
 func (c *cfg) UpdateResource(res resource.Resource) error {
+	if field := unsupportedResourceField(res); field != "" {
+		return config.UnsupportedFieldError{Version: Version, Field: field}
+	}
+
 	// As res is passed by value it is already a shallow copy, but we need to make a deep copy
 	res = res.Copy()
 
@@ -258,6 +273,40 @@ func (c *cfg) UpdateResource(res resource.Resource) error {
 	return nil
 }
 
+// unsupportedResourceField returns the name of the first field of the resource set that this version can not store.
+func unsupportedResourceField(res resource.Resource) string {
+	switch {
+	case res.Module != "":
+		return "resources.module"
+	case res.Owner != "":
+		return "resources.owner"
+	case res.API == nil:
+		return ""
+	case len(res.API.ShortNames) != 0:
+		return "resources.api.shortNames"
+	case len(res.API.Categories) != 0:
+		return "resources.api.categories"
+	case res.API.Subresources != nil:
+		return "resources.api.subresources"
+	case res.API.StorageVersion:
+		return "resources.api.storageVersion"
+	default:
+		return ""
+	}
+}
+
+// RemoveResource implements config.Config
+func (c *cfg) RemoveResource(gvk resource.GVK) error {
+	for i, res := range c.Resources {
+		if gvk.IsEqualTo(res.GVK) {
+			c.Resources = append(c.Resources[:i], c.Resources[i+1:]...)
+			return nil
+		}
+	}
+
+	return config.ResourceNotFoundError{GVK: gvk}
+}
+
 // HasGroup implements config.Config
 func (c cfg) HasGroup(group string) bool {
 	// Return true if the target group is found in the tracked resources
@@ -373,5 +422,12 @@ func (c *cfg) UnmarshalYAML(b []byte) error {
 		return config.UnmarshalError{Err: err}
 	}
 
+	// The resources share their type with newer versions, which have more fields
+	for _, res := range c.Resources {
+		if field := unsupportedResourceField(res); field != "" {
+			return config.UnmarshalError{Err: config.UnsupportedFieldError{Version: Version, Field: field}}
+		}
+	}
+
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	cfgv2 "sigs.k8s.io/kubebuilder/v3/pkg/config/v2"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 )
 
@@ -293,6 +294,28 @@ var _ = Describe("cfg", func() {
 			checkResource(c.Resources[0], resWithoutPlural)
 		})
 
+		It("AddResource and UpdateResource should fail for fields only supported by later versions", func() {
+			r := res.Copy()
+			r.Owner = "team"
+			Expect(errors.As(c.AddResource(r), &config.UnsupportedFieldError{})).To(BeTrue())
+			Expect(errors.As(c.UpdateResource(r), &config.UnsupportedFieldError{})).To(BeTrue())
+			Expect(c.Resources).To(BeEmpty())
+		})
+
+		It("RemoveResource should fail for a non-existent resource", func() {
+			Expect(c.RemoveResource(res.GVK)).NotTo(Succeed())
+		})
+
+		It("RemoveResource should remove an existent resource", func() {
+			other := resWithoutPlural.Copy()
+			other.Kind = "OtherKind"
+			c.Resources = append(c.Resources, resWithoutPlural, other)
+
+			Expect(c.RemoveResource(res.GVK)).To(Succeed())
+			Expect(c.Resources).To(HaveLen(1))
+			checkResource(c.Resources[0], other)
+		})
+
 		It("HasGroup should return false with no tracked resources", func() {
 			Expect(c.HasGroup(res.Group)).To(BeFalse())
 		})
@@ -635,6 +658,12 @@ version: "3"
 				Expect(c.UnmarshalYAML([]byte(content))).NotTo(Succeed())
 			},
 			Entry("for unknown fields", `field: 1
+version: "3"`),
+			Entry("for fields only supported by later versions", `resources:
+- group: group
+  kind: Kind
+  owner: team
+  version: v1
 version: "3"`),
 		)
 	})
@@ -645,3 +674,57 @@ var _ = Describe("New", func() {
 		Expect(New().GetVersion().Compare(Version)).To(Equal(0))
 	})
 })
+
+var _ = Describe("Convert", func() {
+	const (
+		domain = "my.domain"
+		repo   = "myrepo"
+		name   = "ProjectName"
+	)
+
+	gvk := resource.GVK{Group: "group", Version: "v1", Kind: "Kind"}
+
+	It("should convert a project configuration 2", func() {
+		src := cfgv2.New()
+		Expect(src.SetDomain(domain)).To(Succeed())
+		Expect(src.SetRepository(repo)).To(Succeed())
+		Expect(src.SetMultiGroup()).To(Succeed())
+		Expect(src.AddResource(resource.Resource{GVK: gvk})).To(Succeed())
+
+		dst := New()
+		Expect(config.Convert(src, dst, "go.kubebuilder.io/v2")).To(Succeed())
+		Expect(dst.GetVersion().Compare(Version)).To(Equal(0))
+		Expect(dst.GetDomain()).To(Equal(domain))
+		Expect(dst.GetRepository()).To(Equal(repo))
+		Expect(dst.GetPluginChain()).To(BeEmpty())
+		Expect(dst.IsMultiGroup()).To(BeTrue())
+		Expect(dst.HasResource(gvk)).To(BeTrue())
+	})
+
+	It("should convert the plugin configurations for the provided keys", func() {
+		const key = "plugin.kubebuilder.io/v1"
+		type pluginConfig struct {
+			Field string `json:"field"`
+		}
+
+		src := New()
+		Expect(src.SetProjectName(name)).To(Succeed())
+		Expect(src.EncodePluginConfig(key, pluginConfig{Field: "value"})).To(Succeed())
+
+		dst := New()
+		Expect(config.Convert(src, dst, key, "other.kubebuilder.io/v1")).To(Succeed())
+		Expect(dst.GetProjectName()).To(Equal(name))
+		var decoded pluginConfig
+		Expect(dst.DecodePluginConfig(key, &decoded)).To(Succeed())
+		Expect(decoded.Field).To(Equal("value"))
+	})
+
+	It("should fail for fields not supported by the destination version", func() {
+		src := New()
+		Expect(src.SetProjectName(name)).To(Succeed())
+
+		err := config.Convert(src, cfgv2.New())
+		Expect(err).To(HaveOccurred())
+		Expect(errors.As(err, &config.UnsupportedFieldError{})).To(BeTrue())
+	})
+})
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff provides line-based diffing utilities for scaffolded files.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operation is the kind of change a line went through.
+type Operation int
+
+const (
+	// Keep means the line is present in both versions.
+	Keep Operation = iota
+	// Delete means the line is only present in the old version.
+	Delete
+	// Insert means the line is only present in the new version.
+	Insert
+)
+
+// Edit represents a single line of a diff.
+type Edit struct {
+	// Operation is the kind of change.
+	Operation Operation
+	// Line is the content of the line, without the trailing line break.
+	Line string
+	// OldIndex is the index of the line in the old version, or -1 for inserted lines.
+	OldIndex int
+	// NewIndex is the index of the line in the new version, or -1 for deleted lines.
+	NewIndex int
+}
+
+// SplitLines splits content into lines, dropping the line break after the last line.
+func SplitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// Lines returns the edits that transform the old lines into the new ones.
+// It uses a longest common subsequence algorithm, so the result is a minimal diff.
+func Lines(oldLines, newLines []string) []Edit {
+	// Strip the common prefix and suffix to reduce the size of the LCS table.
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	a := oldLines[prefix : len(oldLines)-suffix]
+	b := newLines[prefix : len(newLines)-suffix]
+
+	// lcs[i][j] holds the length of the LCS of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	edits := make([]Edit, 0, len(oldLines)+len(newLines))
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, Edit{Operation: Keep, Line: oldLines[i], OldIndex: i, NewIndex: i})
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, Edit{Operation: Keep, Line: a[i], OldIndex: prefix + i, NewIndex: prefix + j})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, Edit{Operation: Delete, Line: a[i], OldIndex: prefix + i, NewIndex: -1})
+			i++
+		default:
+			edits = append(edits, Edit{Operation: Insert, Line: b[j], OldIndex: -1, NewIndex: prefix + j})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		oldIndex, newIndex := len(oldLines)-suffix+k, len(newLines)-suffix+k
+		edits = append(edits, Edit{Operation: Keep, Line: oldLines[oldIndex], OldIndex: oldIndex, NewIndex: newIndex})
+	}
+
+	return edits
+}
+
+// Unified returns a unified diff between the old and new contents using the provided number of context lines.
+// An empty string is returned if both contents are equal.
+func Unified(oldName, newName, oldContent, newContent string, context int) string {
+	if oldContent == newContent {
+		return ""
+	}
+
+	edits := Lines(SplitLines(oldContent), SplitLines(newContent))
+
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(edits); {
+		// Find the next change
+		for start < len(edits) && edits[start].Operation == Keep {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+
+		// Extend the hunk while changes are closer than twice the context
+		end := start
+		for k := start; k < len(edits); k++ {
+			if edits[k].Operation != Keep {
+				end = k + 1
+			} else if k-end >= 2*context {
+				break
+			}
+		}
+
+		first := start - context
+		if first < 0 {
+			first = 0
+		}
+		last := end + context
+		if last > len(edits) {
+			last = len(edits)
+		}
+
+		writeHunk(&sb, edits[first:last])
+		start = last
+	}
+
+	return sb.String()
+}
+
+// writeHunk writes a single unified diff hunk.
+func writeHunk(sb *strings.Builder, edits []Edit) {
+	oldStart, newStart := -1, -1
+	oldCount, newCount := 0, 0
+	for _, edit := range edits {
+		if edit.OldIndex >= 0 {
+			if oldStart < 0 {
+				oldStart = edit.OldIndex
+			}
+			oldCount++
+		}
+		if edit.NewIndex >= 0 {
+			if newStart < 0 {
+				newStart = edit.NewIndex
+			}
+			newCount++
+		}
+	}
+
+	_, _ = fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount))
+	for _, edit := range edits {
+		switch edit.Operation {
+		case Keep:
+			_, _ = sb.WriteString(" ")
+		case Delete:
+			_, _ = sb.WriteString("-")
+		case Insert:
+			_, _ = sb.WriteString("+")
+		}
+		_, _ = sb.WriteString(edit.Line)
+		_, _ = sb.WriteString("\n")
+	}
+}
+
+// hunkRange formats the range of a hunk using 1-based line numbers.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// Empty ranges point to the line before the hunk
+		if start < 0 {
+			start = 0
+		}
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
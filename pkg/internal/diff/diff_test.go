@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDiff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Diff Suite")
+}
+
+var _ = Describe("Lines", func() {
+	It("should return only equal edits for equal contents", func() {
+		lines := []string{"a", "b", "c"}
+		for _, edit := range Lines(lines, lines) {
+			Expect(edit.Operation).To(Equal(Keep))
+		}
+	})
+
+	It("should return a minimal set of changes", func() {
+		edits := Lines([]string{"a", "b", "c", "d"}, []string{"a", "x", "c", "d", "e"})
+		Expect(edits).To(Equal([]Edit{
+			{Operation: Keep, Line: "a", OldIndex: 0, NewIndex: 0},
+			{Operation: Delete, Line: "b", OldIndex: 1, NewIndex: -1},
+			{Operation: Insert, Line: "x", OldIndex: -1, NewIndex: 1},
+			{Operation: Keep, Line: "c", OldIndex: 2, NewIndex: 2},
+			{Operation: Keep, Line: "d", OldIndex: 3, NewIndex: 3},
+			{Operation: Insert, Line: "e", OldIndex: -1, NewIndex: 4},
+		}))
+	})
+})
+
+var _ = Describe("Unified", func() {
+	It("should return an empty string for equal contents", func() {
+		Expect(Unified("a", "b", "same\n", "same\n", 3)).To(BeEmpty())
+	})
+
+	It("should diff a new file", func() {
+		Expect(Unified("/dev/null", "b/file", "", "one\ntwo\n", 3)).To(Equal(`--- /dev/null
++++ b/file
+@@ -0,0 +1,2 @@
++one
++two
+`))
+	})
+
+	It("should split distant changes in different hunks", func() {
+		oldContent := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+		newContent := "1\nII\n3\n4\n5\n6\n7\n8\nIX\n10\n"
+		Expect(Unified("a/file", "b/file", oldContent, newContent, 1)).To(Equal(`--- a/file
++++ b/file
+@@ -1,3 +1,3 @@
+ 1
+-2
++II
+ 3
+@@ -8,3 +8,3 @@
+ 8
+-9
++IX
+ 10
+`))
+	})
+
+	It("should merge close changes in the same hunk", func() {
+		oldContent := "1\n2\n3\n4\n5\n"
+		newContent := "1\nII\n3\nIV\n5\n"
+		Expect(Unified("a/file", "b/file", oldContent, newContent, 1)).To(Equal(`--- a/file
++++ b/file
+@@ -1,5 +1,5 @@
+ 1
+-2
++II
+ 3
+-4
++IV
+ 5
+`))
+	})
+})
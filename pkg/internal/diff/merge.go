@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"strings"
+)
+
+const (
+	conflictStart     = "<<<<<<< "
+	conflictSeparator = "======="
+	conflictEnd       = ">>>>>>> "
+)
+
+// hunk is a contiguous change that replaces the base lines in [start, end) with lines.
+type hunk struct {
+	start, end int
+	lines      []string
+}
+
+// hunks groups the edits between base and other into hunks.
+func hunks(base, other []string) []hunk {
+	result := make([]hunk, 0)
+	basePos := 0
+	var current *hunk
+	for _, edit := range Lines(base, other) {
+		switch edit.Operation {
+		case Keep:
+			if current != nil {
+				result = append(result, *current)
+				current = nil
+			}
+			basePos++
+		case Delete:
+			if current == nil {
+				current = &hunk{start: basePos, end: basePos}
+			}
+			current.end++
+			basePos++
+		case Insert:
+			if current == nil {
+				current = &hunk{start: basePos, end: basePos}
+			}
+			current.lines = append(current.lines, edit.Line)
+		}
+	}
+	if current != nil {
+		result = append(result, *current)
+	}
+	return result
+}
+
+// apply returns the lines in base[start:end] after applying the provided hunks, which must be contained in that range.
+func apply(base []string, start, end int, hs []hunk) []string {
+	out := make([]string, 0, end-start)
+	pos := start
+	for _, h := range hs {
+		out = append(out, base[pos:h.start]...)
+		out = append(out, h.lines...)
+		pos = h.end
+	}
+	return append(out, base[pos:end]...)
+}
+
+// Merge performs a three-way merge of the changes made from base to ours and from base to theirs.
+//
+// Changes that only happened in one side, or that are identical in both sides, are applied.
+// Overlapping changes that differ are written between conflict markers labelled with oursLabel
+// and theirsLabel. The returned boolean reports if any conflict was found.
+func Merge(base, ours, theirs, oursLabel, theirsLabel string) (string, bool) {
+	baseLines := SplitLines(base)
+	oursHunks := hunks(baseLines, SplitLines(ours))
+	theirsHunks := hunks(baseLines, SplitLines(theirs))
+
+	out := make([]string, 0, len(baseLines))
+	conflict := false
+	pos := 0
+	i, j := 0, 0
+	for i < len(oursHunks) || j < len(theirsHunks) {
+		// Start a new region with the first hunk of either side
+		var start, end int
+		switch {
+		case j == len(theirsHunks) || (i < len(oursHunks) && oursHunks[i].start <= theirsHunks[j].start):
+			start, end = oursHunks[i].start, oursHunks[i].end
+		default:
+			start, end = theirsHunks[j].start, theirsHunks[j].end
+		}
+
+		// Extend the region with every hunk of both sides that overlaps or touches it
+		firstOurs, firstTheirs := i, j
+		for extended := true; extended; {
+			extended = false
+			for i < len(oursHunks) && oursHunks[i].start <= end {
+				if oursHunks[i].end > end {
+					end = oursHunks[i].end
+				}
+				i++
+				extended = true
+			}
+			for j < len(theirsHunks) && theirsHunks[j].start <= end {
+				if theirsHunks[j].end > end {
+					end = theirsHunks[j].end
+				}
+				j++
+				extended = true
+			}
+		}
+
+		out = append(out, baseLines[pos:start]...)
+		pos = end
+
+		oursRegion := apply(baseLines, start, end, oursHunks[firstOurs:i])
+		theirsRegion := apply(baseLines, start, end, theirsHunks[firstTheirs:j])
+		switch {
+		case firstTheirs == j:
+			out = append(out, oursRegion...)
+		case firstOurs == i:
+			out = append(out, theirsRegion...)
+		case equalLines(oursRegion, theirsRegion):
+			out = append(out, oursRegion...)
+		default:
+			conflict = true
+			out = append(out, conflictStart+oursLabel)
+			out = append(out, oursRegion...)
+			out = append(out, conflictSeparator)
+			out = append(out, theirsRegion...)
+			out = append(out, conflictEnd+theirsLabel)
+		}
+	}
+	out = append(out, baseLines[pos:]...)
+
+	if len(out) == 0 {
+		return "", conflict
+	}
+	return strings.Join(out, "\n") + "\n", conflict
+}
+
+// equalLines returns true if both slices contain the same lines.
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// MergeTwoWay merges ours and theirs when there is no common base to compare them with.
+//
+// Without a base it is not possible to know which side made a change, so the lines that are equal in both sides are
+// kept and every difference is written between conflict markers labelled with oursLabel and theirsLabel. The returned
+// boolean reports if any conflict was found.
+func MergeTwoWay(ours, theirs, oursLabel, theirsLabel string) (string, bool) {
+	oursLines := SplitLines(ours)
+
+	out := make([]string, 0, len(oursLines))
+	conflict := false
+	pos := 0
+	for _, h := range hunks(oursLines, SplitLines(theirs)) {
+		conflict = true
+		out = append(out, oursLines[pos:h.start]...)
+		out = append(out, conflictStart+oursLabel)
+		out = append(out, oursLines[h.start:h.end]...)
+		out = append(out, conflictSeparator)
+		out = append(out, h.lines...)
+		out = append(out, conflictEnd+theirsLabel)
+		pos = h.end
+	}
+	out = append(out, oursLines[pos:]...)
+
+	if len(out) == 0 {
+		return "", conflict
+	}
+	return strings.Join(out, "\n") + "\n", conflict
+}
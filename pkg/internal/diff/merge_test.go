@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merge", func() {
+	const base = "a\nb\nc\nd\ne\n"
+
+	DescribeTable("should merge without conflicts",
+		func(ours, theirs, expected string) {
+			merged, conflict := Merge(base, ours, theirs, "ours", "theirs")
+			Expect(conflict).To(BeFalse())
+			Expect(merged).To(Equal(expected))
+		},
+		Entry("when nothing changed", base, base, base),
+		Entry("when only ours changed", "a\nB\nc\nd\ne\n", base, "a\nB\nc\nd\ne\n"),
+		Entry("when only theirs changed", base, "a\nb\nc\nD\ne\n", "a\nb\nc\nD\ne\n"),
+		Entry("when both changed different lines", "A\nb\nc\nd\ne\n", "a\nb\nc\nd\nE\n", "A\nb\nc\nd\nE\n"),
+		Entry("when both made the same change", "a\nB\nc\nd\ne\n", "a\nB\nc\nd\ne\n", "a\nB\nc\nd\ne\n"),
+		Entry("when ours inserted and theirs deleted", "a\nb\nc\nx\nd\ne\n", "b\nc\nd\ne\n", "b\nc\nx\nd\ne\n"),
+	)
+
+	It("should write conflict markers for overlapping changes", func() {
+		merged, conflict := Merge(base, "a\nb\nOURS\nd\ne\n", "a\nb\nTHEIRS\nd\ne\n", "current", "scaffolded")
+		Expect(conflict).To(BeTrue())
+		Expect(merged).To(Equal(`a
+b
+<<<<<<< current
+OURS
+=======
+THEIRS
+>>>>>>> scaffolded
+d
+e
+`))
+	})
+
+	It("should conflict the whole content for an empty base", func() {
+		merged, conflict := Merge("", "ours\n", "theirs\n", "current", "scaffolded")
+		Expect(conflict).To(BeTrue())
+		Expect(merged).To(Equal("<<<<<<< current\nours\n=======\ntheirs\n>>>>>>> scaffolded\n"))
+	})
+})
+
+var _ = Describe("MergeTwoWay", func() {
+	It("should keep the content if both sides are equal", func() {
+		merged, conflict := MergeTwoWay("a\nb\n", "a\nb\n", "current", "scaffolded")
+		Expect(conflict).To(BeFalse())
+		Expect(merged).To(Equal("a\nb\n"))
+	})
+
+	It("should write conflict markers for every difference", func() {
+		merged, conflict := MergeTwoWay("a\nOURS\nc\nd\n", "a\nTHEIRS\nc\nd\nNEW\n", "current", "scaffolded")
+		Expect(conflict).To(BeTrue())
+		Expect(merged).To(Equal(`a
+<<<<<<< current
+OURS
+=======
+THEIRS
+>>>>>>> scaffolded
+c
+d
+<<<<<<< current
+=======
+NEW
+>>>>>>> scaffolded
+`))
+	})
+})
@@ -113,6 +113,26 @@ func (e CloseFileError) Unwrap() error {
 	return e.error
 }
 
+// DeleteFileError is a wrapper error that will be used for errors when deleting a file
+type DeleteFileError struct {
+	error
+}
+
+// Unwrap implements Wrapper interface
+func (e DeleteFileError) Unwrap() error {
+	return e.error
+}
+
+// LoadTemplateOverridesError is a wrapper error that will be used for errors when loading the template overrides
+type LoadTemplateOverridesError struct {
+	error
+}
+
+// Unwrap implements Wrapper interface
+func (e LoadTemplateOverridesError) Unwrap() error {
+	return e.error
+}
+
 // ModelAlreadyExistsError is returned if the file is expected not to exist but a previous model does
 type ModelAlreadyExistsError struct {
 	path string
@@ -44,6 +44,8 @@ var _ = Describe("Errors", func() {
 		Entry("for file reading errors", ReadFileError{testErr}),
 		Entry("for file writing errors", WriteFileError{testErr}),
 		Entry("for file closing errors", CloseFileError{testErr}),
+		Entry("for file deletion errors", DeleteFileError{testErr}),
+		Entry("for template overrides loading errors", LoadTemplateOverridesError{testErr}),
 	)
 
 	// NOTE: the following test increases coverage
@@ -16,6 +16,10 @@ limitations under the License.
 
 package machinery
 
+import (
+	"fmt"
+)
+
 // IfExistsAction determines what to do if the scaffold file already exists
 type IfExistsAction int
 
@@ -28,8 +32,57 @@ const (
 
 	// OverwriteFile truncates and overwrites the existing file
 	OverwriteFile
+
+	// MergeFile performs a three-way merge between the previously scaffolded file, the existing file and the new
+	// scaffold, writing conflict markers for the changes that can not be merged automatically
+	MergeFile
+)
+
+// String implements fmt.Stringer
+func (a IfExistsAction) String() string {
+	switch a {
+	case SkipFile:
+		return "skip"
+	case Error:
+		return "error"
+	case OverwriteFile:
+		return "overwrite"
+	case MergeFile:
+		return "merge"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(a))
+	}
+}
+
+// FileOperation is the operation performed on a file while scaffolding
+type FileOperation string
+
+const (
+	// FileCreated means that the file did not exist and was written
+	FileCreated FileOperation = "created"
+
+	// FileUpdated means that the file already existed and was written according to its IfExistsAction
+	FileUpdated FileOperation = "updated"
+
+	// FileSkipped means that the file already existed and was left untouched according to its IfExistsAction
+	FileSkipped FileOperation = "skipped"
+
+	// FileDeleted means that the file was removed
+	FileDeleted FileOperation = "deleted"
 )
 
+// FileReport describes the operation performed on a file while scaffolding
+type FileReport struct {
+	// Path is the file the operation was performed on
+	Path string
+
+	// Operation is the operation performed on the file
+	Operation FileOperation
+
+	// IfExistsAction is the behavior that was configured in case the file existed
+	IfExistsAction IfExistsAction
+}
+
 // File describes a file that will be written
 type File struct {
 	// Path is the file to write
@@ -18,9 +18,17 @@ package machinery
 
 import (
 	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 // Filesystem abstracts the underlying disk for scaffolding
 type Filesystem struct {
 	FS afero.Fs
+
+	// Reporter, if set, is called with the report of every file that is written, skipped or deleted
+	Reporter func(FileReport)
+
+	// Environment is the environment of the execution that scaffolds the files, nil for the process environment
+	Environment *util.Environment
 }
@@ -0,0 +1,237 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinery
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GoAnchor locates the position of a marker in the syntax tree of a Go file, which allows to insert code fragments
+// even if the marker was removed or moved
+type GoAnchor struct {
+	// Imports specifies that the code fragments are import specs, e.g. `alias "path"`,
+	// which are added to the import declaration
+	Imports bool
+
+	// Func is the name of the function whose body the code fragments belong to.
+	// Function literals passed as an argument to a call to a function with this name are also considered,
+	// e.g. "BeforeSuite" for `var _ = BeforeSuite(func() { ... })`.
+	Func string
+
+	// Before lists function calls, e.g. "mgr.Start", that the code fragments need to precede.
+	// Code fragments are inserted in front of the first statement of the function body that contains any of them,
+	// or at the end of the function body if none is found.
+	Before []string
+}
+
+// insertGoCodeFragments inserts the code fragments at the position of their anchors in a Go file.
+// It returns the resulting content and the code fragments that could not be inserted.
+func insertGoCodeFragments(path string, content []byte, anchors map[Marker]GoAnchor,
+	codeFragmentsMap CodeFragmentsMap) ([]byte, CodeFragmentsMap) {
+	notInserted := make(CodeFragmentsMap)
+
+	for _, marker := range sortedMarkers(codeFragmentsMap) {
+		codeFragments := codeFragmentsMap[marker]
+
+		anchor, hasAnchor := anchors[marker]
+		if !hasAnchor {
+			notInserted[marker] = codeFragments
+			continue
+		}
+
+		// The file is parsed again for each marker as previous insertions move the positions
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, path, content, parser.ParseComments)
+		if err != nil {
+			notInserted[marker] = codeFragments
+			continue
+		}
+
+		var offset int
+		var prefix, suffix string
+		found := true
+		if anchor.Imports {
+			offset, prefix, suffix = locateImports(fset, file)
+		} else {
+			offset, suffix, found = locateStatement(fset, file, content, anchor)
+		}
+		if !found {
+			notInserted[marker] = codeFragments
+			continue
+		}
+
+		text := prefix + strings.Join(codeFragments, "") + suffix
+		inserted := make([]byte, 0, len(content)+len(text))
+		inserted = append(inserted, content[:offset]...)
+		inserted = append(inserted, text...)
+		content = append(inserted, content[offset:]...)
+	}
+
+	return content, notInserted
+}
+
+// locateImports returns the offset where import specs need to be inserted,
+// as well as the text that needs to surround them
+func locateImports(fset *token.FileSet, file *ast.File) (offset int, prefix, suffix string) {
+	for _, decl := range file.Decls {
+		genDecl, isGenDecl := decl.(*ast.GenDecl)
+		if !isGenDecl || genDecl.Tok != token.IMPORT {
+			continue
+		}
+
+		// Import specs are added at the end of a grouped import declaration
+		if genDecl.Lparen.IsValid() {
+			return fset.Position(genDecl.Rparen).Offset, "", ""
+		}
+
+		// A new import declaration is added after a single import declaration
+		return fset.Position(genDecl.End()).Offset, "\nimport (\n", ")\n"
+	}
+
+	// A new import declaration is added after the package clause if there are no imports
+	return fset.Position(file.Name.End()).Offset, "\n\nimport (\n", ")\n"
+}
+
+// locateStatement returns the offset where statements need to be inserted according to the anchor,
+// as well as the text that needs to follow them
+func locateStatement(fset *token.FileSet, file *ast.File, content []byte,
+	anchor GoAnchor) (offset int, suffix string, found bool) {
+	body := findFuncBody(file, anchor.Func)
+	if body == nil {
+		return 0, "", false
+	}
+
+	for _, stmt := range body.List {
+		if containsCall(stmt, anchor.Before) {
+			// Keep a blank line between the inserted statements and the statement they precede
+			return lineStart(content, fset.Position(stmt.Pos()).Offset), "\n", true
+		}
+	}
+
+	return lineStart(content, fset.Position(body.Rbrace).Offset), "", true
+}
+
+// findFuncBody returns the body of the function with the provided name, or of the first function literal passed
+// as an argument to a call to a function with that name
+func findFuncBody(file *ast.File, name string) (body *ast.BlockStmt) {
+	if name == "" {
+		return nil
+	}
+
+	for _, decl := range file.Decls {
+		if funcDecl, isFuncDecl := decl.(*ast.FuncDecl); isFuncDecl &&
+			funcDecl.Recv == nil && funcDecl.Name.Name == name && funcDecl.Body != nil {
+			return funcDecl.Body
+		}
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if body != nil {
+			return false
+		}
+
+		call, isCall := node.(*ast.CallExpr)
+		if !isCall || !calls(call, name) {
+			return true
+		}
+
+		for _, arg := range call.Args {
+			if funcLit, isFuncLit := arg.(*ast.FuncLit); isFuncLit {
+				body = funcLit.Body
+				return false
+			}
+		}
+		return true
+	})
+
+	return body
+}
+
+// calls checks if the call expression calls a function with the provided name,
+// either directly (`name(...)`) or through a selector (`pkg.name(...)`)
+func calls(call *ast.CallExpr, name string) bool {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return fun.Name == name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name == name
+	default:
+		return false
+	}
+}
+
+// containsCall checks if the node contains a call to any of the provided functions
+func containsCall(node ast.Node, funcs []string) (found bool) {
+	if len(funcs) == 0 {
+		return false
+	}
+
+	ast.Inspect(node, func(node ast.Node) bool {
+		if found {
+			return false
+		}
+
+		if call, isCall := node.(*ast.CallExpr); isCall {
+			name := types.ExprString(call.Fun)
+			for _, f := range funcs {
+				if name == f {
+					found = true
+					return false
+				}
+			}
+		}
+		return true
+	})
+
+	return found
+}
+
+// lineStart returns the offset of the beginning of the line that contains the provided offset
+func lineStart(content []byte, offset int) int {
+	for offset > 0 && content[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
+
+// sortedMarkers returns the markers of the map sorted so that the output is deterministic
+func sortedMarkers(codeFragmentsMap CodeFragmentsMap) []Marker {
+	markers := make([]Marker, 0, len(codeFragmentsMap))
+	for marker := range codeFragmentsMap {
+		markers = append(markers, marker)
+	}
+	sort.Slice(markers, func(i, j int) bool { return markers[i].String() < markers[j].String() })
+	return markers
+}
+
+// reportNotInserted informs about the code fragments that could not be inserted in a file
+func reportNotInserted(out io.Writer, path string, codeFragmentsMap CodeFragmentsMap) {
+	for _, marker := range sortedMarkers(codeFragmentsMap) {
+		fmt.Fprintf(out, "unable to find where to insert the following code in %s "+
+			"(marker %q was not found), add it manually:\n", path, marker.String())
+		for _, codeFragment := range codeFragmentsMap[marker] {
+			fmt.Fprint(out, codeFragment)
+		}
+	}
+}
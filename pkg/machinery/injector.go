@@ -19,6 +19,7 @@ package machinery
 import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 // injector is used to inject certain fields to file templates.
@@ -31,6 +32,9 @@ type injector struct {
 
 	// resource contains the information of the API that is being scaffolded.
 	resource *resource.Resource
+
+	// environment is the environment of the execution that scaffolds the files.
+	environment *util.Environment
 }
 
 // injectInto injects fields from the universe into the builder
@@ -63,4 +67,8 @@ func (i injector) injectInto(builder Builder) {
 			builderWithResource.InjectResource(i.resource)
 		}
 	}
+	// Inject environment
+	if builderWithEnvironment, hasEnvironment := builder.(HasEnvironment); hasEnvironment {
+		builderWithEnvironment.InjectEnvironment(i.environment)
+	}
 }
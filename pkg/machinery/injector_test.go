@@ -23,6 +23,7 @@ import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 type templateBase struct {
@@ -101,6 +102,15 @@ func (t *templateWithResource) InjectResource(res *resource.Resource) {
 	t.resource = res
 }
 
+type templateWithEnvironment struct {
+	templateBase
+	environment *util.Environment
+}
+
+func (t *templateWithEnvironment) InjectEnvironment(env *util.Environment) {
+	t.environment = env
+}
+
 var _ = Describe("injector", func() {
 	var tmp = templateBase{
 		path:           "my/path/to/file",
@@ -291,5 +301,20 @@ var _ = Describe("injector", func() {
 			})
 
 		})
+
+		Context("Environment", func() {
+			var template *templateWithEnvironment
+
+			BeforeEach(func() {
+				template = &templateWithEnvironment{templateBase: tmp}
+			})
+
+			It("should inject the environment of the execution", func() {
+				env := &util.Environment{Dir: "/project"}
+
+				injector{environment: env}.injectInto(template)
+				Expect(template.environment).To(BeIdenticalTo(env))
+			})
+		})
 	})
 })
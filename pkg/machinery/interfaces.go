@@ -20,6 +20,7 @@ import (
 	"text/template"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 // Builder defines the basic methods that any file builder must implement
@@ -55,6 +56,31 @@ type Inserter interface {
 	GetCodeFragments() CodeFragmentsMap
 }
 
+// GoInserter is an Inserter for Go files that is able to insert code fragments whose marker can not be found
+type GoInserter interface {
+	Inserter
+	// GetGoAnchors returns a map that binds markers to their position in the syntax tree of the Go file
+	GetGoAnchors() map[Marker]GoAnchor
+}
+
+// YAMLInserter is an Inserter for YAML files whose code fragments are entries of sequence fields, which allows to
+// compare them by value and to insert them even if their marker can not be found.
+// If it is also a Remover, its entries are removed from their fields wherever they are.
+type YAMLInserter interface {
+	Inserter
+	// GetYAMLAnchors returns a map that binds markers to their position in the structure of the YAML file
+	GetYAMLAnchors() map[Marker]YAMLAnchor
+}
+
+// Remover is a file builder that removes code fragments from marked positions
+type Remover interface {
+	Builder
+	// GetMarkers returns the different markers where code fragments will be removed from
+	GetMarkers() []Marker
+	// GetCodeFragmentsToRemove returns a map that binds markers to the code fragments that will be removed
+	GetCodeFragmentsToRemove() CodeFragmentsMap
+}
+
 // HasDomain allows the domain to be used on a template
 type HasDomain interface {
 	// InjectDomain sets the template domain
@@ -97,6 +123,12 @@ type HasResource interface {
 	InjectResource(*resource.Resource)
 }
 
+// HasEnvironment allows the environment of the execution to be used on a template
+type HasEnvironment interface {
+	// InjectEnvironment sets the template environment
+	InjectEnvironment(*util.Environment)
+}
+
 // UseCustomFuncMap allows a template to use a custom template.FuncMap instead of the default FuncMap.
 type UseCustomFuncMap interface {
 	// GetFuncMap returns a custom FuncMap.
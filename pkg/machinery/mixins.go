@@ -18,6 +18,7 @@ package machinery
 
 import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 // PathMixin provides file builders with a path field
@@ -151,3 +152,16 @@ func (m *ResourceMixin) InjectResource(res *resource.Resource) {
 		m.Resource = res
 	}
 }
+
+// EnvironmentMixin provides templates with a injectable environment field
+type EnvironmentMixin struct {
+	// Environment is the environment of the execution, nil for the process environment
+	Environment *util.Environment
+}
+
+// InjectEnvironment implements HasEnvironment
+func (m *EnvironmentMixin) InjectEnvironment(env *util.Environment) {
+	if m.Environment == nil {
+		m.Environment = env
+	}
+}
@@ -29,7 +29,9 @@ import (
 	"golang.org/x/tools/imports"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/internal/diff"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 const (
@@ -37,6 +39,20 @@ const (
 
 	defaultDirectoryPermission os.FileMode = 0700
 	defaultFilePermission      os.FileMode = 0600
+
+	// StateDirectory is the directory where the scaffolding state of the project is stored
+	StateDirectory = ".kubebuilder"
+
+	// pristineDirectory is the directory, relative to StateDirectory, where the last output of each template is
+	// stored so that it can be used as the common ancestor of a three-way merge
+	pristineDirectory = "pristine"
+
+	// TemplateOverrideExtension is the extension of the files that replace the body of a template
+	TemplateOverrideExtension = ".tmpl"
+
+	// Labels used in the conflict markers of a three-way merge
+	currentLabel    = "current"
+	scaffoldedLabel = "scaffolded"
 )
 
 var options = imports.Options{
@@ -57,6 +73,20 @@ type Scaffold struct {
 
 	// injector is used to provide several fields to the templates
 	injector injector
+
+	// reporter is called with the report of every file that is written, skipped or deleted
+	reporter func(FileReport)
+
+	// env is the environment of the execution, used to inform the user
+	env *util.Environment
+
+	// templateOverridesDir is the directory that contains the bodies that replace those of the templates
+	templateOverridesDir string
+	// templateOverrides binds the paths of the overridden templates to their new bodies
+	templateOverrides map[string]string
+
+	// ifExistsAction replaces the IfExistsAction of every template if not nil
+	ifExistsAction *IfExistsAction
 }
 
 // ScaffoldOption allows to provide optional arguments to the Scaffold
@@ -68,6 +98,9 @@ func NewScaffold(fs Filesystem, options ...ScaffoldOption) *Scaffold {
 		fs:       fs.FS,
 		dirPerm:  defaultDirectoryPermission,
 		filePerm: defaultFilePermission,
+		reporter: fs.Reporter,
+		env:      fs.Environment,
+		injector: injector{environment: fs.Environment},
 	}
 
 	for _, option := range options {
@@ -116,10 +149,33 @@ func WithResource(resource *resource.Resource) ScaffoldOption {
 	}
 }
 
+// WithTemplateOverrides provides a directory whose files replace the body of the templates that scaffold the
+// matching path. The path of each file is the path of the scaffolded file followed by the TemplateOverrideExtension,
+// and may contain the same placeholders as resource.Resource.Replacer, e.g. "controllers/%[kind]_controller.go.tmpl".
+func WithTemplateOverrides(dir string) ScaffoldOption {
+	return func(s *Scaffold) {
+		s.templateOverridesDir = dir
+	}
+}
+
+// WithIfExistsAction replaces the behavior of every template in case its file already exists,
+// e.g. OverwriteFile to regenerate files regardless of the behavior each template defines
+func WithIfExistsAction(action IfExistsAction) ScaffoldOption {
+	return func(s *Scaffold) {
+		s.ifExistsAction = &action
+	}
+}
+
 // Execute writes to disk the provided files
 func (s *Scaffold) Execute(builders ...Builder) error {
 	// Initialize the files
 	files := make(map[string]*File, len(builders))
+	// Keep track of the template output before any Inserter modifies it
+	pristines := make(map[string]string, len(builders))
+
+	if err := s.loadTemplateOverrides(); err != nil {
+		return LoadTemplateOverridesError{err}
+	}
 
 	for _, builder := range builders {
 		// Inject common fields
@@ -134,7 +190,7 @@ func (s *Scaffold) Execute(builders ...Builder) error {
 
 		// Build models for Template builders
 		if t, isTemplate := builder.(Template); isTemplate {
-			if err := s.buildFileModel(t, files); err != nil {
+			if err := s.buildFileModel(t, files, pristines); err != nil {
 				return err
 			}
 		}
@@ -149,7 +205,75 @@ func (s *Scaffold) Execute(builders ...Builder) error {
 
 	// Persist the files to disk
 	for _, f := range files {
-		if err := s.writeFile(f); err != nil {
+		if err := s.writeFile(f, pristines); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fileDeletion wraps a file builder whose file has to be deleted
+type fileDeletion struct {
+	Builder
+}
+
+// DeleteFile wraps a file builder so that Scaffold.Delete deletes its file even if it is a Remover
+func DeleteFile(builder Builder) Builder {
+	return fileDeletion{builder}
+}
+
+// Delete removes from disk the files built by the provided builders, or the code fragments in case of Removers
+func (s *Scaffold) Delete(builders ...Builder) error {
+	// Initialize the files
+	files := make(map[string]*File, len(builders))
+	paths := make([]string, 0, len(builders))
+
+	for _, builder := range builders {
+		// Unwrap the builders whose file has to be deleted
+		wrapper, deleteFile := builder.(fileDeletion)
+		if deleteFile {
+			builder = wrapper.Builder
+		}
+
+		// Inject common fields
+		s.injector.injectInto(builder)
+
+		// Validate file builders
+		if reqValBuilder, requiresValidation := builder.(RequiresValidation); requiresValidation {
+			if err := reqValBuilder.Validate(); err != nil {
+				return ValidateError{err}
+			}
+		}
+
+		// Template builders need their default values set to know their path
+		if t, isTemplate := builder.(Template); isTemplate {
+			if err := t.SetTemplateDefaults(); err != nil {
+				return SetTemplateDefaultsError{err}
+			}
+		}
+
+		// Build models for Remover builders, which are not deleted even if they are also Templates
+		if r, isRemover := builder.(Remover); isRemover && !deleteFile {
+			if err := s.removeFromFileModel(r, files); err != nil {
+				return err
+			}
+			continue
+		}
+
+		paths = append(paths, builder.GetPath())
+	}
+
+	// Persist the files to disk
+	for _, f := range files {
+		if err := s.writeFile(f, nil); err != nil {
+			return err
+		}
+	}
+
+	// Delete the files from disk
+	for _, path := range paths {
+		if err := s.deleteFile(path); err != nil {
 			return err
 		}
 	}
@@ -158,28 +282,37 @@ func (s *Scaffold) Execute(builders ...Builder) error {
 }
 
 // buildFileModel scaffolds a single file
-func (Scaffold) buildFileModel(t Template, models map[string]*File) error {
+func (s Scaffold) buildFileModel(t Template, models map[string]*File, pristines map[string]string) error {
 	// Set the template default values
 	if err := t.SetTemplateDefaults(); err != nil {
 		return SetTemplateDefaultsError{err}
 	}
 
 	path := t.GetPath()
+	ifExistsAction := t.GetIfExistsAction()
+	if s.ifExistsAction != nil {
+		ifExistsAction = *s.ifExistsAction
+	}
 
 	// Handle already existing models
 	if _, found := models[path]; found {
-		switch t.GetIfExistsAction() {
+		switch ifExistsAction {
 		case SkipFile:
 			return nil
 		case Error:
 			return ModelAlreadyExistsError{path}
-		case OverwriteFile:
+		case OverwriteFile, MergeFile:
 		default:
-			return UnknownIfExistsActionError{path, t.GetIfExistsAction()}
+			return UnknownIfExistsActionError{path, ifExistsAction}
 		}
 	}
 
-	b, err := doTemplate(t)
+	body := t.GetBody()
+	if override, found := s.templateOverrideFor(path); found {
+		body = override
+	}
+
+	b, err := doTemplate(t, body)
 	if err != nil {
 		return err
 	}
@@ -187,13 +320,14 @@ func (Scaffold) buildFileModel(t Template, models map[string]*File) error {
 	models[path] = &File{
 		Path:           path,
 		Contents:       string(b),
-		IfExistsAction: t.GetIfExistsAction(),
+		IfExistsAction: ifExistsAction,
 	}
+	pristines[path] = string(b)
 	return nil
 }
 
-// doTemplate executes the template for a file using the input
-func doTemplate(t Template) ([]byte, error) {
+// doTemplate executes the template body for a file using the input
+func doTemplate(t Template, body string) ([]byte, error) {
 	// Create a new template.Template using the type of the Template as the name
 	temp := template.New(fmt.Sprintf("%T", t))
 
@@ -205,7 +339,7 @@ func doTemplate(t Template) ([]byte, error) {
 	temp.Funcs(fm)
 
 	// Set the template body
-	if _, err := temp.Parse(t.GetBody()); err != nil {
+	if _, err := temp.Parse(body); err != nil {
 		return nil, err
 	}
 
@@ -228,6 +362,59 @@ func doTemplate(t Template) ([]byte, error) {
 	return b, nil
 }
 
+// loadTemplateOverrides reads the template bodies from the template overrides directory
+func (s *Scaffold) loadTemplateOverrides() error {
+	if s.templateOverridesDir == "" || s.templateOverrides != nil {
+		return nil
+	}
+
+	overrides := make(map[string]string)
+	err := afero.Walk(s.fs, s.templateOverridesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != TemplateOverrideExtension {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.templateOverridesDir, path)
+		if err != nil {
+			return err
+		}
+		b, err := afero.ReadFile(s.fs, path)
+		if err != nil {
+			return err
+		}
+		overrides[strings.TrimSuffix(rel, TemplateOverrideExtension)] = string(b)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.templateOverrides = overrides
+	return nil
+}
+
+// templateOverrideFor returns the template body that replaces the one of the template that scaffolds the path
+func (s Scaffold) templateOverrideFor(path string) (string, bool) {
+	path = filepath.Clean(path)
+	if body, found := s.templateOverrides[path]; found {
+		return body, true
+	}
+
+	if s.injector.resource != nil {
+		replacer := s.injector.resource.Replacer()
+		for overridePath, body := range s.templateOverrides {
+			if filepath.Clean(replacer.Replace(overridePath)) == path {
+				return body, true
+			}
+		}
+	}
+
+	return "", false
+}
+
 // updateFileModel updates a single file
 func (s Scaffold) updateFileModel(i Inserter, models map[string]*File) error {
 	m, err := s.loadPreviousModel(i, models)
@@ -236,7 +423,7 @@ func (s Scaffold) updateFileModel(i Inserter, models map[string]*File) error {
 	}
 
 	// Get valid code fragments
-	codeFragments := getValidCodeFragments(i)
+	codeFragments := getValidCodeFragments(i.GetMarkers(), i.GetCodeFragments())
 
 	// Remove code fragments that already were applied
 	err = filterExistingValues(m.Contents, codeFragments)
@@ -244,16 +431,35 @@ func (s Scaffold) updateFileModel(i Inserter, models map[string]*File) error {
 		return err
 	}
 
+	// Remove code fragments whose entries already are in the YAML file, regardless of their formatting
+	yamlInserter, isYAMLInserter := i.(YAMLInserter)
+	isYAMLInserter = isYAMLInserter && (filepath.Ext(i.GetPath()) == ".yaml" || filepath.Ext(i.GetPath()) == ".yml")
+	if isYAMLInserter {
+		filterExistingYAMLValues(m.Contents, yamlInserter.GetYAMLAnchors(), codeFragments)
+	}
+
 	// If no code fragment to insert, we are done
 	if len(codeFragments) == 0 {
 		return nil
 	}
 
+	// Code fragments whose marker is missing are inserted based on the structure of the file when possible
+	missing := extractMissingMarkers(m.Contents, codeFragments)
+
 	content, err := insertStrings(m.Contents, codeFragments)
 	if err != nil {
 		return err
 	}
 
+	if len(missing) != 0 {
+		if goInserter, isGoInserter := i.(GoInserter); isGoInserter && filepath.Ext(i.GetPath()) == ".go" {
+			content, missing = insertGoCodeFragments(i.GetPath(), content, goInserter.GetGoAnchors(), missing)
+		} else if isYAMLInserter {
+			content, missing = insertYAMLCodeFragments(content, yamlInserter.GetYAMLAnchors(), missing)
+		}
+		reportNotInserted(s.env.OutOrStdout(), i.GetPath(), missing)
+	}
+
 	// TODO(adirio): move go-formatting to write step
 	formattedContent := content
 	if ext := filepath.Ext(i.GetPath()); ext == ".go" {
@@ -264,14 +470,17 @@ func (s Scaffold) updateFileModel(i Inserter, models map[string]*File) error {
 	}
 
 	m.Contents = string(formattedContent)
-	m.IfExistsAction = OverwriteFile
+	// Models that are merged keep doing so, as overwriting them would discard the changes in the existing file
+	if m.IfExistsAction != MergeFile {
+		m.IfExistsAction = OverwriteFile
+	}
 	models[m.Path] = m
 	return nil
 }
 
 // loadPreviousModel gets the previous model from the models map or the actual file
-func (s Scaffold) loadPreviousModel(i Inserter, models map[string]*File) (*File, error) {
-	path := i.GetPath()
+func (s Scaffold) loadPreviousModel(b Builder, models map[string]*File) (*File, error) {
+	path := b.GetPath()
 
 	// Lets see if we already have a model for this file
 	if m, found := models[path]; found {
@@ -298,7 +507,7 @@ func (s Scaffold) loadPreviousModel(i Inserter, models map[string]*File) (*File,
 		case Error:
 			// Writing will result in an error, so we can return error now
 			return nil, FileAlreadyExistsError{path}
-		case OverwriteFile:
+		case OverwriteFile, MergeFile:
 			// Model has preference
 			return m, nil
 		default:
@@ -330,13 +539,63 @@ func (s Scaffold) loadModelFromFile(path string) (f *File, err error) {
 	return &File{Path: path, Contents: string(content)}, nil
 }
 
-// getValidCodeFragments obtains the code fragments from a file.Inserter
-func getValidCodeFragments(i Inserter) CodeFragmentsMap {
-	// Get the code fragments
-	codeFragments := i.GetCodeFragments()
+// removeFromFileModel removes code fragments from a single file
+func (s Scaffold) removeFromFileModel(r Remover, models map[string]*File) error {
+	path := r.GetPath()
+
+	// If there is no model nor file, there is nothing to remove
+	if _, found := models[path]; !found {
+		exists, err := afero.Exists(s.fs, path)
+		if err != nil {
+			return ExistsFileError{err}
+		}
+		if !exists {
+			return nil
+		}
+	}
+
+	m, err := s.loadPreviousModel(r, models)
+	if err != nil {
+		return err
+	}
+
+	// Get valid code fragments
+	codeFragments := getValidCodeFragments(r.GetMarkers(), r.GetCodeFragmentsToRemove())
+
+	// If no code fragment to remove, we are done
+	if len(codeFragments) == 0 {
+		return nil
+	}
+
+	content, err := removeStrings(m.Contents, codeFragments)
+	if err != nil {
+		return err
+	}
+
+	// Entries of YAML files are also removed if they were moved away from their marker or reformatted
+	if yamlInserter, isYAMLInserter := r.(YAMLInserter); isYAMLInserter &&
+		(filepath.Ext(path) == ".yaml" || filepath.Ext(path) == ".yml") {
+		content = removeYAMLCodeFragments(content, yamlInserter.GetYAMLAnchors(), codeFragments)
+	}
+
+	// Imports that are no longer used after removing the code fragments are also dropped
+	formattedContent := content
+	if ext := filepath.Ext(path); ext == ".go" {
+		formattedContent, err = imports.Process(path, content, nil)
+		if err != nil {
+			return err
+		}
+	}
 
+	m.Contents = string(formattedContent)
+	m.IfExistsAction = OverwriteFile
+	models[m.Path] = m
+	return nil
+}
+
+// getValidCodeFragments filters the code fragments of a file.Inserter or file.Remover by their markers
+func getValidCodeFragments(validMarkers []Marker, codeFragments CodeFragmentsMap) CodeFragmentsMap {
 	// Validate the code fragments
-	validMarkers := i.GetMarkers()
 	for marker := range codeFragments {
 		valid := false
 		for _, validMarker := range validMarkers {
@@ -444,6 +703,29 @@ func scanMultiline(content string, scanLines int, scanFunc func(contentGroup str
 	return scanner.Err()
 }
 
+// extractMissingMarkers removes the code fragments whose marker is not present in the content from the map,
+// and returns them
+func extractMissingMarkers(content string, codeFragmentsMap CodeFragmentsMap) CodeFragmentsMap {
+	missing := make(CodeFragmentsMap)
+	for marker, codeFragments := range codeFragmentsMap {
+		if !hasMarker(content, marker) {
+			missing[marker] = codeFragments
+			delete(codeFragmentsMap, marker)
+		}
+	}
+	return missing
+}
+
+// hasMarker checks if any line of the content is the marker
+func hasMarker(content string, marker Marker) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if marker.EqualsLine(line) {
+			return true
+		}
+	}
+	return false
+}
+
 func insertStrings(content string, codeFragmentsMap CodeFragmentsMap) ([]byte, error) {
 	out := new(bytes.Buffer)
 
@@ -468,7 +750,96 @@ func insertStrings(content string, codeFragmentsMap CodeFragmentsMap) ([]byte, e
 	return out.Bytes(), nil
 }
 
-func (s Scaffold) writeFile(f *File) (err error) {
+// removeStrings removes the code fragments found above their markers.
+// Lines are compared with trimmed whitespace in order to match different levels of indentation.
+func removeStrings(content string, codeFragmentsMap CodeFragmentsMap) ([]byte, error) {
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for marker, codeFragments := range codeFragmentsMap {
+		for _, codeFragment := range codeFragments {
+			lines = removeCodeFragment(lines, marker, codeFragment)
+		}
+	}
+
+	out := new(bytes.Buffer)
+	for _, line := range lines {
+		_, _ = out.WriteString(line + "\n") // bytes.Buffer.WriteString always returns nil errors
+	}
+
+	return out.Bytes(), nil
+}
+
+// removeCodeFragment removes every occurrence of the code fragment above the marker from lines.
+func removeCodeFragment(lines []string, marker Marker, codeFragment string) []string {
+	fragmentLines := strings.Split(strings.TrimSpace(codeFragment), "\n")
+	for i := range fragmentLines {
+		fragmentLines[i] = strings.TrimSpace(fragmentLines[i])
+	}
+
+	// Code fragments are inserted right above the marker, so only the lines above it are considered,
+	// unless the marker was removed, in which case the whole file is considered
+	end := len(lines)
+	for i, line := range lines {
+		if marker.EqualsLine(line) {
+			end = i
+			break
+		}
+	}
+
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		if i+len(fragmentLines) <= end && matchesLines(lines[i:i+len(fragmentLines)], fragmentLines) {
+			i += len(fragmentLines) - 1
+			continue
+		}
+		out = append(out, lines[i])
+	}
+
+	return out
+}
+
+// matchesLines checks if lines match the trimmed expected lines.
+func matchesLines(lines, expected []string) bool {
+	for i := range expected {
+		if strings.TrimSpace(lines[i]) != expected[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteFile removes a file and its recorded template output
+func (s Scaffold) deleteFile(path string) error {
+	if err := s.fs.Remove(path); err == nil {
+		s.report(path, FileDeleted, SkipFile)
+	} else if !os.IsNotExist(err) {
+		return DeleteFileError{err}
+	}
+
+	if err := s.fs.Remove(pristinePath(path)); err != nil && !os.IsNotExist(err) {
+		return DeleteFileError{err}
+	}
+
+	return nil
+}
+
+// report informs the reporter, if any, about the operation performed on a file
+func (s Scaffold) report(path string, operation FileOperation, ifExistsAction IfExistsAction) {
+	if s.reporter != nil {
+		s.reporter(FileReport{Path: path, Operation: operation, IfExistsAction: ifExistsAction})
+	}
+}
+
+func (s Scaffold) writeFile(f *File, pristines map[string]string) (err error) {
+	contents := f.Contents
+
 	// Check if the file to write already exists
 	exists, err := afero.Exists(s.fs, f.Path)
 	if err != nil {
@@ -478,8 +849,14 @@ func (s Scaffold) writeFile(f *File) (err error) {
 		switch f.IfExistsAction {
 		case OverwriteFile:
 			// By not returning, the file is written as if it didn't exist
+		case MergeFile:
+			// By merging the contents, the changes made to the file since it was scaffolded are kept
+			if contents, err = s.mergeFile(f); err != nil {
+				return err
+			}
 		case SkipFile:
 			// By returning nil, the file is not written but the process will carry on
+			s.report(f.Path, FileSkipped, f.IfExistsAction)
 			return nil
 		case Error:
 			// By returning an error, the file is not written and the process will fail
@@ -487,13 +864,61 @@ func (s Scaffold) writeFile(f *File) (err error) {
 		}
 	}
 
+	if err := s.createOrUpdateFile(f.Path, contents); err != nil {
+		return err
+	}
+	if exists {
+		s.report(f.Path, FileUpdated, f.IfExistsAction)
+	} else {
+		s.report(f.Path, FileCreated, f.IfExistsAction)
+	}
+
+	// Record the template output so that it can be used as the base of future merges
+	if pristine, found := pristines[f.Path]; found {
+		return s.createOrUpdateFile(pristinePath(f.Path), pristine)
+	}
+
+	return nil
+}
+
+// mergeFile performs a three-way merge between the template output recorded the last time the file was written,
+// the existing file and the new model, and returns the merged contents
+func (s Scaffold) mergeFile(f *File) (string, error) {
+	current, err := afero.ReadFile(s.fs, f.Path)
+	if err != nil {
+		return "", ReadFileError{err}
+	}
+
+	// If the previous template output was not recorded, e.g. for files scaffolded before it was recorded,
+	// there is no base to merge with, so every difference is left for the user to resolve
+	var merged string
+	var conflict bool
+	pristine, err := afero.ReadFile(s.fs, pristinePath(f.Path))
+	switch {
+	case os.IsNotExist(err):
+		merged, conflict = diff.MergeTwoWay(string(current), f.Contents, currentLabel, scaffoldedLabel)
+	case err != nil:
+		return "", ReadFileError{err}
+	default:
+		merged, conflict = diff.Merge(string(pristine), string(current), f.Contents, currentLabel, scaffoldedLabel)
+	}
+	if conflict {
+		fmt.Fprintf(s.env.OutOrStdout(),
+			"%s could not be merged automatically, resolve the conflicts marked in the file\n", f.Path)
+	}
+
+	return merged, nil
+}
+
+// createOrUpdateFile writes the contents to the provided path, creating the directory if needed
+func (s Scaffold) createOrUpdateFile(path, contents string) (err error) {
 	// Create the directory if needed
-	if err := s.fs.MkdirAll(filepath.Dir(f.Path), s.dirPerm); err != nil {
+	if err := s.fs.MkdirAll(filepath.Dir(path), s.dirPerm); err != nil {
 		return CreateDirectoryError{err}
 	}
 
 	// Create or truncate the file
-	writer, err := s.fs.OpenFile(f.Path, createOrUpdate, s.filePerm)
+	writer, err := s.fs.OpenFile(path, createOrUpdate, s.filePerm)
 	if err != nil {
 		return CreateFileError{err}
 	}
@@ -503,9 +928,14 @@ func (s Scaffold) writeFile(f *File) (err error) {
 		}
 	}()
 
-	if _, err := writer.Write([]byte(f.Contents)); err != nil {
+	if _, err := writer.Write([]byte(contents)); err != nil {
 		return WriteFileError{err}
 	}
 
 	return nil
 }
+
+// pristinePath returns the path where the template output for the provided path is recorded
+func pristinePath(path string) string {
+	return filepath.Join(StateDirectory, pristineDirectory, path)
+}
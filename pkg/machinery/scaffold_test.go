@@ -17,6 +17,7 @@ package machinery
 import (
 	"errors"
 	"os"
+	"path/filepath"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -105,6 +106,31 @@ var _ = Describe("Scaffold", func() {
 			Expect(s.injector.resource).NotTo(BeNil())
 			Expect(s.injector.resource.GVK.IsEqualTo(res.GVK)).To(BeTrue())
 		})
+
+		It("should succeed with template overrides option", func() {
+			const dir = "templates"
+
+			s := NewScaffold(Filesystem{FS: afero.NewMemMapFs()}, WithTemplateOverrides(dir))
+			Expect(s.fs).NotTo(BeNil())
+			Expect(s.dirPerm).To(Equal(defaultDirectoryPermission))
+			Expect(s.filePerm).To(Equal(defaultFilePermission))
+			Expect(s.injector.config).To(BeNil())
+			Expect(s.injector.boilerplate).To(Equal(""))
+			Expect(s.injector.resource).To(BeNil())
+			Expect(s.templateOverridesDir).To(Equal(dir))
+		})
+
+		It("should succeed with if exists action option", func() {
+			s := NewScaffold(Filesystem{FS: afero.NewMemMapFs()}, WithIfExistsAction(OverwriteFile))
+			Expect(s.fs).NotTo(BeNil())
+			Expect(s.dirPerm).To(Equal(defaultDirectoryPermission))
+			Expect(s.filePerm).To(Equal(defaultFilePermission))
+			Expect(s.injector.config).To(BeNil())
+			Expect(s.injector.boilerplate).To(Equal(""))
+			Expect(s.injector.resource).To(BeNil())
+			Expect(s.ifExistsAction).NotTo(BeNil())
+			Expect(*s.ifExistsAction).To(Equal(OverwriteFile))
+		})
 	})
 
 	Describe("Scaffold.Execute", func() {
@@ -376,6 +402,234 @@ func init() {
 					},
 				},
 			),
+			Entry("should insert go code fragments at their anchors if the markers are missing",
+				pathGo,
+				`package test
+
+import (
+	"fmt"
+)
+
+func init() {
+	fmt.Println("init")
+}
+
+func main() {
+	mgr := newManager()
+
+	if err := mgr.Start(); err != nil {
+		fmt.Println(err)
+	}
+}
+`,
+				`package test
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	fmt.Println("init")
+	fmt.Println("a")
+}
+
+func main() {
+	mgr := newManager()
+
+	fmt.Println(strings.ToUpper("b"))
+
+	if err := mgr.Start(); err != nil {
+		fmt.Println(err)
+	}
+}
+`,
+				fakeGoInserter{
+					fakeInserter: fakeInserter{
+						fakeBuilder: fakeBuilder{path: pathGo},
+						codeFragments: CodeFragmentsMap{
+							NewMarkerFor(pathGo, "imports"): {"\"strings\"\n"},
+							NewMarkerFor(pathGo, "init"):    {"fmt.Println(\"a\")\n"},
+							NewMarkerFor(pathGo, "main"):    {"fmt.Println(strings.ToUpper(\"b\"))\n"},
+						},
+					},
+					anchors: map[Marker]GoAnchor{
+						NewMarkerFor(pathGo, "imports"): {Imports: true},
+						NewMarkerFor(pathGo, "init"):    {Func: "init"},
+						NewMarkerFor(pathGo, "main"):    {Func: "main", Before: []string{"mgr.Start"}},
+					},
+				},
+			),
+			Entry("should insert go code fragments in function literals if the markers are missing",
+				pathGo,
+				`package test
+
+import "fmt"
+
+var _ = BeforeSuite(func() {
+	fmt.Println("before")
+})
+`,
+				`package test
+
+import (
+	"fmt"
+	"strings"
+)
+
+var _ = BeforeSuite(func() {
+	fmt.Println("before")
+	fmt.Println(strings.ToUpper("a"))
+})
+`,
+				fakeGoInserter{
+					fakeInserter: fakeInserter{
+						fakeBuilder: fakeBuilder{path: pathGo},
+						codeFragments: CodeFragmentsMap{
+							NewMarkerFor(pathGo, "imports"): {"\"strings\"\n"},
+							NewMarkerFor(pathGo, "suite"):   {"fmt.Println(strings.ToUpper(\"a\"))\n"},
+						},
+					},
+					anchors: map[Marker]GoAnchor{
+						NewMarkerFor(pathGo, "imports"): {Imports: true},
+						NewMarkerFor(pathGo, "suite"):   {Func: "BeforeSuite", Before: []string{"client.New"}},
+					},
+				},
+			),
+			Entry("should prefer the markers over the anchors",
+				pathGo,
+				`package test
+
+func main() {
+	a := 0
+	//+kubebuilder:scaffold:main
+	_ = a
+}
+`,
+				`package test
+
+func main() {
+	a := 0
+	a++
+	//+kubebuilder:scaffold:main
+	_ = a
+}
+`,
+				fakeGoInserter{
+					fakeInserter: fakeInserter{
+						fakeBuilder: fakeBuilder{path: pathGo},
+						codeFragments: CodeFragmentsMap{
+							NewMarkerFor(pathGo, "main"): {"a++\n"},
+						},
+					},
+					anchors: map[Marker]GoAnchor{
+						NewMarkerFor(pathGo, "main"): {Func: "main"},
+					},
+				},
+			),
+			Entry("should not insert code fragments whose marker and anchor are missing",
+				pathGo,
+				`package test
+
+func main() {}
+`,
+				`package test
+
+func main() {}
+`,
+				fakeGoInserter{
+					fakeInserter: fakeInserter{
+						fakeBuilder: fakeBuilder{path: pathGo},
+						codeFragments: CodeFragmentsMap{
+							NewMarkerFor(pathGo, "init"): {"_ = 0\n"},
+							NewMarkerFor(pathGo, "none"): {"_ = 1\n"},
+						},
+					},
+					anchors: map[Marker]GoAnchor{
+						NewMarkerFor(pathGo, "init"): {Func: "init"},
+					},
+				},
+			),
+			Entry("should filter already existing yaml entries regardless of their formatting",
+				pathYaml,
+				`resources:
+- "a.yaml"
+#+kubebuilder:scaffold:resources
+`,
+				`resources:
+- "a.yaml"
+- b.yaml
+#+kubebuilder:scaffold:resources
+`,
+				fakeYAMLInserter{
+					fakeInserter: fakeInserter{
+						fakeBuilder: fakeBuilder{path: pathYaml},
+						codeFragments: CodeFragmentsMap{
+							NewMarkerFor(pathYaml, "resources"): {"- a.yaml\n", "- b.yaml\n"},
+						},
+					},
+					anchors: map[Marker]YAMLAnchor{
+						NewMarkerFor(pathYaml, "resources"): {Field: []string{"resources"}},
+					},
+				},
+			),
+			Entry("should filter commented-out yaml entries that were uncommented",
+				pathYaml,
+				`patchesStrategicMerge:
+- patch.yaml
+#+kubebuilder:scaffold:patches
+`,
+				`patchesStrategicMerge:
+- patch.yaml
+#- other.yaml
+#+kubebuilder:scaffold:patches
+`,
+				fakeYAMLInserter{
+					fakeInserter: fakeInserter{
+						fakeBuilder: fakeBuilder{path: pathYaml},
+						codeFragments: CodeFragmentsMap{
+							NewMarkerFor(pathYaml, "patches"): {"#- patch.yaml\n", "#- other.yaml\n"},
+						},
+					},
+					anchors: map[Marker]YAMLAnchor{
+						NewMarkerFor(pathYaml, "patches"): {Field: []string{"patchesStrategicMerge"}},
+					},
+				},
+			),
+			Entry("should insert yaml entries in their fields if the markers are missing",
+				pathYaml,
+				`# kustomization
+patchesStrategicMerge:
+- patch.yaml
+resources:
+- a.yaml
+`,
+				`# kustomization
+patchesStrategicMerge:
+- patch.yaml
+#- commented.yaml
+resources:
+- a.yaml
+- b.yaml
+configurations:
+- config.yaml
+`,
+				fakeYAMLInserter{
+					fakeInserter: fakeInserter{
+						fakeBuilder: fakeBuilder{path: pathYaml},
+						codeFragments: CodeFragmentsMap{
+							NewMarkerFor(pathYaml, "resources"):      {"- b.yaml\n"},
+							NewMarkerFor(pathYaml, "configurations"): {"- config.yaml\n"},
+							NewMarkerFor(pathYaml, "patches"):        {"#- commented.yaml\n"},
+						},
+					},
+					anchors: map[Marker]YAMLAnchor{
+						NewMarkerFor(pathYaml, "resources"):      {Field: []string{"resources"}},
+						NewMarkerFor(pathYaml, "configurations"): {Field: []string{"configurations"}},
+						NewMarkerFor(pathYaml, "patches"):        {Field: []string{"patchesStrategicMerge"}},
+					},
+				},
+			),
 			Entry("should not insert anything if no code fragment",
 				pathYaml,
 				`
@@ -448,10 +702,434 @@ func init() {
 				Expect(err).To(HaveOccurred())
 				Expect(errors.As(err, &FileAlreadyExistsError{})).To(BeTrue())
 			})
+
+			It("should replace the behavior of the templates if configured to do so", func() {
+				action := OverwriteFile
+				s.ifExistsAction = &action
+
+				Expect(s.Execute(fakeTemplate{
+					fakeBuilder: fakeBuilder{path: path, ifExistsAction: Error},
+					body:        content,
+				})).To(Succeed())
+
+				b, err := afero.ReadFile(s.fs, path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal(content))
+			})
+		})
+
+		Context("report files", func() {
+			var reports []FileReport
+
+			BeforeEach(func() {
+				reports = nil
+				s.reporter = func(r FileReport) { reports = append(reports, r) }
+			})
+
+			It("should report created, updated and skipped files", func() {
+				Expect(afero.WriteFile(s.fs, pathGo, []byte("package test\n"), 0666)).To(Succeed())
+				Expect(afero.WriteFile(s.fs, pathYaml, []byte{}, 0666)).To(Succeed())
+
+				Expect(s.Execute(
+					fakeTemplate{fakeBuilder: fakeBuilder{path: path, ifExistsAction: Error}, body: content},
+					fakeTemplate{fakeBuilder: fakeBuilder{path: pathGo, ifExistsAction: OverwriteFile},
+						body: "package test\n"},
+					fakeTemplate{fakeBuilder: fakeBuilder{path: pathYaml, ifExistsAction: SkipFile}, body: content},
+				)).To(Succeed())
+
+				Expect(reports).To(ConsistOf(
+					FileReport{Path: path, Operation: FileCreated, IfExistsAction: Error},
+					FileReport{Path: pathGo, Operation: FileUpdated, IfExistsAction: OverwriteFile},
+					FileReport{Path: pathYaml, Operation: FileSkipped, IfExistsAction: SkipFile},
+				))
+			})
+
+			It("should report deleted files", func() {
+				Expect(afero.WriteFile(s.fs, path, []byte(content), 0666)).To(Succeed())
+
+				Expect(s.Delete(fakeTemplate{fakeBuilder: fakeBuilder{path: path}, body: content})).To(Succeed())
+
+				Expect(reports).To(ConsistOf(FileReport{Path: path, Operation: FileDeleted, IfExistsAction: SkipFile}))
+			})
+		})
+
+		Context("override templates", func() {
+			const overridesDir = "templates"
+
+			BeforeEach(func() {
+				s.templateOverridesDir = overridesDir
+			})
+
+			It("should use the body of the override that matches the path", func() {
+				Expect(afero.WriteFile(s.fs, filepath.Join(overridesDir, path+TemplateOverrideExtension),
+					[]byte("overridden {{ .GetPath }}"), 0666)).To(Succeed())
+
+				Expect(s.Execute(fakeTemplate{fakeBuilder: fakeBuilder{path: path}, body: content})).To(Succeed())
+
+				b, err := afero.ReadFile(s.fs, path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal("overridden " + path))
+			})
+
+			It("should resolve the placeholders of the override paths", func() {
+				s.injector.resource = &resource.Resource{
+					GVK: resource.GVK{Group: "crew", Version: "v1", Kind: "Captain"},
+				}
+				overridePath := filepath.Join(overridesDir, "%[group]", "%[kind]"+TemplateOverrideExtension)
+				Expect(afero.WriteFile(s.fs, overridePath, []byte("overridden"), 0666)).To(Succeed())
+
+				Expect(s.Execute(
+					fakeTemplate{fakeBuilder: fakeBuilder{path: filepath.Join("crew", "captain")}, body: content},
+					fakeTemplate{fakeBuilder: fakeBuilder{path: path}, body: content},
+				)).To(Succeed())
+
+				b, err := afero.ReadFile(s.fs, filepath.Join("crew", "captain"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal("overridden"))
+				b, err = afero.ReadFile(s.fs, path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal(content))
+			})
+
+			It("should fail if the directory does not exist", func() {
+				err := s.Execute(fakeTemplate{fakeBuilder: fakeBuilder{path: path}, body: content})
+				Expect(err).To(HaveOccurred())
+				Expect(errors.As(err, &LoadTemplateOverridesError{})).To(BeTrue())
+			})
+		})
+
+		Context("merge when the file already exists", func() {
+			const (
+				original   = "package file\n\nfunc A() {}\n\nfunc B() {}\n"
+				userEdited = "package file\n\nfunc A() { println() }\n\nfunc B() {}\n"
+				upgraded   = "package file\n\nfunc A() {}\n\nfunc B() int { return 0 }\n"
+			)
+
+			var merge = func(body string) {
+				Expect(s.Execute(fakeTemplate{
+					fakeBuilder: fakeBuilder{path: pathGo, ifExistsAction: MergeFile},
+					body:        body,
+				})).To(Succeed())
+			}
+
+			It("should record the template output", func() {
+				merge(original)
+
+				b, err := afero.ReadFile(s.fs, pristinePath(pathGo))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal(original))
+			})
+
+			It("should record the output of templates that are not merged", func() {
+				Expect(s.Execute(fakeTemplate{
+					fakeBuilder: fakeBuilder{path: pathGo, ifExistsAction: Error},
+					body:        original,
+				})).To(Succeed())
+
+				b, err := afero.ReadFile(s.fs, pristinePath(pathGo))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal(original))
+			})
+
+			It("should keep the changes made to a file that was not merged when it was scaffolded", func() {
+				Expect(s.Execute(fakeTemplate{
+					fakeBuilder: fakeBuilder{path: pathGo, ifExistsAction: Error},
+					body:        original,
+				})).To(Succeed())
+				Expect(afero.WriteFile(s.fs, pathGo, []byte(userEdited), 0666)).To(Succeed())
+				merge(upgraded)
+
+				b, err := afero.ReadFile(s.fs, pathGo)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal("package file\n\nfunc A() { println() }\n\nfunc B() int { return 0 }\n"))
+			})
+
+			It("should write conflict markers for every difference if its template output was not recorded", func() {
+				Expect(afero.WriteFile(s.fs, pathGo, []byte(userEdited), 0666)).To(Succeed())
+				merge(upgraded)
+
+				b, err := afero.ReadFile(s.fs, pathGo)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal("package file\n\n" +
+					"<<<<<<< " + currentLabel + "\nfunc A() { println() }\n" +
+					"=======\nfunc A() {}\n>>>>>>> " + scaffoldedLabel + "\n\n" +
+					"<<<<<<< " + currentLabel + "\nfunc B() {}\n" +
+					"=======\nfunc B() int { return 0 }\n>>>>>>> " + scaffoldedLabel + "\n"))
+			})
+
+			It("should keep the changes made to the file", func() {
+				merge(original)
+				Expect(afero.WriteFile(s.fs, pathGo, []byte(userEdited), 0666)).To(Succeed())
+				merge(upgraded)
+
+				b, err := afero.ReadFile(s.fs, pathGo)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal("package file\n\nfunc A() { println() }\n\nfunc B() int { return 0 }\n"))
+
+				b, err = afero.ReadFile(s.fs, pristinePath(pathGo))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal(upgraded))
+			})
+
+			It("should write conflict markers if the changes overlap", func() {
+				merge(original)
+				Expect(afero.WriteFile(s.fs, pathGo, []byte(userEdited), 0666)).To(Succeed())
+				merge("package file\n\nfunc A() error { return nil }\n\nfunc B() {}\n")
+
+				b, err := afero.ReadFile(s.fs, pathGo)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal("package file\n\n" +
+					"<<<<<<< current\nfunc A() { println() }\n" +
+					"=======\nfunc A() error { return nil }\n" +
+					">>>>>>> scaffolded\n\nfunc B() {}\n"))
+			})
+
+			It("should keep merging after inserting code fragments", func() {
+				merge(original)
+				Expect(afero.WriteFile(s.fs, pathGo, []byte(userEdited), 0666)).To(Succeed())
+
+				Expect(s.Execute(
+					fakeTemplate{
+						fakeBuilder: fakeBuilder{path: pathGo, ifExistsAction: MergeFile},
+						body:        original + "\n//+kubebuilder:scaffold:-\n",
+					},
+					fakeInserter{
+						fakeBuilder:   fakeBuilder{path: pathGo},
+						codeFragments: CodeFragmentsMap{NewMarkerFor(pathGo, "-"): {"func C() {}\n"}},
+					},
+				)).To(Succeed())
+
+				b, err := afero.ReadFile(s.fs, pathGo)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(b)).To(Equal(userEdited + "\nfunc C() {}\n\n//+kubebuilder:scaffold:-\n"))
+			})
 		})
 	})
 })
 
+var _ = Describe("Scaffold.Delete", func() {
+	const (
+		path    = "filename"
+		pathGo  = path + ".go"
+		content = "Hello world!"
+	)
+
+	var s *Scaffold
+
+	BeforeEach(func() {
+		s = &Scaffold{fs: afero.NewMemMapFs()}
+	})
+
+	It("should delete the files built by templates and their recorded output", func() {
+		Expect(s.Execute(fakeTemplate{
+			fakeBuilder: fakeBuilder{path: path, ifExistsAction: MergeFile},
+			body:        content,
+		})).To(Succeed())
+		exists, err := afero.Exists(s.fs, pristinePath(path))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+
+		Expect(s.Delete(fakeTemplate{fakeBuilder: fakeBuilder{path: path}})).To(Succeed())
+
+		for _, p := range []string{path, pristinePath(path)} {
+			exists, err := afero.Exists(s.fs, p)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeFalse())
+		}
+	})
+
+	It("should delete the files of removers if requested", func() {
+		Expect(afero.WriteFile(s.fs, pathGo, []byte(content), 0666)).To(Succeed())
+
+		Expect(s.Delete(DeleteFile(fakeTemplateRemover{
+			fakeTemplate:  fakeTemplate{fakeBuilder: fakeBuilder{path: pathGo}},
+			codeFragments: CodeFragmentsMap{NewMarkerFor(pathGo, "-"): {"var a int\n"}},
+		}))).To(Succeed())
+
+		exists, err := afero.Exists(s.fs, pathGo)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should not fail if the files do not exist", func() {
+		Expect(s.Delete(
+			fakeTemplate{fakeBuilder: fakeBuilder{path: path}},
+			fakeRemover{fakeInserter: fakeInserter{
+				fakeBuilder:   fakeBuilder{path: pathGo},
+				codeFragments: CodeFragmentsMap{NewMarkerFor(pathGo, "-"): {"var a int\n"}},
+			}},
+		)).To(Succeed())
+	})
+
+	It("should remove yaml entries from their fields wherever they are", func() {
+		const pathYaml = path + ".yaml"
+		Expect(afero.WriteFile(s.fs, pathYaml, []byte(`resources:
+- a.yaml
+- "b.yaml"
+#+kubebuilder:scaffold:resources
+- c.yaml
+patchesStrategicMerge:
+- patches/b.yaml
+#- patches/a.yaml
+`), 0666)).To(Succeed())
+
+		Expect(s.Delete(fakeYAMLRemover{fakeYAMLInserter{
+			fakeInserter: fakeInserter{
+				fakeBuilder: fakeBuilder{path: pathYaml},
+				codeFragments: CodeFragmentsMap{
+					NewMarkerFor(pathYaml, "resources"): {"- b.yaml\n", "- c.yaml\n"},
+					NewMarkerFor(pathYaml, "patches"):   {"#- patches/b.yaml\n"},
+				},
+			},
+			anchors: map[Marker]YAMLAnchor{
+				NewMarkerFor(pathYaml, "resources"): {Field: []string{"resources"}},
+				NewMarkerFor(pathYaml, "patches"):   {Field: []string{"patchesStrategicMerge"}},
+			},
+		}})).To(Succeed())
+
+		b, err := afero.ReadFile(s.fs, pathYaml)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`resources:
+- a.yaml
+#+kubebuilder:scaffold:resources
+patchesStrategicMerge:
+#- patches/a.yaml
+`))
+	})
+
+	It("should fail if unable to set default values for a template", func() {
+		err := s.Delete(fakeTemplate{err: errors.New("test error")})
+		Expect(err).To(HaveOccurred())
+		Expect(errors.As(err, &SetTemplateDefaultsError{})).To(BeTrue())
+	})
+
+	DescribeTable("remove strings",
+		func(input, expected string, files ...Builder) {
+			Expect(afero.WriteFile(s.fs, pathGo, []byte(input), 0666)).To(Succeed())
+
+			Expect(s.Delete(files...)).To(Succeed())
+
+			b, err := afero.ReadFile(s.fs, pathGo)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(b)).To(Equal(expected))
+		},
+		Entry("should remove the code fragments above the marker",
+			`package test
+
+var a int
+var b int
+
+//+kubebuilder:scaffold:-
+`,
+			`package test
+
+var b int
+
+//+kubebuilder:scaffold:-
+`,
+			fakeRemover{fakeInserter: fakeInserter{
+				fakeBuilder:   fakeBuilder{path: pathGo},
+				codeFragments: CodeFragmentsMap{NewMarkerFor(pathGo, "-"): {"var a int\n"}},
+			}},
+		),
+		Entry("should match code fragments with different indentation",
+			`package test
+
+func init() {
+	_ = "a"
+	_ = "b"
+	//+kubebuilder:scaffold:-
+}
+`,
+			`package test
+
+func init() {
+	_ = "b"
+	//+kubebuilder:scaffold:-
+}
+`,
+			fakeRemover{fakeInserter: fakeInserter{
+				fakeBuilder:   fakeBuilder{path: pathGo},
+				codeFragments: CodeFragmentsMap{NewMarkerFor(pathGo, "-"): {"_ = \"a\"\n"}},
+			}},
+		),
+		Entry("should not remove the code fragments below the marker",
+			`package test
+
+//+kubebuilder:scaffold:-
+
+var a int
+`,
+			`package test
+
+//+kubebuilder:scaffold:-
+
+var a int
+`,
+			fakeRemover{fakeInserter: fakeInserter{
+				fakeBuilder:   fakeBuilder{path: pathGo},
+				codeFragments: CodeFragmentsMap{NewMarkerFor(pathGo, "-"): {"var a int\n"}},
+			}},
+		),
+		Entry("should remove the code fragments anywhere if the marker is missing",
+			`package test
+
+func main() {
+	a := 0
+	a++
+	_ = a
+}
+`,
+			`package test
+
+func main() {
+	a := 0
+	_ = a
+}
+`,
+			fakeRemover{fakeInserter: fakeInserter{
+				fakeBuilder:   fakeBuilder{path: pathGo},
+				codeFragments: CodeFragmentsMap{NewMarkerFor(pathGo, "-"): {"a++\n"}},
+			}},
+		),
+		Entry("should drop imports that are no longer used",
+			`package test
+
+import (
+	"fmt"
+)
+
+var a = fmt.Sprint()
+
+//+kubebuilder:scaffold:-
+`,
+			`package test
+
+//+kubebuilder:scaffold:-
+`,
+			fakeRemover{fakeInserter: fakeInserter{
+				fakeBuilder:   fakeBuilder{path: pathGo},
+				codeFragments: CodeFragmentsMap{NewMarkerFor(pathGo, "-"): {"var a = fmt.Sprint()\n"}},
+			}},
+		),
+		Entry("should keep the file of removers that are also templates",
+			`package test
+
+var a int
+//+kubebuilder:scaffold:-
+`,
+			`package test
+
+//+kubebuilder:scaffold:-
+`,
+			fakeTemplateRemover{
+				fakeTemplate:  fakeTemplate{fakeBuilder: fakeBuilder{path: pathGo}},
+				codeFragments: CodeFragmentsMap{NewMarkerFor(pathGo, "-"): {"var a int\n"}},
+			},
+		),
+	)
+})
+
 var _ Builder = fakeBuilder{}
 
 // fakeBuilder is used to mock a Builder
@@ -532,3 +1210,75 @@ func (f fakeInserter) GetMarkers() []Marker {
 func (f fakeInserter) GetCodeFragments() CodeFragmentsMap {
 	return f.codeFragments
 }
+
+var _ GoInserter = fakeGoInserter{}
+
+type fakeGoInserter struct {
+	fakeInserter
+
+	anchors map[Marker]GoAnchor
+}
+
+// GetGoAnchors implements GoInserter
+func (f fakeGoInserter) GetGoAnchors() map[Marker]GoAnchor {
+	return f.anchors
+}
+
+var _ YAMLInserter = fakeYAMLInserter{}
+
+type fakeYAMLInserter struct {
+	fakeInserter
+
+	anchors map[Marker]YAMLAnchor
+}
+
+// GetYAMLAnchors implements YAMLInserter
+func (f fakeYAMLInserter) GetYAMLAnchors() map[Marker]YAMLAnchor {
+	return f.anchors
+}
+
+var (
+	_ Remover      = fakeYAMLRemover{}
+	_ YAMLInserter = fakeYAMLRemover{}
+)
+
+type fakeYAMLRemover struct {
+	fakeYAMLInserter
+}
+
+// GetCodeFragmentsToRemove implements Remover
+func (f fakeYAMLRemover) GetCodeFragmentsToRemove() CodeFragmentsMap {
+	return f.codeFragments
+}
+
+var _ Remover = fakeRemover{}
+
+type fakeRemover struct {
+	fakeInserter
+}
+
+// GetCodeFragmentsToRemove implements Remover
+func (f fakeRemover) GetCodeFragmentsToRemove() CodeFragmentsMap {
+	return f.codeFragments
+}
+
+var (
+	_ Template = fakeTemplateRemover{}
+	_ Remover  = fakeTemplateRemover{}
+)
+
+type fakeTemplateRemover struct {
+	fakeTemplate
+
+	codeFragments CodeFragmentsMap
+}
+
+// GetMarkers implements Remover
+func (f fakeTemplateRemover) GetMarkers() []Marker {
+	return fakeInserter{codeFragments: f.codeFragments}.GetMarkers()
+}
+
+// GetCodeFragmentsToRemove implements Remover
+func (f fakeTemplateRemover) GetCodeFragmentsToRemove() CodeFragmentsMap {
+	return f.codeFragments
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinery
+
+import (
+	"reflect"
+	"strings"
+
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// YAMLAnchor locates the position of a marker in the structure of a YAML file, which allows to compare code fragments
+// by value and to insert and remove them even if the marker was removed or moved
+type YAMLAnchor struct {
+	// Field is the path to the sequence field whose entries the code fragments are, e.g. ["resources"] or
+	// ["patchesStrategicMerge"]. Commented-out entries, e.g. "#- patch.yaml", are compared as the entries they would
+	// be once uncommented, and are inserted as they are.
+	Field []string
+}
+
+// filterExistingYAMLValues removes the code fragments whose entries are already present in their sequence field,
+// regardless of their formatting
+func filterExistingYAMLValues(content string, anchors map[Marker]YAMLAnchor, codeFragmentsMap CodeFragmentsMap) {
+	node, err := yaml.Parse(content)
+	if err != nil {
+		// Fall back to the text-based comparison
+		return
+	}
+
+	for marker, codeFragments := range codeFragmentsMap {
+		anchor, hasAnchor := anchors[marker]
+		if !hasAnchor {
+			continue
+		}
+
+		field, err := node.Pipe(yaml.Lookup(anchor.Field...))
+		if err != nil || field == nil || field.YNode().Kind != yaml.SequenceNode {
+			continue
+		}
+		existing := make([]interface{}, 0, len(field.YNode().Content))
+		for _, entry := range field.YNode().Content {
+			var value interface{}
+			if err := entry.Decode(&value); err == nil {
+				existing = append(existing, value)
+			}
+		}
+
+		filtered := make([]string, 0, len(codeFragments))
+		for _, codeFragment := range codeFragments {
+			if !entriesExist(codeFragment, existing) {
+				filtered = append(filtered, codeFragment)
+			}
+		}
+
+		if len(filtered) == 0 {
+			delete(codeFragmentsMap, marker)
+		} else {
+			codeFragmentsMap[marker] = filtered
+		}
+	}
+}
+
+// entriesExist checks if the code fragment is a list of sequence entries that are already in the existing values
+func entriesExist(codeFragment string, existing []interface{}) bool {
+	entries, ok := yamlEntries(codeFragment)
+	if !ok {
+		return false
+	}
+
+	for _, entry := range entries {
+		if !containsValue(existing, entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsValue checks if value is one of values
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// yamlEntries decodes the sequence entries of a code fragment, uncommenting them if they are commented out
+func yamlEntries(codeFragment string) ([]interface{}, bool) {
+	lines := strings.Split(codeFragment, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		lines[i] = line[:len(line)-len(trimmed)] + strings.TrimPrefix(trimmed, "#")
+	}
+
+	var entries []interface{}
+	if err := yaml.Unmarshal([]byte(strings.Join(lines, "\n")), &entries); err != nil || len(entries) == 0 {
+		return nil, false
+	}
+	return entries, true
+}
+
+// insertYAMLCodeFragments appends the code fragments to the sequence fields of their anchors in a YAML file,
+// creating them if needed. It returns the resulting content and the code fragments that could not be inserted.
+func insertYAMLCodeFragments(content []byte, anchors map[Marker]YAMLAnchor,
+	codeFragmentsMap CodeFragmentsMap) ([]byte, CodeFragmentsMap) {
+	notInserted := make(CodeFragmentsMap)
+
+	for _, marker := range sortedMarkers(codeFragmentsMap) {
+		anchor, hasAnchor := anchors[marker]
+		if !hasAnchor {
+			notInserted[marker] = codeFragmentsMap[marker]
+			continue
+		}
+
+		for _, codeFragment := range codeFragmentsMap[marker] {
+			if _, ok := yamlEntries(codeFragment); !ok {
+				notInserted[marker] = append(notInserted[marker], codeFragment)
+				continue
+			}
+
+			var inserted bool
+			if content, inserted = appendYAMLEntries(content, anchor.Field, codeFragment); !inserted {
+				notInserted[marker] = append(notInserted[marker], codeFragment)
+			}
+		}
+	}
+
+	return content, notInserted
+}
+
+// appendYAMLEntries appends the sequence entries after the last entry of the field, or adds the field to its parent
+// mapping if it does not exist. The rest of the content is kept as is in order to respect the user's formatting.
+func appendYAMLEntries(content []byte, field []string, entries string) ([]byte, bool) {
+	if len(field) == 0 {
+		return content, false
+	}
+
+	node, err := yaml.Parse(string(content))
+	if err != nil {
+		return content, false
+	}
+	parent := node
+	if len(field) > 1 {
+		if parent, err = node.Pipe(yaml.Lookup(field[:len(field)-1]...)); err != nil || parent == nil {
+			return content, false
+		}
+	}
+	if parent.YNode().Kind != yaml.MappingNode || len(parent.YNode().Content) == 0 {
+		return content, false
+	}
+
+	var key, value *yaml.Node
+	for i := 0; i+1 < len(parent.YNode().Content); i += 2 {
+		if parent.YNode().Content[i].Value == field[len(field)-1] {
+			key, value = parent.YNode().Content[i], parent.YNode().Content[i+1]
+			break
+		}
+	}
+
+	var line, indent int
+	var text string
+	switch {
+	case key == nil:
+		// Add the field after the last line of its parent mapping, at the same indentation as its other keys
+		line, indent = lastLine(parent.YNode()), parent.YNode().Content[0].Column-1
+		text = field[len(field)-1] + ":\n" + entries
+	case value.Kind == yaml.SequenceNode && value.Style&yaml.FlowStyle == 0 && len(value.Content) != 0:
+		// Entries are preceded by "- ", so their dash is two columns before their content
+		line, indent = lastLine(value), value.Content[0].Column-3
+		text = entries
+	case value.Kind == yaml.ScalarNode && value.Tag == yaml.NodeTagNull:
+		line, indent = key.Line, key.Column-1
+		text = entries
+	default:
+		return content, false
+	}
+	if indent < 0 {
+		indent = 0
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	if line > len(lines) {
+		return content, false
+	}
+	if !strings.HasSuffix(lines[line-1], "\n") {
+		lines[line-1] += "\n"
+	}
+
+	out := new(strings.Builder)
+	for _, l := range lines[:line] {
+		_, _ = out.WriteString(l) // strings.Builder.WriteString always returns nil errors
+	}
+	for _, l := range strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n") {
+		_, _ = out.WriteString(strings.Repeat(" ", indent) + strings.TrimSuffix(l, "\n") + "\n")
+	}
+	for _, l := range lines[line:] {
+		_, _ = out.WriteString(l)
+	}
+
+	return []byte(out.String()), true
+}
+
+// removeYAMLCodeFragments removes the entries of the code fragments from the sequence fields of their anchors,
+// wherever they are in the field and regardless of their formatting. Lines that are not removed are kept as they are.
+func removeYAMLCodeFragments(content []byte, anchors map[Marker]YAMLAnchor, codeFragmentsMap CodeFragmentsMap) []byte {
+	for _, marker := range sortedMarkers(codeFragmentsMap) {
+		anchor, hasAnchor := anchors[marker]
+		if !hasAnchor {
+			continue
+		}
+
+		for _, codeFragment := range codeFragmentsMap[marker] {
+			if entries, ok := yamlEntries(codeFragment); ok {
+				content = removeYAMLEntries(content, anchor.Field, entries)
+			}
+		}
+	}
+
+	return content
+}
+
+// removeYAMLEntries removes the lines of the block sequence entries of the field that are equal to any of entries
+func removeYAMLEntries(content []byte, field []string, entries []interface{}) []byte {
+	node, err := yaml.Parse(string(content))
+	if err != nil {
+		return content
+	}
+	value, err := node.Pipe(yaml.Lookup(field...))
+	if err != nil || value == nil {
+		return content
+	}
+	if value.YNode().Kind != yaml.SequenceNode || value.YNode().Style&yaml.FlowStyle != 0 {
+		return content
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	removed := make([]bool, len(lines))
+	for _, item := range value.YNode().Content {
+		var itemValue interface{}
+		if err := item.Decode(&itemValue); err != nil || !containsValue(entries, itemValue) {
+			continue
+		}
+		for line := item.Line; line <= lastLine(item) && line <= len(lines); line++ {
+			removed[line-1] = true
+		}
+	}
+
+	out := new(strings.Builder)
+	for i, l := range lines {
+		if !removed[i] {
+			_, _ = out.WriteString(l) // strings.Builder.WriteString always returns nil errors
+		}
+	}
+	return []byte(out.String())
+}
+
+// lastLine returns the last line of the content of a node
+func lastLine(node *yaml.Node) int {
+	line := node.Line
+	for _, child := range node.Content {
+		if childLine := lastLine(child); childLine > line {
+			line = childLine
+		}
+	}
+	return line
+}
@@ -18,6 +18,8 @@ package resource
 
 import (
 	"fmt"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/internal/validation"
 )
 
 // API contains information about scaffolded APIs
@@ -27,6 +29,27 @@ type API struct {
 
 	// Namespaced is true if the API is namespaced.
 	Namespaced bool `json:"namespaced,omitempty"`
+
+	// ShortNames are the short names of the resource, e.g. to be used with kubectl.
+	ShortNames []string `json:"shortNames,omitempty"`
+
+	// Categories are the groups of resources the resource belongs to, e.g. "all".
+	Categories []string `json:"categories,omitempty"`
+
+	// Subresources holds the subresources enabled for the resource.
+	Subresources *Subresources `json:"subresources,omitempty"`
+
+	// StorageVersion is true if this version of the resource is the one persisted in storage.
+	StorageVersion bool `json:"storageVersion,omitempty"`
+}
+
+// Subresources contains information about the subresources enabled for an API
+type Subresources struct {
+	// Status is true if the status subresource is enabled.
+	Status bool `json:"status,omitempty"`
+
+	// Scale is true if the scale subresource is enabled.
+	Scale bool `json:"scale,omitempty"`
 }
 
 // Validate checks that the API is valid.
@@ -36,6 +59,19 @@ func (api API) Validate() error {
 		return fmt.Errorf("invalid CRD version: %w", err)
 	}
 
+	// Validate the short names and categories
+	// NOTE: IsDNS1035Label returns a slice of strings instead of an error, so no wrapping
+	for _, shortName := range api.ShortNames {
+		if errors := validation.IsDNS1035Label(shortName); len(errors) != 0 {
+			return fmt.Errorf("invalid short name %q: %#v", shortName, errors)
+		}
+	}
+	for _, category := range api.Categories {
+		if errors := validation.IsDNS1035Label(category); len(errors) != 0 {
+			return fmt.Errorf("invalid category %q: %#v", category, errors)
+		}
+	}
+
 	return nil
 }
 
@@ -43,6 +79,16 @@ func (api API) Validate() error {
 func (api API) Copy() API {
 	// As this function doesn't use a pointer receiver, api is already a shallow copy.
 	// Any field that is a pointer, slice or map needs to be deep copied.
+	if api.ShortNames != nil {
+		api.ShortNames = append([]string{}, api.ShortNames...)
+	}
+	if api.Categories != nil {
+		api.Categories = append([]string{}, api.Categories...)
+	}
+	if api.Subresources != nil {
+		subresources := *api.Subresources
+		api.Subresources = &subresources
+	}
 	return api
 }
 
@@ -65,10 +111,58 @@ func (api *API) Update(other *API) error {
 	// Update the namespace.
 	api.Namespaced = api.Namespaced || other.Namespaced
 
+	// Update the short names and categories.
+	api.ShortNames = appendMissing(api.ShortNames, other.ShortNames...)
+	api.Categories = appendMissing(api.Categories, other.Categories...)
+
+	// Update the subresources.
+	if other.Subresources != nil {
+		if api.Subresources == nil {
+			api.Subresources = &Subresources{}
+		}
+		api.Subresources.Status = api.Subresources.Status || other.Subresources.Status
+		api.Subresources.Scale = api.Subresources.Scale || other.Subresources.Scale
+	}
+
+	// Update the storage version.
+	api.StorageVersion = api.StorageVersion || other.StorageVersion
+
 	return nil
 }
 
 // IsEmpty returns if the API's fields all contain zero-values.
 func (api API) IsEmpty() bool {
-	return api.CRDVersion == "" && !api.Namespaced
+	return api.CRDVersion == "" && !api.Namespaced && len(api.ShortNames) == 0 && len(api.Categories) == 0 &&
+		(api.Subresources == nil || *api.Subresources == Subresources{}) && !api.StorageVersion
+}
+
+// GetShortNames returns the short names of the resource, if any.
+func (api *API) GetShortNames() []string {
+	if api == nil {
+		return nil
+	}
+	return api.ShortNames
+}
+
+// GetCategories returns the categories of the resource, if any.
+func (api *API) GetCategories() []string {
+	if api == nil {
+		return nil
+	}
+	return api.Categories
+}
+
+// HasStatusSubresource returns true if the status subresource is enabled.
+func (api *API) HasStatusSubresource() bool {
+	return api != nil && api.Subresources != nil && api.Subresources.Status
+}
+
+// HasScaleSubresource returns true if the scale subresource is enabled.
+func (api *API) HasScaleSubresource() bool {
+	return api != nil && api.Subresources != nil && api.Subresources.Scale
+}
+
+// IsStorageVersion returns true if this version of the resource is the one persisted in storage.
+func (api *API) IsStorageVersion() bool {
+	return api != nil && api.StorageVersion
 }
@@ -34,6 +34,8 @@ var _ = Describe("API", func() {
 			// Ensure that the rest of the fields are valid to check each part
 			Entry("empty CRD version", API{}),
 			Entry("invalid CRD version", API{CRDVersion: "1"}),
+			Entry("invalid short name", API{CRDVersion: v1, ShortNames: []string{"Short_Name"}}),
+			Entry("invalid category", API{CRDVersion: v1, Categories: []string{"Category_"}}),
 		)
 	})
 
@@ -113,6 +115,75 @@ var _ = Describe("API", func() {
 				Expect(api.Namespaced).To(BeFalse())
 			})
 		})
+
+		Context("Short names and categories", func() {
+			It("should add the ones that are not present", func() {
+				api = API{ShortNames: []string{"cap"}, Categories: []string{"all"}}
+				other = API{ShortNames: []string{"cap", "cpt"}, Categories: []string{"crew"}}
+				Expect(api.Update(&other)).To(Succeed())
+				Expect(api.ShortNames).To(Equal([]string{"cap", "cpt"}))
+				Expect(api.Categories).To(Equal([]string{"all", "crew"}))
+			})
+		})
+
+		Context("Subresources", func() {
+			It("should enable the subresources enabled by any of them", func() {
+				api = API{}
+				other = API{Subresources: &Subresources{Status: true}}
+				Expect(api.Update(&other)).To(Succeed())
+				Expect(api.Subresources).To(Equal(&Subresources{Status: true}))
+
+				other = API{Subresources: &Subresources{Scale: true}}
+				Expect(api.Update(&other)).To(Succeed())
+				Expect(api.Subresources).To(Equal(&Subresources{Status: true, Scale: true}))
+			})
+		})
+
+		Context("Storage version", func() {
+			It("should set the storage version if provided", func() {
+				api = API{}
+				other = API{StorageVersion: true}
+				Expect(api.Update(&other)).To(Succeed())
+				Expect(api.StorageVersion).To(BeTrue())
+			})
+		})
+	})
+
+	Context("Copy", func() {
+		It("should not share the short names, categories and subresources", func() {
+			api := API{ShortNames: []string{"cap"}, Categories: []string{"all"}, Subresources: &Subresources{}}
+			other := api.Copy()
+			other.ShortNames[0] = "cpt"
+			other.Categories[0] = "crew"
+			other.Subresources.Status = true
+			Expect(api).To(Equal(API{ShortNames: []string{"cap"}, Categories: []string{"all"},
+				Subresources: &Subresources{}}))
+		})
+	})
+
+	Context("Accessors", func() {
+		It("should return the zero values for a nil API", func() {
+			var api *API
+			Expect(api.GetShortNames()).To(BeNil())
+			Expect(api.GetCategories()).To(BeNil())
+			Expect(api.HasStatusSubresource()).To(BeFalse())
+			Expect(api.HasScaleSubresource()).To(BeFalse())
+			Expect(api.IsStorageVersion()).To(BeFalse())
+		})
+
+		It("should return the stored values", func() {
+			api := &API{
+				ShortNames:     []string{"cap"},
+				Categories:     []string{"all"},
+				Subresources:   &Subresources{Status: true, Scale: true},
+				StorageVersion: true,
+			}
+			Expect(api.GetShortNames()).To(Equal([]string{"cap"}))
+			Expect(api.GetCategories()).To(Equal([]string{"all"}))
+			Expect(api.HasStatusSubresource()).To(BeTrue())
+			Expect(api.HasScaleSubresource()).To(BeTrue())
+			Expect(api.IsStorageVersion()).To(BeTrue())
+		})
 	})
 
 	Context("IsEmpty", func() {
@@ -135,6 +206,10 @@ var _ = Describe("API", func() {
 			func(api API) { Expect(api.IsEmpty()).To(BeFalse()) },
 			Entry("cluster-scope", cluster),
 			Entry("namespace-scope", namespaced),
+			Entry("short names", API{ShortNames: []string{"cap"}}),
+			Entry("categories", API{Categories: []string{"all"}}),
+			Entry("subresources", API{Subresources: &Subresources{Status: true}}),
+			Entry("storage version", API{StorageVersion: true}),
 		)
 	})
 })
@@ -42,6 +42,12 @@ type Resource struct {
 
 	// Webhooks holds the information related to the associated webhooks.
 	Webhooks *Webhooks `json:"webhooks,omitempty"`
+
+	// Module is the path of the Go module that provides the types of an external resource.
+	Module string `json:"module,omitempty"`
+
+	// Owner is the team or person that owns the resource.
+	Owner string `json:"owner,omitempty"`
 }
 
 // Validate checks that the Resource is valid.
@@ -119,6 +125,21 @@ func (r Resource) HasConversionWebhook() bool {
 	return r.Webhooks != nil && r.Webhooks.Conversion
 }
 
+// IsExternal returns true if the API types of the resource are provided by another Go module.
+func (r Resource) IsExternal() bool {
+	return r.Module != ""
+}
+
+// GetModule returns the Go module that provides the API types of the resource, if it is not the project.
+func (r Resource) GetModule() string {
+	return r.Module
+}
+
+// GetOwner returns the team or person that owns the resource, if any.
+func (r Resource) GetOwner() string {
+	return r.Owner
+}
+
 // IsRegularPlural returns true if the plural is the regular plural form for the kind.
 func (r Resource) IsRegularPlural() bool {
 	return r.Plural == RegularPlural(r.Kind)
@@ -164,6 +185,20 @@ func (r *Resource) Update(other Resource) error {
 		}
 	}
 
+	if other.Module != "" && r.Module != other.Module {
+		if r.Module == "" {
+			r.Module = other.Module
+		} else {
+			return fmt.Errorf("unable to update Resource (Module %q) with another with non-matching Module %q",
+				r.Module, other.Module)
+		}
+	}
+
+	// Update the owner.
+	if r.Owner == "" {
+		r.Owner = other.Owner
+	}
+
 	// Update API.
 	if r.API == nil && other.API != nil {
 		r.API = &API{}
@@ -183,6 +183,16 @@ var _ = Describe("Resource", func() {
 			)
 		})
 
+		Context("IsExternal", func() {
+			It("should return true if the API types are provided by another module", func() {
+				Expect(Resource{Module: "github.com/example/apis"}.IsExternal()).To(BeTrue())
+			})
+
+			It("should return false if the API types are part of the project", func() {
+				Expect(Resource{}.IsExternal()).To(BeFalse())
+			})
+		})
+
 		Context("IsRegularPlural", func() {
 			It("should return true if the regular plural form is used", func() {
 				Expect(res.IsRegularPlural()).To(BeTrue())
@@ -194,6 +204,16 @@ var _ = Describe("Resource", func() {
 		})
 	})
 
+	Context("metadata", func() {
+		It("should return the module that provides the API types", func() {
+			Expect(Resource{Module: "github.com/example/apis"}.GetModule()).To(Equal("github.com/example/apis"))
+		})
+
+		It("should return the owner", func() {
+			Expect(Resource{Owner: "crew-team"}.GetOwner()).To(Equal("crew-team"))
+		})
+	})
+
 	Context("Copy", func() {
 		const (
 			path           = "api/v1"
@@ -329,6 +349,43 @@ var _ = Describe("Resource", func() {
 			Expect(r.Update(other)).NotTo(Succeed())
 		})
 
+		It("should work for a new module", func() {
+			const module = "github.com/example/apis"
+			r = Resource{GVK: gvk}
+			other = Resource{
+				GVK:    gvk,
+				Module: module,
+			}
+			Expect(r.Update(other)).To(Succeed())
+			Expect(r.Module).To(Equal(module))
+		})
+
+		It("should fail for different modules", func() {
+			r = Resource{
+				GVK:    gvk,
+				Module: "github.com/example/apis",
+			}
+			other = Resource{
+				GVK:    gvk,
+				Module: "github.com/other/apis",
+			}
+			Expect(r.Update(other)).NotTo(Succeed())
+		})
+
+		It("should set the owner if not previously set", func() {
+			r = Resource{GVK: gvk}
+			other = Resource{
+				GVK:   gvk,
+				Owner: "crew-team",
+			}
+			Expect(r.Update(other)).To(Succeed())
+			Expect(r.Owner).To(Equal("crew-team"))
+
+			other.Owner = "other-team"
+			Expect(r.Update(other)).To(Succeed())
+			Expect(r.Owner).To(Equal("crew-team"))
+		})
+
 		Context("API", func() {
 			It("should work with nil APIs", func() {
 				r = Resource{GVK: gvk}
@@ -37,6 +37,23 @@ func validateAPIVersion(version string) error {
 	}
 }
 
+// appendMissing appends the values that are not present in the slice yet
+func appendMissing(slice []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range slice {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			slice = append(slice, value)
+		}
+	}
+	return slice
+}
+
 // safeImport returns a cleaned version of the provided string that can be used for imports
 func safeImport(unsafe string) string {
 	safe := unsafe
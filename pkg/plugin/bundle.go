@@ -50,6 +50,15 @@ func NewBundle(name string, version Version, plugins ...Plugin) (Bundle, error)
 		}
 	}
 
+	// Plugins that conflict with each other can not be bundled, and bundled plugins run in the declared order
+	if err := validateDependencies(allPlugins, false); err != nil {
+		return nil, fmt.Errorf("unable to bundle plugins: %w", err)
+	}
+	allPlugins, err := sortByDependencies(allPlugins)
+	if err != nil {
+		return nil, fmt.Errorf("unable to bundle plugins: %w", err)
+	}
+
 	return bundle{
 		name:                     name,
 		version:                  version,
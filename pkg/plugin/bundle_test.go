@@ -104,6 +104,35 @@ var _ = Describe("Bundle", func() {
 			Expect(b.Plugins()).To(Equal(plugins))
 		})
 
+		It("should sort the plugins by their declared execution order", func() {
+			first := mockPlugin{name: "first.kubebuilder.io", supportedProjectVersions: p1.supportedProjectVersions}
+			second := mockDependentPlugin{
+				mockPlugin: mockPlugin{
+					name:                     "second.kubebuilder.io",
+					supportedProjectVersions: p1.supportedProjectVersions,
+				},
+				dependencies: Dependencies{RunsAfter: []string{"first.kubebuilder.io"}},
+			}
+
+			b, err := NewBundle(name, version, second, first)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(b.Plugins()).To(Equal([]Plugin{first, second}))
+		})
+
+		It("should fail for conflicting plugins", func() {
+			other := mockPlugin{name: "other.kubebuilder.io", supportedProjectVersions: p1.supportedProjectVersions}
+			conflicting := mockDependentPlugin{
+				mockPlugin: mockPlugin{
+					name:                     "conflicting.kubebuilder.io",
+					supportedProjectVersions: p1.supportedProjectVersions,
+				},
+				dependencies: Dependencies{ConflictsWith: []string{"other.kubebuilder.io"}},
+			}
+
+			_, err := NewBundle(name, version, other, conflicting)
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should fail for plugins with no common supported project version", func() {
 			for _, plugins := range [][]Plugin{
 				{p2, p4},
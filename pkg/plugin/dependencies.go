@@ -0,0 +1,186 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dependencies are the relationships of a plugin with other plugins.
+// Plugins are referenced by their key, whose version may be omitted to match any version, e.g.
+// "kustomize.common.kubebuilder.io/v1" or "go.kubebuilder.io". Bundles are matched both by their own key
+// and by the keys of the plugins they wrap.
+type Dependencies struct {
+	// Requires are the plugins that need to be in the same plugin chain.
+	Requires []string
+	// ConflictsWith are the plugins that can not be in the same plugin chain.
+	ConflictsWith []string
+	// RunsAfter are the plugins whose hooks need to be executed before the ones of this plugin
+	// if they are in the same plugin chain.
+	RunsAfter []string
+}
+
+// HasDependencies is an interface for plugins that declare their relationships with other plugins.
+type HasDependencies interface {
+	// Dependencies returns the relationships of the plugin with other plugins.
+	Dependencies() Dependencies
+}
+
+// ResolveDependencies checks that the requirements and conflicts of the provided plugin chain are satisfied,
+// and returns the plugin chain sorted so that plugins run after the ones they declare, keeping the provided
+// order otherwise. The plugins wrapped by a bundle are kept together.
+func ResolveDependencies(plugins []Plugin) ([]Plugin, error) {
+	if err := validateDependencies(plugins, true); err != nil {
+		return nil, err
+	}
+	return sortByDependencies(plugins)
+}
+
+// validateDependencies checks that no plugin conflicts with another one and, optionally, that the required
+// plugins are present.
+func validateDependencies(plugins []Plugin, checkRequirements bool) error {
+	for _, p := range unbundle(plugins) {
+		deps := dependenciesOf(p)
+
+		for _, key := range deps.ConflictsWith {
+			for _, other := range plugins {
+				if matchesOther(other, key, p) {
+					return fmt.Errorf("plugin %q conflicts with plugin %q", KeyFor(p), KeyFor(other))
+				}
+			}
+		}
+
+		if !checkRequirements {
+			continue
+		}
+		for _, key := range deps.Requires {
+			found := false
+			for _, other := range plugins {
+				if matchesOther(other, key, p) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("plugin %q requires a plugin matching %q in the plugin chain", KeyFor(p), key)
+			}
+		}
+	}
+	return nil
+}
+
+// sortByDependencies sorts the plugins so that each plugin runs after the ones it declares in RunsAfter,
+// keeping the provided order otherwise.
+func sortByDependencies(plugins []Plugin) ([]Plugin, error) {
+	// after[i] are the indexes of the plugins that need to run before the i-th plugin
+	after := make([][]int, len(plugins))
+	for i, p := range plugins {
+		for _, member := range unbundle([]Plugin{p}) {
+			for _, key := range dependenciesOf(member).RunsAfter {
+				for j, other := range plugins {
+					if i != j && matchesOther(other, key, member) {
+						after[i] = append(after[i], j)
+					}
+				}
+			}
+		}
+	}
+
+	sorted := make([]Plugin, 0, len(plugins))
+	placed := make([]bool, len(plugins))
+	for len(sorted) < len(plugins) {
+		next := -1
+		for i := range plugins {
+			if !placed[i] && allPlaced(after[i], placed) {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			pending := make([]string, 0, len(plugins)-len(sorted))
+			for i, p := range plugins {
+				if !placed[i] {
+					pending = append(pending, fmt.Sprintf("%q", KeyFor(p)))
+				}
+			}
+			return nil, fmt.Errorf("plugins %s declare a circular execution order", strings.Join(pending, ", "))
+		}
+
+		placed[next] = true
+		sorted = append(sorted, plugins[next])
+	}
+	return sorted, nil
+}
+
+// dependenciesOf returns the dependencies declared by a plugin, if any.
+func dependenciesOf(p Plugin) Dependencies {
+	if p, hasDependencies := p.(HasDependencies); hasDependencies {
+		return p.Dependencies()
+	}
+	return Dependencies{}
+}
+
+// matchesOther checks if the plugin, or any of the plugins it wraps if it is a bundle, matches the provided key
+// without taking into account the plugin that declares the dependency or the bundle that wraps it.
+func matchesOther(p Plugin, key string, self Plugin) bool {
+	selfKey := KeyFor(self)
+
+	bundle, isBundle := p.(Bundle)
+	if !isBundle {
+		return KeyFor(p) != selfKey && matches(p, key)
+	}
+
+	wrapsSelf := false
+	for _, member := range bundle.Plugins() {
+		if KeyFor(member) == selfKey {
+			wrapsSelf = true
+		} else if matches(member, key) {
+			return true
+		}
+	}
+	return !wrapsSelf && matches(p, key)
+}
+
+// matches checks if the plugin has the name and, if provided, the version of the key.
+func matches(p Plugin, key string) bool {
+	name, version := SplitKey(key)
+	return p.Name() == name && (version == "" || p.Version().String() == version)
+}
+
+// unbundle returns the plugins replacing bundles by the plugins they wrap.
+func unbundle(plugins []Plugin) []Plugin {
+	unbundled := make([]Plugin, 0, len(plugins))
+	for _, p := range plugins {
+		if bundle, isBundle := p.(Bundle); isBundle {
+			unbundled = append(unbundled, bundle.Plugins()...)
+		} else {
+			unbundled = append(unbundled, p)
+		}
+	}
+	return unbundled
+}
+
+// allPlaced checks if every index has been placed.
+func allPlaced(indexes []int, placed []bool) bool {
+	for _, i := range indexes {
+		if !placed[i] {
+			return false
+		}
+	}
+	return true
+}
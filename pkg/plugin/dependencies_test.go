@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+)
+
+var _ = Describe("ResolveDependencies", func() {
+	var (
+		projectVersions = []config.Version{{Number: 3}}
+
+		newPlugin = func(name string, number int, deps Dependencies) Plugin {
+			return mockDependentPlugin{
+				mockPlugin: mockPlugin{
+					name:                     name,
+					version:                  Version{Number: number},
+					supportedProjectVersions: projectVersions,
+				},
+				dependencies: deps,
+			}
+		}
+
+		goV2 = newPlugin("go.kubebuilder.io", 2, Dependencies{})
+		goV3 = newPlugin("base.go.kubebuilder.io", 3, Dependencies{
+			ConflictsWith: []string{"go.kubebuilder.io/v2"},
+		})
+		kustomize = newPlugin("kustomize.common.kubebuilder.io", 1, Dependencies{})
+		addon     = newPlugin("declarative.go.kubebuilder.io", 1, Dependencies{
+			Requires:  []string{"go.kubebuilder.io"},
+			RunsAfter: []string{"go.kubebuilder.io"},
+		})
+		docs = newPlugin("docs.kubebuilder.io", 1, Dependencies{
+			Requires:  []string{"kustomize.common.kubebuilder.io/v1"},
+			RunsAfter: []string{"declarative.go.kubebuilder.io", "kustomize.common.kubebuilder.io"},
+		})
+
+		goV3Bundle Bundle
+	)
+
+	BeforeEach(func() {
+		var err error
+		goV3Bundle, err = NewBundle("go.kubebuilder.io", Version{Number: 3}, kustomize, goV3)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("should keep the order of plugins without declared execution order",
+		func(plugins func() []Plugin) {
+			Expect(ResolveDependencies(plugins())).To(Equal(plugins()))
+		},
+		Entry("for a single plugin", func() []Plugin { return []Plugin{goV2} }),
+		Entry("for independent plugins", func() []Plugin { return []Plugin{goV3, kustomize} }),
+		Entry("for independent plugins in any order", func() []Plugin { return []Plugin{kustomize, goV3} }),
+		Entry("for plugins already in order", func() []Plugin { return []Plugin{goV2, addon} }),
+		Entry("for a bundle", func() []Plugin { return []Plugin{goV3Bundle, addon} }),
+	)
+
+	It("should sort the plugins after the ones they run after", func() {
+		Expect(ResolveDependencies([]Plugin{addon, goV2})).To(Equal([]Plugin{goV2, addon}))
+		Expect(ResolveDependencies([]Plugin{docs, addon, goV3Bundle})).To(Equal([]Plugin{goV3Bundle, addon, docs}))
+	})
+
+	It("should match bundles by the plugins they wrap", func() {
+		Expect(ResolveDependencies([]Plugin{docs, goV3Bundle})).To(Equal([]Plugin{goV3Bundle, docs}))
+	})
+
+	DescribeTable("should fail",
+		func(plugins func() []Plugin, message string) {
+			_, err := ResolveDependencies(plugins())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(message))
+		},
+		Entry("if a required plugin is missing",
+			func() []Plugin { return []Plugin{addon} },
+			`plugin "declarative.go.kubebuilder.io/v1" requires a plugin matching "go.kubebuilder.io"`),
+		Entry("if a required plugin has a different version",
+			func() []Plugin {
+				return []Plugin{goV2, newPlugin("kustomize.common.kubebuilder.io", 2, Dependencies{}), docs}
+			},
+			`plugin "docs.kubebuilder.io/v1" requires a plugin matching "kustomize.common.kubebuilder.io/v1"`),
+		Entry("if a plugin conflicts with another one",
+			func() []Plugin { return []Plugin{goV2, goV3} },
+			`plugin "base.go.kubebuilder.io/v3" conflicts with plugin "go.kubebuilder.io/v2"`),
+		Entry("if a plugin conflicts with another one that runs before",
+			func() []Plugin { return []Plugin{goV3Bundle, goV2} },
+			`plugin "base.go.kubebuilder.io/v3" conflicts with plugin "go.kubebuilder.io/v2"`),
+		Entry("if the execution order is circular",
+			func() []Plugin {
+				return []Plugin{
+					newPlugin("a.kubebuilder.io", 1, Dependencies{RunsAfter: []string{"b.kubebuilder.io"}}),
+					newPlugin("b.kubebuilder.io", 1, Dependencies{RunsAfter: []string{"a.kubebuilder.io"}}),
+				}
+			},
+			`plugins "a.kubebuilder.io/v1", "b.kubebuilder.io/v1" declare a circular execution order`),
+	)
+})
@@ -28,6 +28,7 @@ type PluginRequest struct {
 	Args []string `json:"args"`
 
 	// Command contains the command to be executed by the plugin such as init, create api, etc.
+	// The metadata command is sent when the plugin is discovered, to obtain the project versions it supports.
 	Command string `json:"command"`
 
 	// Universe represents the modified file contents that gets updated over a series of plugin runs
@@ -52,6 +53,14 @@ type PluginResponse struct {
 	// Universe in the PluginResponse represents the updated file contents that was written by the plugin.
 	Universe map[string]string `json:"universe"`
 
+	// SupportedProjectVersions are the project versions supported by the plugin, e.g. "3".
+	// They are only returned in response to the metadata command.
+	SupportedProjectVersions []string `json:"supportedProjectVersions,omitempty"`
+
+	// RemovedFiles are the paths of the files that were removed by the plugin, relative to the project root.
+	// Files that are not part of the Universe are kept unless they are listed here.
+	RemovedFiles []string `json:"removedFiles,omitempty"`
+
 	// Error is a boolean type that indicates whether there were any errors due to plugin failures.
 	Error bool `json:"error,omitempty"`
 
@@ -22,13 +22,14 @@ import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 )
 
-// FilterPluginsByKey returns the set of plugins that match the provided key (may be not-fully qualified)
+// FilterPluginsByKey returns the set of plugins that match the provided key (may be not-fully qualified).
+// The version of the key may be a version range (see VersionRange).
 func FilterPluginsByKey(plugins []Plugin, key string) ([]Plugin, error) {
 	name, ver := SplitKey(key)
 	hasVersion := ver != ""
-	var version Version
+	var versionRange VersionRange
 	if hasVersion {
-		if err := version.Parse(ver); err != nil {
+		if err := versionRange.Parse(ver); err != nil {
 			return nil, err
 		}
 	}
@@ -38,7 +39,7 @@ func FilterPluginsByKey(plugins []Plugin, key string) ([]Plugin, error) {
 		if !strings.HasPrefix(plugin.Name(), name) {
 			continue
 		}
-		if hasVersion && plugin.Version().Compare(version) != 0 {
+		if hasVersion && !versionRange.Contains(plugin.Version()) {
 			continue
 		}
 		filtered = append(filtered, plugin)
@@ -46,6 +47,35 @@ func FilterPluginsByKey(plugins []Plugin, key string) ([]Plugin, error) {
 	return filtered, nil
 }
 
+// FilterPreferredVersions returns, for each plugin name, the plugin with the highest stable version,
+// or with the highest version if none of them is stable, keeping the provided order.
+func FilterPreferredVersions(plugins []Plugin) []Plugin {
+	preferred := make(map[string]Plugin, len(plugins))
+	for _, plugin := range plugins {
+		current, found := preferred[plugin.Name()]
+		if !found || isPreferredVersion(plugin.Version(), current.Version()) {
+			preferred[plugin.Name()] = plugin
+		}
+	}
+
+	filtered := make([]Plugin, 0, len(preferred))
+	for _, plugin := range plugins {
+		if preferred[plugin.Name()].Version().Compare(plugin.Version()) == 0 {
+			filtered = append(filtered, plugin)
+		}
+	}
+	return filtered
+}
+
+// isPreferredVersion checks if v is preferred over other, i.e., if it is stable and other is not,
+// or if both have the same stability and v is higher.
+func isPreferredVersion(v, other Version) bool {
+	if v.IsStable() != other.IsStable() {
+		return v.IsStable()
+	}
+	return v.Compare(other) > 0
+}
+
 // FilterPluginsByProjectVersion returns the set of plugins that support the provided project version
 func FilterPluginsByProjectVersion(plugins []Plugin, projectVersion config.Version) []Plugin {
 	filtered := make([]Plugin, 0, len(plugins))
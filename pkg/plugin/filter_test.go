@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/stage"
 )
 
 var (
@@ -65,14 +66,39 @@ var _ = Describe("FilterPluginsByKey", func() {
 		Entry("go plugins (kubebuilder domain)", "go.kubebuilder", []Plugin{p1, p2}),
 		Entry("go v2 plugins", "go/v2", []Plugin{p1, p5}),
 		Entry("go v2 plugins (kubebuilder domain)", "go.kubebuilder/v2", []Plugin{p1}),
+		Entry("go v3+ plugins", "go/v3+", []Plugin{p2}),
+		Entry("go >=v2 plugins", "go/>=v2", []Plugin{p1, p2, p5}),
+		Entry("latest go plugins", "go/latest", []Plugin{p1, p2, p5}),
 	)
 
 	It("should fail for invalid versions", func() {
 		_, err := FilterPluginsByKey(allPlugins, "go/a")
 		Expect(err).To(HaveOccurred())
+		_, err = FilterPluginsByKey(allPlugins, "go/a+")
+		Expect(err).To(HaveOccurred())
 	})
 })
 
+var _ = Describe("FilterPreferredVersions", func() {
+	var (
+		p2Alpha = mockPlugin{name: "go.kubebuilder.io", version: Version{Number: 2, Stage: stage.Alpha}}
+		p4Alpha = mockPlugin{name: "go.kubebuilder.io", version: Version{Number: 4, Stage: stage.Alpha}}
+		p1Beta  = mockPlugin{name: "example.kubebuilder.io", version: Version{Number: 1, Stage: stage.Beta}}
+		p2Beta  = mockPlugin{name: "example.kubebuilder.io", version: Version{Number: 2, Stage: stage.Beta}}
+	)
+
+	DescribeTable("should keep the preferred version of each plugin",
+		func(plugins, preferred []Plugin) {
+			Expect(FilterPreferredVersions(plugins)).To(Equal(preferred))
+		},
+		Entry("for a single plugin", []Plugin{p1}, []Plugin{p1}),
+		Entry("for the highest stable version", []Plugin{p2, p1}, []Plugin{p2}),
+		Entry("for a stable version over an unstable one", []Plugin{p2Alpha, p4Alpha, p2, p1}, []Plugin{p2}),
+		Entry("for the highest unstable version", []Plugin{p1Beta, p2Beta}, []Plugin{p2Beta}),
+		Entry("for several plugins", []Plugin{p1, p1Beta, p2, p2Beta, p5}, []Plugin{p2, p2Beta, p5}),
+	)
+})
+
 var _ = Describe("FilterPluginsByKey", func() {
 	DescribeTable("should filter",
 		func(projectVersion config.Version, plugins []Plugin) {
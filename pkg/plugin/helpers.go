@@ -72,9 +72,9 @@ func ValidateKey(key string) error {
 	if err := validateName(name); err != nil {
 		return fmt.Errorf("invalid plugin name %q: %v", name, err)
 	}
-	// CLI-set plugins do not have to contain a version.
+	// CLI-set plugins do not have to contain a version, and it may be a version range.
 	if version != "" {
-		var v Version
+		var v VersionRange
 		if err := v.Parse(version); err != nil {
 			return fmt.Errorf("invalid plugin version %q: %v", version, err)
 		}
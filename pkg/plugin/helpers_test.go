@@ -113,12 +113,22 @@ var _ = Describe("ValidateKey", func() {
 		Expect(ValidateKey(key)).To(Succeed())
 	})
 
+	DescribeTable("should succeed for version ranges",
+		func(key string) {
+			Expect(ValidateKey(key)).To(Succeed())
+		},
+		Entry("for minimum versions with suffix", "go.kubebuilder.io/v3+"),
+		Entry("for minimum versions with prefix", "go.kubebuilder.io/>=v3-alpha"),
+		Entry("for latest versions", "go.kubebuilder.io/latest"),
+	)
+
 	DescribeTable("should fail",
 		func(key string) {
 			Expect(ValidateKey(key)).NotTo(Succeed())
 		},
 		Entry("for invalid plugin names", "go_kubebuilder.io/v1"),
 		Entry("for invalid versions", "go.kubebuilder.io/a"),
+		Entry("for invalid version ranges", "go.kubebuilder.io/>=a"),
 	)
 })
 
@@ -18,6 +18,7 @@ package plugin
 
 import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 )
 
 // Plugin is an interface that defines the common base for all plugins.
@@ -41,6 +42,22 @@ type Deprecated interface {
 	DeprecationWarning() string
 }
 
+// HasConfigSchema is an interface for plugins that store their configuration in the project configuration file.
+type HasConfigSchema interface {
+	Plugin
+	// ConfigSchema returns the JSON Schema of the configuration stored by the plugin under its key in the plugins
+	// field, which is used to validate the project configuration file.
+	ConfigSchema() config.Schema
+}
+
+// HasScaffoldedFiles is an interface for plugins that can list the files they scaffold for a resource.
+type HasScaffoldedFiles interface {
+	Plugin
+	// ScaffoldedFiles returns the paths, relative to the project root, of the files scaffolded by the plugin for the
+	// resource, which are used to check that the resources in the project configuration file exist.
+	ScaffoldedFiles(res resource.Resource, multiGroup bool) []string
+}
+
 // Init is an interface for plugins that provide an `init` subcommand.
 type Init interface {
 	Plugin
@@ -69,6 +86,37 @@ type Edit interface {
 	GetEditSubcommand() EditSubcommand
 }
 
+// DeleteAPI is an interface for plugins that provide a `delete api` subcommand.
+type DeleteAPI interface {
+	Plugin
+	// GetDeleteAPISubcommand returns the underlying DeleteAPISubcommand interface.
+	GetDeleteAPISubcommand() DeleteAPISubcommand
+}
+
+// DeleteWebhook is an interface for plugins that provide a `delete webhook` subcommand.
+type DeleteWebhook interface {
+	Plugin
+	// GetDeleteWebhookSubcommand returns the underlying DeleteWebhookSubcommand interface.
+	GetDeleteWebhookSubcommand() DeleteWebhookSubcommand
+}
+
+// Custom is an interface for plugins that provide subcommands other than the ones above,
+// e.g. `create dashboard` or `generate docs`.
+type Custom interface {
+	Plugin
+	// GetCustomSubcommands returns the custom subcommands provided by the plugin.
+	GetCustomSubcommands() []CustomSubcommand
+}
+
+// Migrator is an interface for plugins that provide an `alpha migrate` subcommand, which migrates projects
+// scaffolded with other plugins, e.g. a previous version of the same plugin.
+type Migrator interface {
+	Plugin
+	// MigrateFrom returns the subcommand that migrates a project scaffolded with the plugin with the provided key,
+	// or nil if the plugin can not migrate such projects.
+	MigrateFrom(previousKey string) MigrateSubcommand
+}
+
 // Full is an interface for plugins that provide `init`, `create api`, `create webhook` and `edit` subcommands.
 type Full interface {
 	Init
@@ -17,11 +17,14 @@ limitations under the License.
 package plugin
 
 import (
+	"context"
+
 	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 // UpdatesMetadata is an interface that implements the optional metadata update method.
@@ -36,12 +39,30 @@ type HasFlags interface {
 	BindFlags(*pflag.FlagSet)
 }
 
+// SharesFlags is an interface that implements the optional shared flags method.
+//
+// Flags bound in BindFlags can always be provided prefixed by the short name of the plugin, e.g. `--go.force`,
+// and also without prefix, e.g. `--force`, as long as no other plugin binds a flag with the same name.
+// Shared flags can be provided without prefix even if other plugins bind them, as long as all of them share them,
+// in which case the value is set for all of them.
+type SharesFlags interface {
+	// SharedFlags returns the names of the flags that are shared with other plugins.
+	SharedFlags() []string
+}
+
 // RequiresConfig is an interface that implements the optional inject config method.
 type RequiresConfig interface {
 	// InjectConfig injects the configuration to a subcommand.
 	InjectConfig(config.Config) error
 }
 
+// RequiresEnvironment is an interface that implements the optional inject environment method.
+type RequiresEnvironment interface {
+	// InjectEnvironment injects the environment of the execution to a subcommand.
+	// It is injected before the configuration and is also carried by the context of the context-aware hooks.
+	InjectEnvironment(*util.Environment) error
+}
+
 // RequiresResource is an interface that implements the required inject resource method.
 type RequiresResource interface {
 	// InjectResource injects the resource model to a subcommand.
@@ -66,6 +87,44 @@ type HasPostScaffold interface {
 	PostScaffold() error
 }
 
+// HasPreScaffoldWithContext is an interface that implements the optional context-aware pre-scaffold method.
+// It is called instead of PreScaffold if both are implemented.
+type HasPreScaffoldWithContext interface {
+	// PreScaffoldWithContext executes tasks before the main scaffolding.
+	// The context is done when the execution is cancelled, e.g. on an interrupt signal.
+	PreScaffoldWithContext(context.Context, machinery.Filesystem) error
+}
+
+// ScaffolderWithContext is an interface that implements the optional context-aware scaffold method.
+// It is called instead of Scaffold if both are implemented.
+type ScaffolderWithContext interface {
+	// ScaffoldWithContext implements the main scaffolding.
+	// The context is done when the execution is cancelled, e.g. on an interrupt signal.
+	ScaffoldWithContext(context.Context, machinery.Filesystem) error
+}
+
+// HasPostScaffoldWithContext is an interface that implements the optional context-aware post-scaffold method.
+// It is called instead of PostScaffold if both are implemented.
+type HasPostScaffoldWithContext interface {
+	// PostScaffoldWithContext executes tasks after the main scaffolding.
+	// The context is done when the execution is cancelled, e.g. on an interrupt signal.
+	PostScaffoldWithContext(context.Context) error
+}
+
+// RequiresReview is an interface that implements the optional files to review method.
+type RequiresReview interface {
+	// FilesToReview returns the files that need to be reviewed manually once every hook has been executed.
+	FilesToReview() []FileToReview
+}
+
+// FileToReview is a file that needs to be reviewed manually, e.g. because it could not be updated automatically.
+type FileToReview struct {
+	// Path is the path of the file.
+	Path string
+	// Reason describes why the file needs to be reviewed and what may need to be changed.
+	Reason string
+}
+
 // Subcommand is a base interface for all subcommands.
 type Subcommand interface {
 	Scaffolder
@@ -92,3 +151,36 @@ type CreateWebhookSubcommand interface {
 type EditSubcommand interface {
 	Subcommand
 }
+
+// DeleteAPISubcommand is an interface that represents a `delete api` subcommand.
+type DeleteAPISubcommand interface {
+	Subcommand
+	RequiresResource
+}
+
+// DeleteWebhookSubcommand is an interface that represents a `delete webhook` subcommand.
+type DeleteWebhookSubcommand interface {
+	Subcommand
+	RequiresResource
+}
+
+// MigrateSubcommand is an interface that represents an `alpha migrate` subcommand.
+// The project configuration injected to it has already been converted to the project version the plugins
+// are migrated to, and its plugin chain set to their keys.
+type MigrateSubcommand interface {
+	Subcommand
+}
+
+// CustomSubcommand represents a subcommand provided by a Custom plugin.
+// Custom subcommands go through the same hooks as the rest of subcommands and require an initialized project.
+// If the subcommand implements RequiresResource, the resource flags are bound to it.
+type CustomSubcommand struct {
+	// Path is the space-separated list of names from the root command to the subcommand, e.g. "generate docs".
+	// Missing parent commands are created. Several plugins may provide a subcommand with the same path,
+	// in which case their hooks are called in the order of the plugin chain.
+	Path string
+	// Short is the short description of the subcommand shown in the help of its parent command.
+	Short string
+	// Subcommand is the subcommand that implements the hooks.
+	Subcommand Subcommand
+}
@@ -39,3 +39,10 @@ type mockPlugin struct {
 func (p mockPlugin) Name() string                               { return p.name }
 func (p mockPlugin) Version() Version                           { return p.version }
 func (p mockPlugin) SupportedProjectVersions() []config.Version { return p.supportedProjectVersions }
+
+type mockDependentPlugin struct {
+	mockPlugin
+	dependencies Dependencies
+}
+
+func (p mockDependentPlugin) Dependencies() Dependencies { return p.dependencies }
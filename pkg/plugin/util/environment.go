@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+)
+
+// Environment is the environment plugins are executed in, i.e., where and how their commands are run
+// and where their messages and prompts are written to and read from.
+//
+// Each execution of a CLI hands its own Environment to the plugins, through the context of the context-aware hooks,
+// the Filesystem of the scaffolding hooks and the RequiresEnvironment hook, so executions do not affect each other.
+// A nil Environment is the one of the process.
+type Environment struct {
+	// Dir is the working directory of the plugins. The one of the process is used if empty.
+	Dir string
+	// Env are environment variables, in the "KEY=value" form, set for the commands on top of the environment
+	// of the process.
+	Env []string
+	// Stdin is where the answers to prompts are read from. The standard input of the process is used if nil.
+	Stdin io.Reader
+	// Stdout and Stderr receive the messages printed by plugins and the output of the commands that do not
+	// capture it. The standard output and error of the process are used if nil.
+	Stdout, Stderr io.Writer
+
+	// Runner runs the commands of the plugins. ExecRunner is used if nil.
+	Runner Runner
+	// DryRun makes RunCmd only print the commands instead of executing them.
+	DryRun bool
+	// NonInteractive makes every prompt fail instead of waiting for the user's answer.
+	NonInteractive bool
+	// AssumeYes answers every confirmation prompt affirmatively.
+	AssumeYes bool
+	// CommandObserver, if set, is notified of every command run by RunCmd, including its error if any.
+	// Commands that are skipped in dry-run mode are also notified.
+	CommandObserver func(msg string, args []string, err error)
+
+	// stdinReader buffers Stdin so that consecutive prompts do not lose input.
+	stdinReader *bufio.Reader
+}
+
+// environmentKey is the key of the Environment in a context.
+type environmentKey struct{}
+
+// WithEnvironment returns a copy of ctx that carries env, which is used by Run, Output and RunCmdWithContext.
+func WithEnvironment(ctx context.Context, env *Environment) context.Context {
+	return context.WithValue(ctx, environmentKey{}, env)
+}
+
+// EnvironmentFrom returns the Environment carried by ctx, or nil, i.e. the one of the process, if there is none.
+func EnvironmentFrom(ctx context.Context) *Environment {
+	env, _ := ctx.Value(environmentKey{}).(*Environment)
+	return env
+}
+
+// Getwd returns the working directory of the plugins.
+func (env *Environment) Getwd() (string, error) {
+	if env != nil && env.Dir != "" {
+		return env.Dir, nil
+	}
+	return os.Getwd()
+}
+
+// OutOrStdout returns the writer plugins print their messages to.
+func (env *Environment) OutOrStdout() io.Writer {
+	if env != nil && env.Stdout != nil {
+		return env.Stdout
+	}
+	return os.Stdout
+}
+
+// ErrOrStderr returns the writer plugins print their errors to.
+func (env *Environment) ErrOrStderr() io.Writer {
+	if env != nil && env.Stderr != nil {
+		return env.Stderr
+	}
+	return os.Stderr
+}
+
+// InOrStdin returns the reader the answers to prompts are read from.
+func (env *Environment) InOrStdin() io.Reader {
+	if env != nil && env.Stdin != nil {
+		return env.Stdin
+	}
+	return os.Stdin
+}
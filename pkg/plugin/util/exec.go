@@ -17,17 +17,34 @@ limitations under the License.
 package util
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"strings"
 )
 
-// RunCmd prints the provided message and command and then executes it binding stdout and stderr
+// RunCmd prints the provided message and command and then executes it with the default ExecRunner,
+// which binds the stdout and stderr of the process
 func RunCmd(msg, cmd string, args ...string) error {
-	c := exec.Command(cmd, args...) //nolint:gosec
-	c.Stdout = os.Stdout
-	c.Stderr = os.Stderr
-	fmt.Println(msg + ":\n$ " + strings.Join(c.Args, " "))
-	return c.Run()
+	return RunCmdWithContext(context.Background(), msg, cmd, args...)
+}
+
+// RunCmdWithContext is like RunCmd but the command is run in the Environment carried by ctx, and stopped if ctx is
+// done before it finishes.
+func RunCmdWithContext(ctx context.Context, msg, cmd string, args ...string) error {
+	return EnvironmentFrom(ctx).RunCmd(ctx, msg, cmd, args...)
+}
+
+// RunCmd prints the provided message and command to the standard output of the Environment and then executes it
+// with its Runner, unless it is in dry-run mode. The command is stopped if ctx is done before it finishes.
+func (env *Environment) RunCmd(ctx context.Context, msg, cmd string, args ...string) (err error) {
+	c := Command{Name: cmd, Args: args}
+	fmt.Fprintln(env.OutOrStdout(), msg+":\n$ "+c.String())
+	if env != nil && env.CommandObserver != nil {
+		observer := env.CommandObserver
+		defer func() { observer(msg, append([]string{cmd}, args...), err) }()
+	}
+	if env != nil && env.DryRun {
+		fmt.Fprintln(env.OutOrStdout(), "Skipping command execution in dry-run mode.")
+		return nil
+	}
+	return env.Run(ctx, c)
 }
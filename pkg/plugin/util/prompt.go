@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// stdinIsTerminal checks if the answers can be provided by a user.
+var stdinIsTerminal = isTerminal
+
+// PromptError is returned when the answer to a question cannot be obtained from the user.
+type PromptError struct {
+	// Question is the question that needed to be answered.
+	Question string
+	// Flag is the name of the flag that provides the answer without prompting.
+	Flag string
+	// Reason describes why the user could not be prompted.
+	Reason string
+}
+
+// Error implements error interface
+func (e PromptError) Error() string {
+	return fmt.Sprintf("unable to ask %q (%s), provide the answer with the --%s flag", e.Question, e.Reason, e.Flag)
+}
+
+// Confirm asks the user a yes/no question and returns true if the answer was "y" or "yes".
+// The flag is the name of the flag that provides the answer without prompting, which is mentioned in the error
+// returned when the user cannot be prompted: in non-interactive mode, or if stdin is a file that is not a terminal.
+// The question is printed to the standard output of the Environment and the answer read from its standard input.
+func (env *Environment) Confirm(question, flag string) (bool, error) {
+	if env != nil && env.AssumeYes {
+		return true, nil
+	}
+
+	if env != nil && env.NonInteractive {
+		return false, PromptError{Question: question, Flag: flag, Reason: "running in non-interactive mode"}
+	}
+	if !stdinIsTerminal(env.InOrStdin()) {
+		return false, PromptError{Question: question, Flag: flag, Reason: "stdin is not a terminal"}
+	}
+
+	stdinReader := bufio.NewReader(env.InOrStdin())
+	if env != nil {
+		if env.stdinReader == nil {
+			env.stdinReader = stdinReader
+		}
+		stdinReader = env.stdinReader
+	}
+
+	fmt.Fprintln(env.OutOrStdout(), question+" [y/n]")
+	for {
+		text, err := stdinReader.ReadString('\n')
+		if err != nil && (err != io.EOF || text == "") {
+			reason := fmt.Sprintf("error reading the answer: %v", err)
+			return false, PromptError{Question: question, Flag: flag, Reason: reason}
+		}
+
+		switch answer := strings.TrimSpace(text); answer {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		default:
+			fmt.Fprintf(env.OutOrStdout(), "invalid input %q, should be [y/n]\n", answer)
+		}
+	}
+}
+
+// isTerminal checks if r can be answered by a user. Files, e.g. the standard input of the process, need to be
+// attached to a terminal, while other readers are provided programmatically.
+func isTerminal(r io.Reader) bool {
+	f, isFile := r.(*os.File)
+	if !isFile {
+		return true
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
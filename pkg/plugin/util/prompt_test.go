@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Confirm", func() {
+	const (
+		question = "Create Resource"
+		flag     = "resource"
+	)
+
+	var (
+		terminal bool
+		env      *Environment
+	)
+
+	BeforeEach(func() {
+		terminal = true
+		stdinIsTerminal = func(io.Reader) bool { return terminal }
+		env = &Environment{Stdin: strings.NewReader(""), Stdout: io.Discard}
+	})
+
+	AfterEach(func() {
+		stdinIsTerminal = isTerminal
+	})
+
+	setInput := func(input string) {
+		env.Stdin = strings.NewReader(input)
+	}
+
+	DescribeTable("should return the user's answer",
+		func(input string, expected bool) {
+			setInput(input)
+
+			Expect(env.Confirm(question, flag)).To(Equal(expected))
+		},
+		Entry("for y", "y\n", true),
+		Entry("for yes", "yes\n", true),
+		Entry("for n", "n\n", false),
+		Entry("for no", "no\n", false),
+		Entry("surrounded by spaces", "  yes  \n", true),
+		Entry("after an invalid answer", "maybe\nn\n", false),
+		Entry("without a trailing new line", "y", true),
+	)
+
+	It("should keep the remaining input for the following prompts", func() {
+		setInput("y\nn\n")
+
+		Expect(env.Confirm(question, flag)).To(BeTrue())
+		Expect(env.Confirm(question, flag)).To(BeFalse())
+	})
+
+	It("should answer affirmatively without reading in yes mode", func() {
+		env.AssumeYes = true
+		env.NonInteractive = true
+
+		Expect(env.Confirm(question, flag)).To(BeTrue())
+	})
+
+	It("should read the answers from a standard input that is not a file", func() {
+		stdinIsTerminal = isTerminal
+		setInput("y\n")
+
+		Expect(env.Confirm(question, flag)).To(BeTrue())
+	})
+
+	DescribeTable("should fail naming the flag",
+		func(setup func(), reason string) {
+			setup()
+
+			_, err := env.Confirm(question, flag)
+			Expect(err).To(HaveOccurred())
+			var promptErr PromptError
+			Expect(errors.As(err, &promptErr)).To(BeTrue())
+			Expect(promptErr.Question).To(Equal(question))
+			Expect(promptErr.Flag).To(Equal(flag))
+			Expect(promptErr.Reason).To(ContainSubstring(reason))
+			Expect(err.Error()).To(ContainSubstring("--" + flag))
+		},
+		Entry("in non-interactive mode", func() { env.NonInteractive = true }, "non-interactive"),
+		Entry("if stdin is not a terminal", func() { terminal = false }, "not a terminal"),
+		Entry("if there is no answer", func() { setInput("") }, "EOF"),
+		Entry("if there is no valid answer", func() { setInput("maybe\n") }, "EOF"),
+	)
+})
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Command is a command run on behalf of a plugin, e.g. `go mod tidy` or `make generate`.
+type Command struct {
+	// Name is the executable of the command, e.g. "go".
+	Name string
+	// Args are the arguments of the command.
+	Args []string
+	// Dir is the working directory of the command, if any.
+	// Run, RunCmd and Output set it to the one of the Environment.
+	Dir string
+	// Env are environment variables, in the "KEY=value" form, set for the command on top of the environment
+	// of the process. Run, RunCmd and Output prepend the ones of the Environment.
+	Env []string
+	// Stdin is the standard input of the command, if any.
+	Stdin io.Reader
+	// Stdout and Stderr capture the output of the command if set.
+	// Otherwise, the Runner decides where the output is written.
+	Stdout, Stderr io.Writer
+}
+
+// String returns the command line of the command.
+func (c Command) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// Runner runs the commands of the plugins.
+//
+// It can be replaced with the Runner of the Environment, e.g. to stub the commands in tests, to record them,
+// or to substitute them with offline or vendored alternatives.
+type Runner interface {
+	// Run runs the command, stopping it if ctx is done before it finishes.
+	// The Environment the command is run in is carried by ctx.
+	Run(ctx context.Context, cmd Command) error
+}
+
+// ExecRunner is the default Runner, which runs the commands as processes.
+type ExecRunner struct {
+	// Dir is the working directory of the commands that do not set one. The current one is used if empty.
+	Dir string
+	// Env are environment variables, in the "KEY=value" form, set on top of the environment of the process
+	// and below the ones of each command.
+	Env []string
+	// Timeout is the maximum duration of each command. Commands are not limited if zero.
+	Timeout time.Duration
+	// Stdout and Stderr receive the output of the commands that do not capture it.
+	// The standard output and error of the Environment carried by the context are used if nil.
+	Stdout, Stderr io.Writer
+}
+
+// Run implements Runner.
+func (r ExecRunner) Run(ctx context.Context, cmd Command) error {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	c := exec.CommandContext(ctx, cmd.Name, cmd.Args...) //nolint:gosec
+	c.Dir = r.Dir
+	if cmd.Dir != "" {
+		c.Dir = cmd.Dir
+	}
+	if len(r.Env) != 0 || len(cmd.Env) != 0 {
+		c.Env = append(append(os.Environ(), r.Env...), cmd.Env...)
+	}
+	c.Stdin = cmd.Stdin
+	env := EnvironmentFrom(ctx)
+	c.Stdout = firstWriter(cmd.Stdout, r.Stdout, env.OutOrStdout())
+	c.Stderr = firstWriter(cmd.Stderr, r.Stderr, env.ErrOrStderr())
+
+	if err := c.Run(); err != nil {
+		// Report the cancellation instead of the signal that killed the command
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
+
+// firstWriter returns the first non-nil writer.
+func firstWriter(writers ...io.Writer) io.Writer {
+	for _, w := range writers {
+		if w != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+// Run runs the command with the Runner of the Environment, e.g. to execute a program with a given standard input.
+// Like Output, the command is neither printed nor skipped in dry-run mode.
+// It is run from the working directory and with the environment variables of the Environment.
+func (env *Environment) Run(ctx context.Context, cmd Command) error {
+	var runner Runner = ExecRunner{}
+	if env != nil {
+		if cmd.Dir == "" {
+			cmd.Dir = env.Dir
+		}
+		if len(env.Env) != 0 {
+			cmd.Env = append(append([]string{}, env.Env...), cmd.Env...)
+		}
+		if env.Runner != nil {
+			runner = env.Runner
+		}
+	}
+	if EnvironmentFrom(ctx) != env {
+		ctx = WithEnvironment(ctx, env)
+	}
+	return runner.Run(ctx, cmd)
+}
+
+// Output runs the command with the Runner of the Environment and returns its standard output.
+// Unlike RunCmd, the command is neither printed nor skipped in dry-run mode, so it is meant for commands
+// that only gather information, e.g. `go version`. The standard error is included in the returned error.
+func (env *Environment) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	if err := env.Run(ctx, Command{Name: name, Args: args, Stdout: stdout, Stderr: stderr}); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.Bytes(), fmt.Errorf("%w: %s", err, msg)
+		}
+		return stdout.Bytes(), err
+	}
+	return stdout.Bytes(), nil
+}
+
+// Run runs the command in the Environment carried by ctx, see Environment.Run.
+func Run(ctx context.Context, cmd Command) error {
+	return EnvironmentFrom(ctx).Run(ctx, cmd)
+}
+
+// Output runs the command in the Environment carried by ctx and returns its standard output,
+// see Environment.Output.
+func Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return EnvironmentFrom(ctx).Output(ctx, name, args...)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// recordingRunner records the commands instead of running them.
+type recordingRunner struct {
+	commands []string
+	output   string
+	err      error
+}
+
+func (r *recordingRunner) Run(_ context.Context, cmd Command) error {
+	r.commands = append(r.commands, cmd.String())
+	if cmd.Stdout != nil {
+		_, _ = cmd.Stdout.Write([]byte(r.output))
+	}
+	return r.err
+}
+
+var _ = Describe("Runner", func() {
+	Context("Environment.Runner", func() {
+		var (
+			r   *recordingRunner
+			env *Environment
+		)
+
+		BeforeEach(func() {
+			r = &recordingRunner{output: "go version go1.16 linux/amd64"}
+			env = &Environment{Runner: r, Stdout: new(bytes.Buffer)}
+		})
+
+		It("should run the commands of RunCmd with the provided runner", func() {
+			Expect(env.RunCmd(context.Background(), "Update dependencies", "go", "mod", "tidy")).To(Succeed())
+			Expect(r.commands).To(Equal([]string{"go mod tidy"}))
+		})
+
+		It("should not run the commands of RunCmd in dry-run mode", func() {
+			env.DryRun = true
+
+			Expect(env.RunCmd(context.Background(), "Update dependencies", "go", "mod", "tidy")).To(Succeed())
+			Expect(r.commands).To(BeEmpty())
+		})
+
+		It("should notify the commands of RunCmd to the observer", func() {
+			var observed [][]string
+			env.CommandObserver = func(_ string, args []string, _ error) { observed = append(observed, args) }
+
+			Expect(env.RunCmd(context.Background(), "Update dependencies", "go", "mod", "tidy")).To(Succeed())
+			Expect(observed).To(Equal([][]string{{"go", "mod", "tidy"}}))
+		})
+
+		It("should return the output of Output", func() {
+			out, err := env.Output(context.Background(), "go", "version")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal("go version go1.16 linux/amd64"))
+			Expect(r.commands).To(Equal([]string{"go version"}))
+		})
+
+		It("should run the commands with the environment carried by the context", func() {
+			ctx := WithEnvironment(context.Background(), env)
+			Expect(RunCmdWithContext(ctx, "Update dependencies", "go", "mod", "tidy")).To(Succeed())
+			_, err := Output(ctx, "go", "version")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.commands).To(Equal([]string{"go mod tidy", "go version"}))
+		})
+
+		It("should return the errors of the provided runner", func() {
+			r.err = errors.New("offline")
+			Expect(env.RunCmd(context.Background(), "Update dependencies", "go", "mod", "tidy")).To(MatchError(r.err))
+		})
+	})
+
+	Context("ExecRunner", func() {
+		It("should capture the output of the command", func() {
+			stdout := new(bytes.Buffer)
+			cmd := Command{Name: "echo", Args: []string{"hello"}, Stdout: stdout}
+			Expect(ExecRunner{}.Run(context.Background(), cmd)).To(Succeed())
+			Expect(stdout.String()).To(Equal("hello\n"))
+		})
+
+		It("should write the output of the command to its writers", func() {
+			stdout := new(bytes.Buffer)
+			cmd := Command{Name: "echo", Args: []string{"hello"}}
+			Expect(ExecRunner{Stdout: stdout}.Run(context.Background(), cmd)).To(Succeed())
+			Expect(stdout.String()).To(Equal("hello\n"))
+		})
+
+		It("should send the standard input to the command", func() {
+			stdout := new(bytes.Buffer)
+			cmd := Command{Name: "cat", Stdin: strings.NewReader("request"), Stdout: stdout}
+			Expect(ExecRunner{}.Run(context.Background(), cmd)).To(Succeed())
+			Expect(stdout.String()).To(Equal("request"))
+		})
+
+		It("should run the command in the working directory", func() {
+			dir, err := filepath.EvalSymlinks(os.TempDir())
+			Expect(err).NotTo(HaveOccurred())
+
+			stdout := new(bytes.Buffer)
+			Expect(ExecRunner{Dir: dir}.Run(context.Background(), Command{Name: "pwd", Stdout: stdout})).To(Succeed())
+			Expect(stdout.String()).To(Equal(dir + "\n"))
+		})
+
+		It("should set the environment variables", func() {
+			stdout := new(bytes.Buffer)
+			r := ExecRunner{Env: []string{"KUBEBUILDER_RUNNER_TEST=set"}}
+			cmd := Command{Name: "sh", Args: []string{"-c", "echo $KUBEBUILDER_RUNNER_TEST"}, Stdout: stdout}
+			Expect(r.Run(context.Background(), cmd)).To(Succeed())
+			Expect(stdout.String()).To(Equal("set\n"))
+		})
+
+		It("should kill the command once the timeout expires", func() {
+			r := ExecRunner{Timeout: 10 * time.Millisecond}
+			Expect(r.Run(context.Background(), Command{Name: "sleep", Args: []string{"10"}})).
+				To(MatchError(context.DeadlineExceeded))
+		})
+	})
+
+	Context("Environment", func() {
+		It("should run the commands from its working directory and with its environment variables", func() {
+			dir, err := filepath.EvalSymlinks(os.TempDir())
+			Expect(err).NotTo(HaveOccurred())
+			stdout := new(bytes.Buffer)
+			env := &Environment{Dir: dir, Env: []string{"KUBEBUILDER_RUNNER_TEST=set"}, Stdout: stdout}
+
+			Expect(env.RunCmd(context.Background(), "Print the environment",
+				"sh", "-c", "pwd; echo $KUBEBUILDER_RUNNER_TEST")).To(Succeed())
+			Expect(stdout.String()).To(Equal("Print the environment:\n$ sh -c pwd; echo $KUBEBUILDER_RUNNER_TEST\n" +
+				dir + "\nset\n"))
+		})
+
+		It("should return its working directory", func() {
+			Expect((&Environment{Dir: "/project"}).Getwd()).To(Equal("/project"))
+		})
+
+		It("should be the one of the process if nil", func() {
+			var env *Environment
+			wd, err := os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.Getwd()).To(Equal(wd))
+			Expect(env.OutOrStdout()).To(Equal(os.Stdout))
+			Expect(env.ErrOrStderr()).To(Equal(os.Stderr))
+			Expect(EnvironmentFrom(context.Background())).To(BeNil())
+		})
+	})
+
+	Context("Output", func() {
+		It("should include the standard error in the error", func() {
+			_, err := Output(context.Background(), "sh", "-c", "echo failure >&2; exit 1")
+			Expect(err).To(MatchError(ContainSubstring("failure")))
+		})
+	})
+})
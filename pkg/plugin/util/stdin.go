@@ -25,6 +25,9 @@ import (
 
 // YesNo reads from stdin looking for one of "y", "yes", "n", "no" and returns
 // true for "y" and false for "n"
+//
+// Deprecated: use Confirm, which respects the non-interactive mode and fails instead of blocking
+// when the user cannot be prompted.
 func YesNo(reader *bufio.Reader) bool {
 	for {
 		text := readstdin(reader)
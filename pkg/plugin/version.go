@@ -105,3 +105,66 @@ func (v Version) IsStable() bool {
 	// Any other version than 0 depends on its stage field
 	return v.Stage.IsStable()
 }
+
+const (
+	// latestVersion is the version of a plugin key that matches any version.
+	latestVersion = "latest"
+	// minVersionPrefix and minVersionSuffix denote the minimum version of a plugin key.
+	minVersionPrefix = ">="
+	minVersionSuffix = "+"
+)
+
+// VersionRange is the set of versions matched by the version of a plugin key. It may be an exact version,
+// e.g. "v3", every version greater than or equal to a minimum version, e.g. "v3+" or ">=v3-alpha",
+// or every version, i.e. "latest".
+type VersionRange struct {
+	// Min is the minimum version of the range, or its only version if the range is exact.
+	Min Version
+	// Exact indicates that the range only contains its minimum version.
+	Exact bool
+	// Latest indicates that the range contains every version.
+	Latest bool
+}
+
+// Parse parses versionRange inline, assuming it adheres to format: latest|(>=)?<version>|<version>(+)?
+func (r *VersionRange) Parse(versionRange string) error {
+	*r = VersionRange{}
+
+	switch {
+	case versionRange == latestVersion:
+		r.Latest = true
+		return nil
+	case strings.HasPrefix(versionRange, minVersionPrefix):
+		versionRange = strings.TrimPrefix(versionRange, minVersionPrefix)
+	case strings.HasSuffix(versionRange, minVersionSuffix):
+		versionRange = strings.TrimSuffix(versionRange, minVersionSuffix)
+	default:
+		r.Exact = true
+	}
+
+	return r.Min.Parse(versionRange)
+}
+
+// String returns the string representation of r.
+func (r VersionRange) String() string {
+	switch {
+	case r.Latest:
+		return latestVersion
+	case r.Exact:
+		return r.Min.String()
+	default:
+		return r.Min.String() + minVersionSuffix
+	}
+}
+
+// Contains checks if v is part of the range.
+func (r VersionRange) Contains(v Version) bool {
+	switch {
+	case r.Latest:
+		return true
+	case r.Exact:
+		return v.Compare(r.Min) == 0
+	default:
+		return v.Compare(r.Min) >= 0
+	}
+}
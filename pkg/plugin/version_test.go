@@ -185,3 +185,58 @@ var _ = Describe("Version", func() {
 	})
 
 })
+
+var _ = Describe("VersionRange", func() {
+	Context("Parse", func() {
+		DescribeTable("should be correctly parsed for valid version ranges",
+			func(s string, r VersionRange) {
+				var versionRange VersionRange
+				Expect(versionRange.Parse(s)).To(Succeed())
+				Expect(versionRange).To(Equal(r))
+			},
+			Entry("for exact versions", "v3", VersionRange{Min: Version{Number: 3}, Exact: true}),
+			Entry("for exact unstable versions", "v3-alpha",
+				VersionRange{Min: Version{Number: 3, Stage: stage.Alpha}, Exact: true}),
+			Entry("for minimum versions with suffix", "v3+", VersionRange{Min: Version{Number: 3}}),
+			Entry("for minimum versions with prefix", ">=v3-beta",
+				VersionRange{Min: Version{Number: 3, Stage: stage.Beta}}),
+			Entry("for latest versions", "latest", VersionRange{Latest: true}),
+		)
+
+		DescribeTable("should error when parsing an invalid version range",
+			func(s string) {
+				var versionRange VersionRange
+				Expect(versionRange.Parse(s)).NotTo(Succeed())
+			},
+			Entry("for an empty range", ""),
+			Entry("for an invalid minimum version with suffix", "va+"),
+			Entry("for an invalid minimum version with prefix", ">=v3-gamma"),
+			Entry("for both a prefix and a suffix", ">=v3+"),
+		)
+	})
+
+	Context("String", func() {
+		DescribeTable("should return the correct string value",
+			func(r VersionRange, s string) { Expect(r.String()).To(Equal(s)) },
+			Entry("for exact versions", VersionRange{Min: Version{Number: 3}, Exact: true}, "v3"),
+			Entry("for minimum versions", VersionRange{Min: Version{Number: 3, Stage: stage.Alpha}}, "v3-alpha+"),
+			Entry("for latest versions", VersionRange{Latest: true}, "latest"),
+		)
+	})
+
+	Context("Contains", func() {
+		v2 := Version{Number: 2}
+		v3Alpha := Version{Number: 3, Stage: stage.Alpha}
+		v3 := Version{Number: 3}
+
+		DescribeTable("should check if the range contains the version",
+			func(r VersionRange, v Version, contains bool) { Expect(r.Contains(v)).To(Equal(contains)) },
+			Entry("for exact versions", VersionRange{Min: v3, Exact: true}, v3, true),
+			Entry("for other exact versions", VersionRange{Min: v3, Exact: true}, v3Alpha, false),
+			Entry("for minimum versions", VersionRange{Min: v3Alpha}, v3Alpha, true),
+			Entry("for higher versions", VersionRange{Min: v3Alpha}, v3, true),
+			Entry("for lower versions", VersionRange{Min: v3Alpha}, v2, false),
+			Entry("for latest versions", VersionRange{Latest: true}, v2, true),
+		)
+	})
+})
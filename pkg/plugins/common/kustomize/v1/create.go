@@ -17,8 +17,6 @@ limitations under the License.
 package v1
 
 import (
-	"strconv"
-
 	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
@@ -29,13 +27,16 @@ type createSubcommand struct {
 	config   config.Config
 	resource *resource.Resource
 
-	flagSet *pflag.FlagSet
-
 	// force indicates whether to scaffold files even if they exist.
 	force bool
 }
 
-func (p *createSubcommand) BindFlags(fs *pflag.FlagSet) { p.flagSet = fs }
+func (p *createSubcommand) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&p.force, "force", false, "attempt to create resource even if it already exists")
+}
+
+// SharedFlags implements plugin.SharesFlags so that `--force` also applies to the rest of the plugins.
+func (p *createSubcommand) SharedFlags() []string { return []string{"force"} }
 
 func (p *createSubcommand) InjectConfig(c config.Config) error {
 	p.config = c
@@ -46,13 +47,3 @@ func (p *createSubcommand) InjectResource(res *resource.Resource) error {
 	p.resource = res
 	return nil
 }
-
-func (p *createSubcommand) configure() (err error) {
-	if forceFlag := p.flagSet.Lookup("force"); forceFlag != nil {
-		if p.force, err = strconv.ParseBool(forceFlag.Value.String()); err != nil {
-			return err
-		}
-
-	}
-	return nil
-}
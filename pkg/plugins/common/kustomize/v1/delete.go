@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds"
+)
+
+var _ plugin.DeleteAPISubcommand = &deleteAPISubcommand{}
+
+type deleteAPISubcommand struct {
+	config   config.Config
+	resource *resource.Resource
+}
+
+func (p *deleteAPISubcommand) InjectConfig(c config.Config) error {
+	p.config = c
+	return nil
+}
+
+func (p *deleteAPISubcommand) InjectResource(res *resource.Resource) error {
+	r, err := p.config.GetResource(res.GVK)
+	if err != nil {
+		return err
+	}
+
+	*res = r
+	p.resource = res
+	return nil
+}
+
+func (p *deleteAPISubcommand) Scaffold(fs machinery.Filesystem) error {
+	scaffolder := scaffolds.NewDeleteAPIScaffolder(p.config, *p.resource)
+	scaffolder.InjectFS(fs)
+	return scaffolder.Scaffold()
+}
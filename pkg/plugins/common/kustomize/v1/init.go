@@ -18,7 +18,6 @@ package v1
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -28,6 +27,7 @@ import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/internal/validation"
 	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds"
 )
 
@@ -35,6 +35,7 @@ var _ plugin.InitSubcommand = &initSubcommand{}
 
 type initSubcommand struct {
 	config config.Config
+	env    *util.Environment
 
 	// config options
 	domain          string
@@ -62,6 +63,11 @@ func (p *initSubcommand) BindFlags(fs *pflag.FlagSet) {
 		"create a versioned ComponentConfig file, may be 'true' or 'false'")
 }
 
+func (p *initSubcommand) InjectEnvironment(env *util.Environment) error {
+	p.env = env
+	return nil
+}
+
 func (p *initSubcommand) InjectConfig(c config.Config) error {
 	p.config = c
 
@@ -71,7 +77,7 @@ func (p *initSubcommand) InjectConfig(c config.Config) error {
 
 	// Assign a default project name
 	if p.name == "" {
-		dir, err := os.Getwd()
+		dir, err := p.env.Getwd()
 		if err != nil {
 			return fmt.Errorf("error getting current directory: %v", err)
 		}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/internal/validation"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds"
+)
+
+var (
+	_ plugin.MigrateSubcommand = &migrateSubcommand{}
+	_ plugin.RequiresReview    = &migrateSubcommand{}
+)
+
+type migrateSubcommand struct {
+	config config.Config
+	env    *util.Environment
+
+	// filesToReview are the files that could not be migrated automatically
+	filesToReview []plugin.FileToReview
+}
+
+func (p *migrateSubcommand) UpdateMetadata(cliMeta plugin.CLIMetadata, subcmdMeta *plugin.SubcommandMetadata) {
+	subcmdMeta.Description += `
+The kustomize/v1 plugin migrates projects scaffolded with the go/v2 plugin:
+  - The project name is set to the name of the current directory if it was not tracked.
+  - The kustomize manifests under the "config" directory are regenerated, except for the samples.
+`
+}
+
+func (p *migrateSubcommand) InjectEnvironment(env *util.Environment) error {
+	p.env = env
+	return nil
+}
+
+func (p *migrateSubcommand) InjectConfig(c config.Config) error {
+	p.config = c
+
+	// Projects scaffolded with go/v2 do not track their name, which is used to name the manifests
+	if p.config.GetProjectName() != "" {
+		return nil
+	}
+	dir, err := p.env.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %v", err)
+	}
+	name := strings.ToLower(filepath.Base(dir))
+	if err := validation.IsDNS1123Label(name); err != nil {
+		return fmt.Errorf("project name (%s) is invalid: %v", name, err)
+	}
+	return p.config.SetProjectName(name)
+}
+
+func (p *migrateSubcommand) Scaffold(fs machinery.Filesystem) error {
+	p.filesToReview = nil
+
+	scaffolder := scaffolds.NewMigrateScaffolder(p.config)
+	scaffolder.InjectFS(fs)
+	if err := scaffolder.Scaffold(); err != nil {
+		return err
+	}
+
+	p.review(filepath.Join("config", "default", "kustomization.yaml"),
+		"the file was regenerated, uncomment the webhook, cert-manager and prometheus sections "+
+			"again if the project uses them")
+	if exists, _ := afero.Exists(fs.FS, filepath.Join("config", "crd", "kustomization.yaml")); exists {
+		p.review(filepath.Join("config", "crd", "kustomization.yaml"),
+			"the file was regenerated, uncomment the webhook and CA injection patches again if the project uses them")
+	}
+
+	return nil
+}
+
+func (p *migrateSubcommand) FilesToReview() []plugin.FileToReview {
+	return p.filesToReview
+}
+
+// review records a file that needs to be reviewed manually
+func (p *migrateSubcommand) review(path, reason string) {
+	p.filesToReview = append(p.filesToReview, plugin.FileToReview{Path: path, Reason: reason})
+}
@@ -19,6 +19,7 @@ package v1
 import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	cfgv4 "sigs.k8s.io/kubebuilder/v3/pkg/config/v4"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugins"
 )
@@ -27,13 +28,15 @@ const pluginName = "kustomize.common." + plugins.DefaultNameQualifier
 
 var (
 	pluginVersion            = plugin.Version{Number: 1}
-	supportedProjectVersions = []config.Version{cfgv3.Version}
+	supportedProjectVersions = []config.Version{cfgv3.Version, cfgv4.Version}
 )
 
 var (
 	_ plugin.Init          = Plugin{}
 	_ plugin.CreateAPI     = Plugin{}
 	_ plugin.CreateWebhook = Plugin{}
+	_ plugin.DeleteAPI     = Plugin{}
+	_ plugin.Migrator      = Plugin{}
 )
 
 // Plugin implements the plugin.Full interface
@@ -41,6 +44,8 @@ type Plugin struct {
 	initSubcommand
 	createAPISubcommand
 	createWebhookSubcommand
+	deleteAPISubcommand
+	migrateSubcommand
 }
 
 // Name returns the name of the plugin
@@ -62,3 +67,14 @@ func (p Plugin) GetCreateAPISubcommand() plugin.CreateAPISubcommand { return &p.
 func (p Plugin) GetCreateWebhookSubcommand() plugin.CreateWebhookSubcommand {
 	return &p.createWebhookSubcommand
 }
+
+// GetDeleteAPISubcommand will return the subcommand which is responsible for deleting apis
+func (p Plugin) GetDeleteAPISubcommand() plugin.DeleteAPISubcommand { return &p.deleteAPISubcommand }
+
+// MigrateFrom will return the subcommand which is responsible for migrating projects scaffolded with go/v2
+func (p Plugin) MigrateFrom(previousKey string) plugin.MigrateSubcommand {
+	if previousKey != "go."+plugins.DefaultNameQualifier+"/v2" {
+		return nil
+	}
+	return &p.migrateSubcommand
+}
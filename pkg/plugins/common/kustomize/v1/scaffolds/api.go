@@ -60,7 +60,7 @@ func (s *apiScaffolder) InjectFS(fs machinery.Filesystem) {
 
 // Scaffold implements cmdutil.Scaffolder
 func (s *apiScaffolder) Scaffold() error {
-	fmt.Println("Writing kustomize manifests for you to edit...")
+	fmt.Fprintln(s.fs.Environment.OutOrStdout(), "Writing kustomize manifests for you to edit...")
 
 	// Initialize the machinery.Scaffold that will write the files to disk
 	scaffold := machinery.NewScaffold(s.fs,
@@ -24,8 +24,10 @@ import (
 )
 
 var (
-	_ machinery.Template = &Kustomization{}
-	_ machinery.Inserter = &Kustomization{}
+	_ machinery.Template     = &Kustomization{}
+	_ machinery.Inserter     = &Kustomization{}
+	_ machinery.YAMLInserter = &Kustomization{}
+	_ machinery.Remover      = &Kustomization{}
 )
 
 // Kustomization scaffolds a file that defines the kustomization scheme for the crd folder
@@ -104,6 +106,21 @@ func (f *Kustomization) GetCodeFragments() machinery.CodeFragmentsMap {
 	return fragments
 }
 
+// GetYAMLAnchors implements file.YAMLInserter
+func (f *Kustomization) GetYAMLAnchors() map[machinery.Marker]machinery.YAMLAnchor {
+	// Patches are scaffolded commented out, so they are not inserted again once they are uncommented
+	return map[machinery.Marker]machinery.YAMLAnchor{
+		machinery.NewMarkerFor(f.Path, resourceMarker):         {Field: []string{"resources"}},
+		machinery.NewMarkerFor(f.Path, webhookPatchMarker):     {Field: []string{"patchesStrategicMerge"}},
+		machinery.NewMarkerFor(f.Path, caInjectionPatchMarker): {Field: []string{"patchesStrategicMerge"}},
+	}
+}
+
+// GetCodeFragmentsToRemove implements file.Remover
+func (f *Kustomization) GetCodeFragmentsToRemove() machinery.CodeFragmentsMap {
+	return f.GetCodeFragments()
+}
+
 var kustomizationTemplate = `# This kustomization.yaml is not intended to be run by itself,
 # since it depends on service name and namespace that are out of this kustomize package.
 # It should be run by config/default
@@ -40,7 +40,7 @@ func (f *ManagerWebhookPatch) SetTemplateDefaults() error {
 	f.TemplateBody = managerWebhookPatchTemplate
 
 	if f.Force {
-		f.IfExistsAction = machinery.OverwriteFile
+		f.IfExistsAction = machinery.MergeFile
 	} else {
 		// If file exists (ex. because a webhook was already created), skip creation.
 		f.IfExistsAction = machinery.SkipFile
@@ -40,7 +40,7 @@ func (f *CRDSample) SetTemplateDefaults() error {
 	f.Path = f.Resource.Replacer().Replace(f.Path)
 
 	if f.Force {
-		f.IfExistsAction = machinery.OverwriteFile
+		f.IfExistsAction = machinery.MergeFile
 	} else {
 		f.IfExistsAction = machinery.Error
 	}
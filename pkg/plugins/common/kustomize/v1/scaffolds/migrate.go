@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds/internal/templates/config/certmanager"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds/internal/templates/config/crd"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds/internal/templates/config/crd/patches"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds/internal/templates/config/kdefault"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds/internal/templates/config/manager"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds/internal/templates/config/prometheus"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds/internal/templates/config/rbac"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/common/kustomize/v1/scaffolds/internal/templates/config/webhook"
+)
+
+var _ plugins.Scaffolder = &migrateScaffolder{}
+
+type migrateScaffolder struct {
+	config config.Config
+
+	// fs is the filesystem that will be used by the scaffolder
+	fs machinery.Filesystem
+}
+
+// NewMigrateScaffolder returns a new Scaffolder for project migration operations, which regenerates the kustomize
+// manifests of the project and of the resources tracked in the configuration
+func NewMigrateScaffolder(config config.Config) plugins.Scaffolder {
+	return &migrateScaffolder{
+		config: config,
+	}
+}
+
+// InjectFS implements cmdutil.Scaffolder
+func (s *migrateScaffolder) InjectFS(fs machinery.Filesystem) {
+	s.fs = fs
+}
+
+// Scaffold implements cmdutil.Scaffolder
+func (s *migrateScaffolder) Scaffold() error {
+	fmt.Fprintln(s.fs.Environment.OutOrStdout(), "Regenerating kustomize manifests...")
+
+	// Initialize the machinery.Scaffold that will overwrite the existing files
+	scaffold := machinery.NewScaffold(s.fs,
+		machinery.WithConfig(s.config),
+		machinery.WithIfExistsAction(machinery.OverwriteFile),
+	)
+
+	if err := scaffold.Execute(
+		&rbac.Kustomization{},
+		&rbac.AuthProxyRole{},
+		&rbac.AuthProxyRoleBinding{},
+		&rbac.AuthProxyService{},
+		&rbac.AuthProxyClientRole{},
+		&rbac.RoleBinding{},
+		&rbac.LeaderElectionRole{},
+		&rbac.LeaderElectionRoleBinding{},
+		&rbac.ServiceAccount{},
+		&manager.Kustomization{},
+		&manager.Config{Image: imageName},
+		&manager.ControllerManagerConfig{},
+		&kdefault.Kustomization{},
+		&kdefault.ManagerAuthProxyPatch{},
+		&kdefault.ManagerConfigPatch{},
+		&prometheus.Kustomization{},
+		&prometheus.Monitor{},
+	); err != nil {
+		return fmt.Errorf("error migrating kustomize manifests: %v", err)
+	}
+
+	resources, err := s.config.GetResources()
+	if err != nil {
+		return fmt.Errorf("error migrating kustomize manifests: unable to load resources: %w", err)
+	}
+
+	// The files shared by every resource are regenerated along with the first resource that needs them,
+	// the rest of the resources are added to them
+	var apiRegenerated, webhookRegenerated bool
+	for i := range resources {
+		res := resources[i]
+
+		if res.HasAPI() {
+			options := []machinery.ScaffoldOption{machinery.WithConfig(s.config), machinery.WithResource(&res)}
+			if !apiRegenerated {
+				options = append(options, machinery.WithIfExistsAction(machinery.OverwriteFile))
+				apiRegenerated = true
+			}
+
+			if err := machinery.NewScaffold(s.fs, options...).Execute(
+				&crd.Kustomization{},
+				&crd.KustomizeConfig{},
+			); err != nil {
+				return fmt.Errorf("error migrating kustomize API manifests: %v", err)
+			}
+
+			// Files specific to the resource are only scaffolded if they do not exist
+			if err := machinery.NewScaffold(s.fs,
+				machinery.WithConfig(s.config),
+				machinery.WithResource(&res),
+			).Execute(
+				&rbac.CRDEditorRole{},
+				&rbac.CRDViewerRole{},
+				&patches.EnableWebhookPatch{},
+				&patches.EnableCAInjectionPatch{},
+			); err != nil {
+				return fmt.Errorf("error migrating kustomize API manifests: %v", err)
+			}
+		}
+
+		if res.Webhooks != nil && !res.Webhooks.IsEmpty() && !webhookRegenerated {
+			if err := machinery.NewScaffold(s.fs,
+				machinery.WithConfig(s.config),
+				machinery.WithResource(&res),
+				machinery.WithIfExistsAction(machinery.OverwriteFile),
+			).Execute(
+				&kdefault.WebhookCAInjectionPatch{},
+				&kdefault.ManagerWebhookPatch{},
+				&webhook.Kustomization{},
+				&webhook.KustomizeConfig{},
+				&webhook.Service{},
+				&certmanager.Certificate{},
+				&certmanager.Kustomization{},
+				&certmanager.KustomizeConfig{},
+			); err != nil {
+				return fmt.Errorf("error migrating kustomize webhook manifests: %v", err)
+			}
+			webhookRegenerated = true
+		}
+	}
+
+	return nil
+}
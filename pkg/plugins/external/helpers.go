@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/external"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+)
+
+// defaultAPIVersion is the version of the PluginRequest and PluginResponse schemas.
+const defaultAPIVersion = "v1alpha1"
+
+// filePermission is the permission used for the files written from a plugin response.
+const filePermission os.FileMode = 0600
+
+// runPlugin executes the plugin at path with the util.Runner of the environment carried by ctx,
+// sending request through stdin, and returns its stdout.
+func runPlugin(ctx context.Context, path string, request []byte) ([]byte, error) {
+	stdout := new(bytes.Buffer)
+	if err := util.Run(ctx, util.Command{Name: path, Stdin: bytes.NewReader(request), Stdout: stdout}); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// newPluginRequest creates a PluginRequest for the provided command.
+func newPluginRequest(command string, args []string) external.PluginRequest {
+	return external.PluginRequest{
+		APIVersion: defaultAPIVersion,
+		Command:    command,
+		Args:       args,
+	}
+}
+
+// makePluginRequest sends req to the plugin at path and decodes its response.
+func makePluginRequest(ctx context.Context, path string, req external.PluginRequest) (*external.PluginResponse, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal plugin request: %w", err)
+	}
+
+	out, err := runPlugin(ctx, path, reqBytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to run plugin %q: %w", path, err)
+	}
+
+	res := &external.PluginResponse{}
+	if err := json.Unmarshal(out, res); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal plugin response: %w", err)
+	}
+
+	if res.Error {
+		return nil, fmt.Errorf("plugin %q failed: %s", path, strings.Join(res.ErrorMsgs, "; "))
+	}
+	if res.Command != req.Command {
+		return nil, fmt.Errorf("plugin %q answered to command %q instead of %q", path, res.Command, req.Command)
+	}
+
+	return res, nil
+}
+
+// handlePluginResponse sends req with the current universe to the plugin at path, writes
+// the files that were created or modified by the plugin to fs and removes the ones it reported as removed.
+func handlePluginResponse(ctx context.Context, fs machinery.Filesystem, path string, req external.PluginRequest) error {
+	universe, err := getUniverse(fs)
+	if err != nil {
+		return fmt.Errorf("unable to build the universe: %w", err)
+	}
+	req.Universe = universe
+
+	res, err := makePluginRequest(ctx, path, req)
+	if err != nil {
+		return err
+	}
+
+	// Validate every path before modifying any file
+	for filename := range res.Universe {
+		if err := validateProjectPath(filename); err != nil {
+			return fmt.Errorf("plugin %q returned an invalid file: %w", path, err)
+		}
+	}
+	for _, filename := range res.RemovedFiles {
+		if err := validateProjectPath(filename); err != nil {
+			return fmt.Errorf("plugin %q removed an invalid file: %w", path, err)
+		}
+	}
+
+	for filename, content := range res.Universe {
+		if previous, found := universe[filepath.ToSlash(filepath.Clean(filename))]; found && previous == content {
+			continue
+		}
+
+		if err := fs.FS.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+			return fmt.Errorf("unable to create directory for %q: %w", filename, err)
+		}
+		if err := afero.WriteFile(fs.FS, filename, []byte(content), filePermission); err != nil {
+			return fmt.Errorf("unable to write %q: %w", filename, err)
+		}
+	}
+
+	for _, filename := range res.RemovedFiles {
+		if err := fs.FS.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to remove %q: %w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// validateProjectPath checks that a path returned by a plugin is relative to the project root and stays within it.
+func validateProjectPath(filename string) error {
+	if filename == "" || filepath.IsAbs(filename) || filepath.VolumeName(filename) != "" {
+		return fmt.Errorf("%q is not a path relative to the project root", filename)
+	}
+	cleaned := filepath.Clean(filepath.FromSlash(filename))
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%q is not within the project", filename)
+	}
+	return nil
+}
+
+// skippedDirs are the directories of the project root that are not included in the universe,
+// as they hold binaries and dependencies instead of project files.
+var skippedDirs = map[string]bool{"bin": true, "vendor": true}
+
+// getUniverse returns the contents of every file in fs mapped by their path.
+// Hidden directories, like .git, and the skipped directories are not included.
+func getUniverse(fs machinery.Filesystem) (map[string]string, error) {
+	universe := make(map[string]string)
+
+	err := afero.Walk(fs.FS, ".", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if path != "." && (strings.HasPrefix(info.Name(), ".") || skippedDirs[filepath.ToSlash(path)]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		content, err := afero.ReadFile(fs.FS, path)
+		if err != nil {
+			return err
+		}
+		universe[filepath.ToSlash(path)] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return universe, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/external"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+)
+
+// pluginRunner answers the plugin requests with run instead of executing the plugins.
+type pluginRunner struct {
+	run func(ctx context.Context, path string, request []byte) ([]byte, error)
+}
+
+func (r pluginRunner) Run(ctx context.Context, cmd util.Command) error {
+	request, err := ioutil.ReadAll(cmd.Stdin)
+	if err != nil {
+		return err
+	}
+	response, err := r.run(ctx, cmd.Name, request)
+	if err != nil {
+		return err
+	}
+	_, err = cmd.Stdout.Write(response)
+	return err
+}
+
+var _ = Describe("handlePluginResponse", func() {
+	const pluginPath = "/plugins/myplugin/v1/myplugin"
+
+	var (
+		ctx             context.Context
+		fs              machinery.Filesystem
+		received        external.PluginRequest
+		response        external.PluginResponse
+		pluginExecError error
+	)
+
+	BeforeEach(func() {
+		fs = machinery.Filesystem{FS: afero.NewMemMapFs()}
+		Expect(afero.WriteFile(fs.FS, "main.go", []byte("package main"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs.FS, ".git/HEAD", []byte("ref"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs.FS, "bin/manager", []byte("binary"), 0600)).To(Succeed())
+		Expect(afero.WriteFile(fs.FS, "vendor/modules.txt", []byte("# modules"), 0600)).To(Succeed())
+
+		received = external.PluginRequest{}
+		response = external.PluginResponse{APIVersion: defaultAPIVersion, Command: initCommand}
+		pluginExecError = nil
+
+		runner := pluginRunner{run: func(runCtx context.Context, path string, request []byte) ([]byte, error) {
+			Expect(runCtx).To(Equal(ctx))
+			Expect(path).To(Equal(pluginPath))
+			Expect(json.Unmarshal(request, &received)).To(Succeed())
+			if pluginExecError != nil {
+				return nil, pluginExecError
+			}
+			return json.Marshal(response)
+		}}
+		ctx = util.WithEnvironment(context.Background(), &util.Environment{Runner: runner})
+	})
+
+	It("should send the universe and write the returned files", func() {
+		response.Universe = map[string]string{
+			"main.go":          "package main",
+			"config/file.yaml": "key: value",
+		}
+
+		req := newPluginRequest(initCommand, []string{"--flag"})
+		Expect(handlePluginResponse(ctx, fs, pluginPath, req)).To(Succeed())
+
+		Expect(received.APIVersion).To(Equal(defaultAPIVersion))
+		Expect(received.Command).To(Equal(initCommand))
+		Expect(received.Args).To(Equal([]string{"--flag"}))
+		Expect(received.Universe).To(Equal(map[string]string{"main.go": "package main"}))
+
+		content, err := afero.ReadFile(fs.FS, "config/file.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("key: value"))
+	})
+
+	It("should keep the files that are not in the returned universe", func() {
+		response.Universe = map[string]string{"config/file.yaml": "key: value"}
+
+		Expect(handlePluginResponse(ctx, fs, pluginPath, newPluginRequest(initCommand, nil))).To(Succeed())
+
+		Expect(afero.Exists(fs.FS, "main.go")).To(BeTrue())
+		Expect(afero.Exists(fs.FS, "config/file.yaml")).To(BeTrue())
+	})
+
+	It("should remove the files reported as removed", func() {
+		Expect(afero.WriteFile(fs.FS, "config/old.yaml", []byte("key: value"), 0600)).To(Succeed())
+		response.RemovedFiles = []string{"config/old.yaml", "config/missing.yaml"}
+
+		Expect(handlePluginResponse(ctx, fs, pluginPath, newPluginRequest(initCommand, nil))).To(Succeed())
+
+		Expect(afero.Exists(fs.FS, "config/old.yaml")).To(BeFalse())
+		Expect(afero.Exists(fs.FS, "main.go")).To(BeTrue())
+		Expect(afero.Exists(fs.FS, "bin/manager")).To(BeTrue())
+		Expect(afero.Exists(fs.FS, "vendor/modules.txt")).To(BeTrue())
+	})
+
+	DescribeTable("should reject files outside of the project",
+		func(filename string, removed bool) {
+			if removed {
+				response.RemovedFiles = []string{filename}
+			} else {
+				response.Universe = map[string]string{"config/file.yaml": "key: value", filename: "content"}
+			}
+
+			err := handlePluginResponse(ctx, fs, pluginPath, newPluginRequest(initCommand, nil))
+			Expect(err).To(MatchError(ContainSubstring(filename)))
+			Expect(afero.Exists(fs.FS, "config/file.yaml")).To(BeFalse())
+			Expect(afero.Exists(fs.FS, "main.go")).To(BeTrue())
+		},
+		Entry("for absolute paths", "/etc/passwd", false),
+		Entry("for paths escaping the project", "../outside.go", false),
+		Entry("for paths escaping the project after being cleaned", "config/../../outside.go", false),
+		Entry("for removed paths escaping the project", "../../main.go", true),
+	)
+
+	It("should fail if the plugin reports an error", func() {
+		response.Error = true
+		response.ErrorMsgs = []string{"something went wrong"}
+
+		err := handlePluginResponse(ctx, fs, pluginPath, newPluginRequest(initCommand, nil))
+		Expect(err).To(MatchError(ContainSubstring("something went wrong")))
+	})
+
+	It("should fail if the plugin answers to a different command", func() {
+		response.Command = editCommand
+
+		Expect(handlePluginResponse(ctx, fs, pluginPath, newPluginRequest(initCommand, nil))).NotTo(Succeed())
+	})
+
+	It("should run the plugin with the provided context", func() {
+		type key struct{}
+		ctx = context.WithValue(ctx, key{}, "value")
+
+		Expect(handlePluginResponse(ctx, fs, pluginPath, newPluginRequest(initCommand, nil))).To(Succeed())
+	})
+
+	It("should fail if the plugin can not be executed", func() {
+		pluginExecError = fmt.Errorf("exec format error")
+
+		Expect(handlePluginResponse(ctx, fs, pluginPath, newPluginRequest(initCommand, nil))).NotTo(Succeed())
+	})
+})
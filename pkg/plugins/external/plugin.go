@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+var _ plugin.Full = Plugin{}
+
+// Plugin implements the plugin.Full interface by delegating every subcommand to an out-of-tree executable.
+type Plugin struct {
+	// PName is the name of the plugin.
+	PName string
+	// PVersion is the version of the plugin.
+	PVersion plugin.Version
+	// PSupportedProjectVersions are the project versions supported by the plugin.
+	PSupportedProjectVersions []config.Version
+
+	// Path is the path to the plugin executable.
+	Path string
+	// Args are the raw command line arguments that will be sent to the plugin executable.
+	Args []string
+}
+
+// Name returns the name of the plugin
+func (p Plugin) Name() string { return p.PName }
+
+// Version returns the version of the plugin
+func (p Plugin) Version() plugin.Version { return p.PVersion }
+
+// SupportedProjectVersions returns an array with all project versions supported by the plugin
+func (p Plugin) SupportedProjectVersions() []config.Version { return p.PSupportedProjectVersions }
+
+// GetInitSubcommand will return the subcommand which is responsible for initializing and common scaffolding
+func (p Plugin) GetInitSubcommand() plugin.InitSubcommand {
+	return &initSubcommand{subcommand{path: p.Path, args: p.Args}}
+}
+
+// GetCreateAPISubcommand will return the subcommand which is responsible for scaffolding apis
+func (p Plugin) GetCreateAPISubcommand() plugin.CreateAPISubcommand {
+	return &createAPISubcommand{subcommand{path: p.Path, args: p.Args}}
+}
+
+// GetCreateWebhookSubcommand will return the subcommand which is responsible for scaffolding webhooks
+func (p Plugin) GetCreateWebhookSubcommand() plugin.CreateWebhookSubcommand {
+	return &createWebhookSubcommand{subcommand{path: p.Path, args: p.Args}}
+}
+
+// GetEditSubcommand will return the subcommand which is responsible for editing the scaffold of the project
+func (p Plugin) GetEditSubcommand() plugin.EditSubcommand {
+	return &editSubcommand{subcommand{path: p.Path, args: p.Args}}
+}
+
+// GetSupportedProjectVersions sends a metadata request to the plugin executable at path and returns
+// the project versions it supports. The executable is run in the environment carried by ctx.
+func GetSupportedProjectVersions(ctx context.Context, path string) ([]config.Version, error) {
+	res, err := makePluginRequest(ctx, path, newPluginRequest(metadataCommand, []string{}))
+	if err != nil {
+		return nil, err
+	}
+	if len(res.SupportedProjectVersions) == 0 {
+		return nil, fmt.Errorf("plugin %q does not support any project version", path)
+	}
+
+	versions := make([]config.Version, 0, len(res.SupportedProjectVersions))
+	for _, rawVersion := range res.SupportedProjectVersions {
+		var version config.Version
+		if err := version.Parse(rawVersion); err != nil {
+			return nil, fmt.Errorf("plugin %q supports an invalid project version %q: %w", path, rawVersion, err)
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
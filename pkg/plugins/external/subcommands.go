@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+)
+
+const (
+	initCommand          = "init"
+	createAPICommand     = "create api"
+	createWebhookCommand = "create webhook"
+	editCommand          = "edit"
+	metadataCommand      = "metadata"
+)
+
+// subcommand contains the common fields of every external plugin subcommand.
+type subcommand struct {
+	// path is the path to the plugin executable.
+	path string
+	// args are the raw command line arguments that will be sent to the plugin executable.
+	args []string
+}
+
+// scaffold sends a plugin request for the provided command and applies the response universe to fs.
+// The plugin executable is stopped if ctx is done before it answers.
+func (s subcommand) scaffold(ctx context.Context, fs machinery.Filesystem, command string) error {
+	return handlePluginResponse(ctx, fs, s.path, newPluginRequest(command, s.args))
+}
+
+var (
+	_ plugin.InitSubcommand        = &initSubcommand{}
+	_ plugin.ScaffolderWithContext = &initSubcommand{}
+)
+
+type initSubcommand struct {
+	subcommand
+}
+
+func (p *initSubcommand) Scaffold(fs machinery.Filesystem) error {
+	return p.ScaffoldWithContext(context.Background(), fs)
+}
+
+func (p *initSubcommand) ScaffoldWithContext(ctx context.Context, fs machinery.Filesystem) error {
+	return p.scaffold(ctx, fs, initCommand)
+}
+
+var (
+	_ plugin.CreateAPISubcommand   = &createAPISubcommand{}
+	_ plugin.ScaffolderWithContext = &createAPISubcommand{}
+)
+
+type createAPISubcommand struct {
+	subcommand
+}
+
+func (p *createAPISubcommand) InjectResource(*resource.Resource) error {
+	// The resource is built from flags that are also sent to the plugin executable as raw arguments.
+	return nil
+}
+
+func (p *createAPISubcommand) Scaffold(fs machinery.Filesystem) error {
+	return p.ScaffoldWithContext(context.Background(), fs)
+}
+
+func (p *createAPISubcommand) ScaffoldWithContext(ctx context.Context, fs machinery.Filesystem) error {
+	return p.scaffold(ctx, fs, createAPICommand)
+}
+
+var (
+	_ plugin.CreateWebhookSubcommand = &createWebhookSubcommand{}
+	_ plugin.ScaffolderWithContext   = &createWebhookSubcommand{}
+)
+
+type createWebhookSubcommand struct {
+	subcommand
+}
+
+func (p *createWebhookSubcommand) InjectResource(*resource.Resource) error {
+	// The resource is built from flags that are also sent to the plugin executable as raw arguments.
+	return nil
+}
+
+func (p *createWebhookSubcommand) Scaffold(fs machinery.Filesystem) error {
+	return p.ScaffoldWithContext(context.Background(), fs)
+}
+
+func (p *createWebhookSubcommand) ScaffoldWithContext(ctx context.Context, fs machinery.Filesystem) error {
+	return p.scaffold(ctx, fs, createWebhookCommand)
+}
+
+var (
+	_ plugin.EditSubcommand        = &editSubcommand{}
+	_ plugin.ScaffolderWithContext = &editSubcommand{}
+)
+
+type editSubcommand struct {
+	subcommand
+}
+
+func (p *editSubcommand) Scaffold(fs machinery.Filesystem) error {
+	return p.ScaffoldWithContext(context.Background(), fs)
+}
+
+func (p *editSubcommand) ScaffoldWithContext(ctx context.Context, fs machinery.Filesystem) error {
+	return p.scaffold(ctx, fs, editCommand)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -44,6 +45,7 @@ var _ plugin.CreateAPISubcommand = &createAPISubcommand{}
 
 type createAPISubcommand struct {
 	config config.Config
+	env    *util.Environment
 
 	resource *resource.Resource
 }
@@ -75,6 +77,11 @@ make generate will be run.
 `, cliMeta.CommandName)
 }
 
+func (p *createAPISubcommand) InjectEnvironment(env *util.Environment) error {
+	p.env = env
+	return nil
+}
+
 func (p *createAPISubcommand) InjectConfig(c config.Config) error {
 	p.config = c
 
@@ -95,7 +102,11 @@ func (p *createAPISubcommand) InjectResource(res *resource.Resource) error {
 }
 
 func (p *createAPISubcommand) Scaffold(fs machinery.Filesystem) error {
-	fmt.Println("updating scaffold with declarative pattern...")
+	return p.ScaffoldWithContext(context.Background(), fs)
+}
+
+func (p *createAPISubcommand) ScaffoldWithContext(ctx context.Context, fs machinery.Filesystem) error {
+	fmt.Fprintln(p.env.OutOrStdout(), "updating scaffold with declarative pattern...")
 
 	// Load the boilerplate
 	bp, err := afero.ReadFile(fs.FS, filepath.Join("hack", "boilerplate.go.txt"))
@@ -146,7 +157,7 @@ func (p *createAPISubcommand) Scaffold(fs machinery.Filesystem) error {
 			break
 		}
 	}
-	err = util.RunCmd("Get declarative pattern", "go", "get",
+	err = util.RunCmdWithContext(ctx, "Get declarative pattern", "go", "get",
 		"sigs.k8s.io/kubebuilder-declarative-pattern@"+kbDeclarativePattern)
 	if err != nil {
 		return err
@@ -28,6 +28,7 @@ var _ machinery.Template = &Channel{}
 // Channel scaffolds the file for the channel
 type Channel struct {
 	machinery.TemplateMixin
+	machinery.EnvironmentMixin
 
 	ManifestVersion string
 }
@@ -37,7 +38,7 @@ func (f *Channel) SetTemplateDefaults() error {
 	if f.Path == "" {
 		f.Path = filepath.Join("channels", "stable")
 	}
-	fmt.Println(f.Path)
+	fmt.Fprintln(f.Environment.OutOrStdout(), f.Path)
 
 	f.TemplateBody = channelTemplate
 
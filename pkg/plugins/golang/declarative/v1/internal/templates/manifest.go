@@ -29,6 +29,7 @@ var _ machinery.Template = &Manifest{}
 type Manifest struct {
 	machinery.TemplateMixin
 	machinery.ResourceMixin
+	machinery.EnvironmentMixin
 
 	ManifestVersion string
 }
@@ -39,7 +40,7 @@ func (f *Manifest) SetTemplateDefaults() error {
 		f.Path = filepath.Join("channels", "packages", "%[kind]", f.ManifestVersion, "manifest.yaml")
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	fmt.Fprintln(f.Environment.OutOrStdout(), f.Path)
 
 	f.TemplateBody = manifestTemplate
 
@@ -20,6 +20,7 @@ import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	cfgv2 "sigs.k8s.io/kubebuilder/v3/pkg/config/v2"
 	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	cfgv4 "sigs.k8s.io/kubebuilder/v3/pkg/config/v4"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang"
@@ -29,11 +30,15 @@ const pluginName = "declarative." + golang.DefaultNameQualifier
 
 var (
 	pluginVersion            = plugin.Version{Number: 1}
-	supportedProjectVersions = []config.Version{cfgv2.Version, cfgv3.Version}
+	supportedProjectVersions = []config.Version{cfgv2.Version, cfgv3.Version, cfgv4.Version}
 	pluginKey                = plugin.KeyFor(Plugin{})
 )
 
-var _ plugin.CreateAPI = Plugin{}
+var (
+	_ plugin.CreateAPI       = Plugin{}
+	_ plugin.HasDependencies = Plugin{}
+	_ plugin.HasConfigSchema = Plugin{}
+)
 
 // Plugin implements the plugin.Full interface
 type Plugin struct {
@@ -49,6 +54,40 @@ func (Plugin) Version() plugin.Version { return pluginVersion }
 // SupportedProjectVersions returns an array with all project versions supported by the plugin
 func (Plugin) SupportedProjectVersions() []config.Version { return supportedProjectVersions }
 
+// Dependencies returns the relationships of the plugin with other plugins.
+// The Go plugin needs to scaffold the API and the boilerplate before this plugin can update them.
+func (Plugin) Dependencies() plugin.Dependencies {
+	return plugin.Dependencies{
+		Requires:  []string{golang.DefaultNameQualifier},
+		RunsAfter: []string{golang.DefaultNameQualifier},
+	}
+}
+
+// ConfigSchema returns the JSON Schema of the configuration stored by the plugin
+func (Plugin) ConfigSchema() config.Schema {
+	return config.Schema{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"resources": map[string]interface{}{
+				"description": "Resources that follow a declarative approach.",
+				"type":        "array",
+				"items": map[string]interface{}{
+					"type":                 "object",
+					"required":             []interface{}{"version", "kind"},
+					"additionalProperties": false,
+					"properties": map[string]interface{}{
+						"group":   map[string]interface{}{"type": "string"},
+						"domain":  map[string]interface{}{"type": "string"},
+						"version": map[string]interface{}{"type": "string"},
+						"kind":    map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	}
+}
+
 // GetCreateAPISubcommand will return the subcommand which is responsible for scaffolding apis
 func (p Plugin) GetCreateAPISubcommand() plugin.CreateAPISubcommand { return &p.createAPISubcommand }
 
@@ -17,11 +17,13 @@ limitations under the License.
 package golang
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 const (
@@ -125,19 +127,19 @@ func (v GoVersion) Compare(other GoVersion) int {
 }
 
 // ValidateGoVersion verifies that Go is installed and the current go version is supported by a plugin.
-func ValidateGoVersion(min, max GoVersion) error {
-	err := fetchAndCheckGoVersion(min, max)
+// Go is run in the environment carried by the context.
+func ValidateGoVersion(ctx context.Context, min, max GoVersion) error {
+	err := fetchAndCheckGoVersion(ctx, min, max)
 	if err != nil {
 		return fmt.Errorf("%s. You can skip this check using the --skip-go-version-check flag", err)
 	}
 	return nil
 }
 
-func fetchAndCheckGoVersion(min, max GoVersion) error {
-	cmd := exec.Command("go", "version")
-	out, err := cmd.Output()
+func fetchAndCheckGoVersion(ctx context.Context, min, max GoVersion) error {
+	out, err := util.Output(ctx, "go", "version")
 	if err != nil {
-		return fmt.Errorf("failed to retrieve 'go version': %v", string(out))
+		return fmt.Errorf("failed to retrieve 'go version': %v", err)
 	}
 
 	split := strings.Split(string(out), " ")
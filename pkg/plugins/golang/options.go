@@ -52,6 +52,13 @@ var (
 	}
 )
 
+const (
+	// StatusSubresource is the name of the status subresource
+	StatusSubresource = "status"
+	// ScaleSubresource is the name of the scale subresource
+	ScaleSubresource = "scale"
+)
+
 // Options contains the information required to build a new resource.Resource.
 type Options struct {
 	// Plural is the resource's kind plural form.
@@ -65,6 +72,22 @@ type Options struct {
 	// Namespaced is true if the resource should be namespaced.
 	Namespaced bool
 
+	// ShortNames are the short names of the resource.
+	ShortNames []string
+	// Categories are the categories the resource belongs to.
+	Categories []string
+	// Subresources are the subresources that will be enabled for the resource, the status one if empty.
+	Subresources []string
+	// StorageVersion is true if the version of the resource should be the one stored.
+	StorageVersion bool
+
+	// Owner is the team or person that owns the resource.
+	Owner string
+
+	// Module is the Go module that provides the API types of an external resource, whose API is not scaffolded.
+	// The types are imported from the package they would have in a project with this module path.
+	Module string
+
 	// Flags that define which parts should be scaffolded
 	DoAPI        bool
 	DoController bool
@@ -82,17 +105,46 @@ func (opts Options) UpdateResource(res *resource.Resource, c config.Config) {
 	if opts.DoAPI {
 		res.Path = resource.APIPackagePath(c.GetRepository(), res.Group, res.Version, c.IsMultiGroup())
 		res.API = &resource.API{
-			CRDVersion: opts.CRDVersion,
-			Namespaced: opts.Namespaced,
+			CRDVersion:     opts.CRDVersion,
+			Namespaced:     opts.Namespaced,
+			ShortNames:     opts.ShortNames,
+			Categories:     opts.Categories,
+			StorageVersion: opts.StorageVersion,
+		}
+		if len(opts.Subresources) != 0 {
+			res.API.Subresources = &resource.Subresources{}
+			for _, subresource := range opts.Subresources {
+				switch subresource {
+				case StatusSubresource:
+					res.API.Subresources.Status = true
+				case ScaleSubresource:
+					res.API.Subresources.Scale = true
+				}
+			}
 		}
 	}
 
+	if opts.Module != "" {
+		res.Module = opts.Module
+		res.Path = resource.APIPackagePath(opts.Module, res.Group, res.Version, c.IsMultiGroup())
+	}
+
+	if opts.Owner != "" {
+		res.Owner = opts.Owner
+	}
+
 	if opts.DoController {
 		res.Controller = true
 	}
 
 	if opts.DoDefaulting || opts.DoValidation || opts.DoConversion {
-		res.Path = resource.APIPackagePath(c.GetRepository(), res.Group, res.Version, c.IsMultiGroup())
+		// External resources keep importing their types from their module
+		if loadedRes, err := c.GetResource(res.GVK); err == nil && loadedRes.IsExternal() {
+			res.Module = loadedRes.Module
+			res.Path = loadedRes.Path
+		} else if !res.IsExternal() {
+			res.Path = resource.APIPackagePath(c.GetRepository(), res.Group, res.Version, c.IsMultiGroup())
+		}
 		res.Webhooks.WebhookVersion = opts.WebhookVersion
 		if opts.DoDefaulting {
 			res.Webhooks.Defaulting = true
@@ -111,7 +163,7 @@ func (opts Options) UpdateResource(res *resource.Resource, c config.Config) {
 	//  - Check if the resource group is a well-known core group => builtin core resource
 	//  - In any other case, default to                          => project resource
 	// TODO: need to support '--resource-pkg-path' flag for specifying resourcePath
-	if !opts.DoAPI {
+	if !opts.DoAPI && !res.IsExternal() {
 		var alreadyHasAPI bool
 		if c.GetVersion().Compare(cfgv2.Version) == 0 {
 			alreadyHasAPI = c.HasResource(res.GVK)
@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	cfgv2 "sigs.k8s.io/kubebuilder/v3/pkg/config/v2"
 	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	cfgv4 "sigs.k8s.io/kubebuilder/v3/pkg/config/v4"
 	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 )
 
@@ -90,11 +91,23 @@ var _ = Describe("Options", func() {
 					if options.DoAPI {
 						Expect(res.API.CRDVersion).To(Equal(options.CRDVersion))
 						Expect(res.API.Namespaced).To(Equal(options.Namespaced))
+						Expect(res.API.GetShortNames()).To(Equal(options.ShortNames))
+						Expect(res.API.GetCategories()).To(Equal(options.Categories))
+						Expect(res.API.IsStorageVersion()).To(Equal(options.StorageVersion))
+						for _, subresource := range options.Subresources {
+							switch subresource {
+							case StatusSubresource:
+								Expect(res.API.HasStatusSubresource()).To(BeTrue())
+							case ScaleSubresource:
+								Expect(res.API.HasScaleSubresource()).To(BeTrue())
+							}
+						}
 						Expect(res.API.IsEmpty()).To(BeFalse())
 					} else {
 						Expect(res.API.IsEmpty()).To(BeTrue())
 					}
 					Expect(res.Controller).To(Equal(options.DoController))
+					Expect(res.Owner).To(Equal(options.Owner))
 					Expect(res.Webhooks).NotTo(BeNil())
 					if options.DoDefaulting || options.DoValidation || options.DoConversion {
 						Expect(res.Webhooks.WebhookVersion).To(Equal(options.WebhookVersion))
@@ -113,11 +126,73 @@ var _ = Describe("Options", func() {
 			Entry("when updating nothing", Options{}),
 			Entry("when updating the plural", Options{Plural: "mates"}),
 			Entry("when updating the API", Options{DoAPI: true, CRDVersion: "v1", Namespaced: true}),
+			Entry("when updating the API metadata", Options{
+				DoAPI:          true,
+				CRDVersion:     "v1",
+				ShortNames:     []string{"fm"},
+				Categories:     []string{"crew"},
+				Subresources:   []string{ScaleSubresource},
+				StorageVersion: true,
+			}),
+			Entry("when updating the owner", Options{Owner: "team"}),
 			Entry("when updating the Controller", Options{DoController: true}),
 			Entry("when updating Webhooks",
 				Options{WebhookVersion: "v1", DoDefaulting: true, DoValidation: true, DoConversion: true}),
 		)
 
+		It("should import the API types of external resources from their module", func() {
+			const module = "github.com/example/apis"
+			options := Options{Module: module, DoController: true, WebhookVersion: "v1", DoDefaulting: true}
+			for _, multiGroup := range []bool{false, true} {
+				if multiGroup {
+					Expect(cfg.SetMultiGroup()).To(Succeed())
+				} else {
+					Expect(cfg.ClearMultiGroup()).To(Succeed())
+				}
+
+				res := resource.Resource{
+					GVK:      gvk,
+					Plural:   "firstmates",
+					API:      &resource.API{},
+					Webhooks: &resource.Webhooks{},
+				}
+
+				options.UpdateResource(&res, cfg)
+				Expect(res.Validate()).To(Succeed())
+				Expect(res.IsExternal()).To(BeTrue())
+				Expect(res.GetModule()).To(Equal(module))
+				Expect(res.Path).To(Equal(resource.APIPackagePath(module, gvk.Group, gvk.Version, multiGroup)))
+				Expect(res.HasAPI()).To(BeFalse())
+				Expect(res.HasDefaultingWebhook()).To(BeTrue())
+			}
+		})
+
+		It("should keep importing the API types of external resources when creating their webhooks", func() {
+			const module = "github.com/example/apis"
+			external := resource.Resource{
+				GVK:        gvk,
+				Plural:     "firstmates",
+				Path:       resource.APIPackagePath(module, gvk.Group, gvk.Version, false),
+				Module:     module,
+				Controller: true,
+			}
+			cfg = cfgv4.New()
+			Expect(cfg.SetRepository("test")).To(Succeed())
+			Expect(cfg.AddResource(external)).To(Succeed())
+
+			res := resource.Resource{
+				GVK:      gvk,
+				Plural:   "firstmates",
+				API:      &resource.API{},
+				Webhooks: &resource.Webhooks{},
+			}
+			Options{WebhookVersion: "v1", DoDefaulting: true}.UpdateResource(&res, cfg)
+			Expect(res.Validate()).To(Succeed())
+			Expect(res.GetModule()).To(Equal(module))
+			Expect(res.Path).To(Equal(external.Path))
+			Expect(cfg.UpdateResource(res)).To(Succeed())
+		})
+
 		DescribeTable("should use core apis",
 			func(group, qualified string) {
 				options := Options{}
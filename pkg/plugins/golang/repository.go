@@ -17,12 +17,13 @@ limitations under the License.
 package golang
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
+	"path/filepath"
+	"strings"
 
-	"golang.org/x/tools/go/packages"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
 )
 
 // module and goMod arg just enough of the output of `go mod edit -json` for our purposes
@@ -34,14 +35,9 @@ type module struct {
 }
 
 // findGoModulePath finds the path of the current module, if present.
-func findGoModulePath() (string, error) {
-	cmd := exec.Command("go", "mod", "edit", "-json")
-	cmd.Env = append(cmd.Env, os.Environ()...)
-	out, err := cmd.Output()
+func findGoModulePath(ctx context.Context) (string, error) {
+	out, err := util.Output(ctx, "go", "mod", "edit", "-json")
 	if err != nil {
-		if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
-			err = fmt.Errorf("%s", string(exitErr.Stderr))
-		}
 		return "", err
 	}
 	mod := goMod{}
@@ -51,40 +47,62 @@ func findGoModulePath() (string, error) {
 	return mod.Module.Path, nil
 }
 
+// findPackagePath finds the import path of the package in the current directory, if present.
+func findPackagePath(ctx context.Context) (string, error) {
+	out, err := util.Output(ctx, "go", "list", "-f", "{{.ImportPath}}", ".")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// findGOPATHPackagePath finds the import path that the current directory would have inside GOPATH, if it is there.
+func findGOPATHPackagePath(ctx context.Context) (string, error) {
+	out, err := util.Output(ctx, "go", "env", "GOPATH")
+	if err != nil {
+		return "", err
+	}
+	wd, err := util.EnvironmentFrom(ctx).Getwd()
+	if err != nil {
+		return "", err
+	}
+	for _, gopath := range filepath.SplitList(strings.TrimSpace(string(out))) {
+		rel, err := filepath.Rel(filepath.Join(gopath, "src"), wd)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		return filepath.ToSlash(rel), nil
+	}
+	return "", fmt.Errorf("%s is not inside GOPATH", wd)
+}
+
 // FindCurrentRepo attempts to determine the current repository
-// though a combination of go/packages and `go mod` commands/tricks.
-func FindCurrentRepo() (string, error) {
+// though a combination of `go list` and `go env` commands/tricks.
+// It does not write to the project, so it can be used in dry-run mode.
+// The commands are run in the environment carried by the context.
+func FindCurrentRepo(ctx context.Context) (string, error) {
 	// easiest case: existing go module
-	path, err := findGoModulePath()
+	path, err := findGoModulePath(ctx)
 	if err == nil {
 		return path, nil
 	}
 
 	// next, check if we've got a package in the current directory
-	pkgCfg := &packages.Config{
-		Mode: packages.NeedName, // name gives us path as well
-	}
-	pkgs, err := packages.Load(pkgCfg, ".")
+	path, err = findPackagePath(ctx)
 	// NB(directxman12): when go modules are off and we're outside GOPATH and
-	// we don't otherwise have a good guess packages.Load will fabricate a path
+	// we don't otherwise have a good guess `go list` will fabricate a path
 	// that consists of `_/absolute/path/to/current/directory`.  We shouldn't
 	// use that when it happens.
-	if err == nil && len(pkgs) > 0 && len(pkgs[0].PkgPath) > 0 && pkgs[0].PkgPath[0] != '_' {
-		return pkgs[0].PkgPath, nil
+	if err == nil && len(path) > 0 && path[0] != '_' {
+		return path, nil
 	}
 
-	// otherwise, try to get `go mod init` to guess for us -- it's pretty good
-	cmd := exec.Command("go", "mod", "init")
-	cmd.Env = append(cmd.Env, os.Environ()...)
-	if _, err := cmd.Output(); err != nil {
-		if exitErr, isExitErr := err.(*exec.ExitError); isExitErr {
-			err = fmt.Errorf("%s", string(exitErr.Stderr))
-		}
+	// otherwise, guess it from the location of the current directory inside GOPATH like `go mod init` does
+	path, err = findGOPATHPackagePath(ctx)
+	if err != nil {
 		// give up, let the user figure it out
 		return "", fmt.Errorf("could not determine repository path from module data, "+
-			"package data, or by initializing a module: %v", err)
+			"package data, or GOPATH: %v", err)
 	}
-	//nolint:errcheck
-	defer os.Remove("go.mod") // clean up after ourselves
-	return findGoModulePath()
+	return path, nil
 }
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package golang
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+)
+
+// gopathRunner stubs the go commands of a directory without a go module or a package.
+type gopathRunner struct {
+	gopath string
+}
+
+func (r gopathRunner) Run(_ context.Context, cmd util.Command) error {
+	switch cmd.String() {
+	case "go mod edit -json":
+		return errors.New("go: no go.mod file")
+	case "go list -f {{.ImportPath}} .":
+		_, err := cmd.Stdout.Write([]byte("_/project\n"))
+		return err
+	case "go env GOPATH":
+		_, err := cmd.Stdout.Write([]byte(r.gopath + "\n"))
+		return err
+	}
+	return errors.New("unexpected command")
+}
+
+var _ = Describe("FindCurrentRepo", func() {
+	gopath := filepath.FromSlash("/go")
+
+	withDir := func(dir string) context.Context {
+		return util.WithEnvironment(context.Background(),
+			&util.Environment{Dir: dir, Runner: gopathRunner{gopath: gopath}})
+	}
+
+	It("should guess the repository from the location of the project inside GOPATH", func() {
+		Expect(FindCurrentRepo(withDir(filepath.Join(gopath, "src", "example.com", "project")))).
+			To(Equal("example.com/project"))
+	})
+
+	It("should fail if the project is outside GOPATH", func() {
+		_, err := FindCurrentRepo(withDir(filepath.FromSlash("/project")))
+		Expect(err).To(HaveOccurred())
+	})
+})
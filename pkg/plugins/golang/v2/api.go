@@ -17,10 +17,9 @@ limitations under the License.
 package v2
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
-	"os"
 
 	"github.com/spf13/pflag"
 
@@ -37,6 +36,7 @@ var _ plugin.CreateAPISubcommand = &createAPISubcommand{}
 
 type createAPISubcommand struct {
 	config config.Config
+	env    *util.Environment
 
 	options *goPlugin.Options
 
@@ -104,6 +104,11 @@ func (p *createAPISubcommand) BindFlags(fs *pflag.FlagSet) {
 	p.controllerFlag = fs.Lookup("controller")
 }
 
+func (p *createAPISubcommand) InjectEnvironment(env *util.Environment) error {
+	p.env = env
+	return nil
+}
+
 func (p *createAPISubcommand) InjectConfig(c config.Config) error {
 	p.config = c
 
@@ -118,14 +123,19 @@ func (p *createAPISubcommand) InjectResource(res *resource.Resource) error {
 	}
 
 	// Ask for API and Controller if not specified
-	reader := bufio.NewReader(os.Stdin)
 	if !p.resourceFlag.Changed {
-		fmt.Println("Create Resource [y/n]")
-		p.options.DoAPI = util.YesNo(reader)
+		doAPI, err := p.env.Confirm("Create Resource", p.resourceFlag.Name)
+		if err != nil {
+			return err
+		}
+		p.options.DoAPI = doAPI
 	}
 	if !p.controllerFlag.Changed {
-		fmt.Println("Create Controller [y/n]")
-		p.options.DoController = util.YesNo(reader)
+		doController, err := p.env.Confirm("Create Controller", p.controllerFlag.Name)
+		if err != nil {
+			return err
+		}
+		p.options.DoController = doController
 	}
 
 	p.options.UpdateResource(p.resource, p.config)
@@ -158,17 +168,22 @@ func (p *createAPISubcommand) Scaffold(fs machinery.Filesystem) error {
 }
 
 func (p *createAPISubcommand) PostScaffold() error {
-	err := util.RunCmd("Update dependencies", "go", "mod", "tidy")
+	return p.PostScaffoldWithContext(context.Background())
+}
+
+func (p *createAPISubcommand) PostScaffoldWithContext(ctx context.Context) error {
+	err := util.RunCmdWithContext(ctx, "Update dependencies", "go", "mod", "tidy")
 	if err != nil {
 		return err
 	}
 
 	if p.runMake && p.resource.HasAPI() {
-		err = util.RunCmd("Running make", "make", "generate")
+		err = util.RunCmdWithContext(ctx, "Running make", "make", "generate")
 		if err != nil {
 			return err
 		}
-		fmt.Print("Next: implement your new API and generate the manifests (e.g. CRDs,CRs) with:\n$ make manifests \n")
+		fmt.Fprint(p.env.OutOrStdout(),
+			"Next: implement your new API and generate the manifests (e.g. CRDs,CRs) with:\n$ make manifests \n")
 	}
 
 	return nil
@@ -17,8 +17,8 @@ limitations under the License.
 package v2
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -44,6 +44,7 @@ var _ plugin.InitSubcommand = &initSubcommand{}
 
 type initSubcommand struct {
 	config config.Config
+	env    *util.Environment
 
 	// For help text.
 	commandName string
@@ -99,6 +100,11 @@ func (p *initSubcommand) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&p.name, "project-name", "", "name of this project")
 }
 
+func (p *initSubcommand) InjectEnvironment(env *util.Environment) error {
+	p.env = env
+	return nil
+}
+
 func (p *initSubcommand) InjectConfig(c config.Config) error {
 	p.config = c
 
@@ -106,22 +112,10 @@ func (p *initSubcommand) InjectConfig(c config.Config) error {
 		return err
 	}
 
-	// Try to guess repository if flag is not set.
-	if p.repo == "" {
-		repoPath, err := golang.FindCurrentRepo()
-		if err != nil {
-			return fmt.Errorf("error finding current repository: %v", err)
-		}
-		p.repo = repoPath
-	}
-	if err := p.config.SetRepository(p.repo); err != nil {
-		return err
-	}
-
 	if p.config.GetVersion().Compare(cfgv2.Version) > 0 {
 		// Assign a default project name
 		if p.name == "" {
-			dir, err := os.Getwd()
+			dir, err := p.env.Getwd()
 			if err != nil {
 				return fmt.Errorf("error getting current directory: %v", err)
 			}
@@ -139,18 +133,34 @@ func (p *initSubcommand) InjectConfig(c config.Config) error {
 	return nil
 }
 
-func (p *initSubcommand) PreScaffold(machinery.Filesystem) error {
+func (p *initSubcommand) PreScaffold(fs machinery.Filesystem) error {
+	return p.PreScaffoldWithContext(context.Background(), fs)
+}
+
+func (p *initSubcommand) PreScaffoldWithContext(ctx context.Context, fs machinery.Filesystem) error {
 	// Ensure Go version is in the allowed range if check not turned off.
 	if !p.skipGoVersionCheck {
-		if err := golang.ValidateGoVersion(goVerMin, goVerMax); err != nil {
+		if err := golang.ValidateGoVersion(ctx, goVerMin, goVerMax); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	// Try to guess repository if flag is not set.
+	if p.repo == "" {
+		repoPath, err := golang.FindCurrentRepo(ctx)
+		if err != nil {
+			return fmt.Errorf("error finding current repository: %v", err)
+		}
+		p.repo = repoPath
+	}
+	return p.config.SetRepository(p.repo)
 }
 
 func (p *initSubcommand) Scaffold(fs machinery.Filesystem) error {
+	return p.ScaffoldWithContext(context.Background(), fs)
+}
+
+func (p *initSubcommand) ScaffoldWithContext(ctx context.Context, fs machinery.Filesystem) error {
 	scaffolder := scaffolds.NewInitScaffolder(p.config, p.license, p.owner)
 	scaffolder.InjectFS(fs)
 	err := scaffolder.Scaffold()
@@ -159,13 +169,13 @@ func (p *initSubcommand) Scaffold(fs machinery.Filesystem) error {
 	}
 
 	if !p.fetchDeps {
-		fmt.Println("Skipping fetching dependencies.")
+		fmt.Fprintln(p.env.OutOrStdout(), "Skipping fetching dependencies.")
 		return nil
 	}
 
 	// Ensure that we are pinning controller-runtime version
 	// xref: https://github.com/kubernetes-sigs/kubebuilder/issues/997
-	err = util.RunCmd("Get controller runtime", "go", "get",
+	err = util.RunCmdWithContext(ctx, "Get controller runtime", "go", "get",
 		"sigs.k8s.io/controller-runtime@"+scaffolds.ControllerRuntimeVersion)
 	if err != nil {
 		return err
@@ -175,11 +185,15 @@ func (p *initSubcommand) Scaffold(fs machinery.Filesystem) error {
 }
 
 func (p *initSubcommand) PostScaffold() error {
-	err := util.RunCmd("Update dependencies", "go", "mod", "tidy")
+	return p.PostScaffoldWithContext(context.Background())
+}
+
+func (p *initSubcommand) PostScaffoldWithContext(ctx context.Context) error {
+	err := util.RunCmdWithContext(ctx, "Update dependencies", "go", "mod", "tidy")
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Next: define a resource with:\n$ %s create api\n", p.commandName)
+	fmt.Fprintf(p.env.OutOrStdout(), "Next: define a resource with:\n$ %s create api\n", p.commandName)
 	return nil
 }
@@ -17,9 +17,12 @@ limitations under the License.
 package v2
 
 import (
+	"path/filepath"
+
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	cfgv2 "sigs.k8s.io/kubebuilder/v3/pkg/config/v2"
 	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugins"
 )
@@ -31,7 +34,10 @@ var (
 	supportedProjectVersions = []config.Version{cfgv2.Version, cfgv3.Version}
 )
 
-var _ plugin.Full = Plugin{}
+var (
+	_ plugin.Full               = Plugin{}
+	_ plugin.HasScaffoldedFiles = Plugin{}
+)
 
 // Plugin implements the plugin.Full interface
 type Plugin struct {
@@ -50,6 +56,33 @@ func (Plugin) Version() plugin.Version { return pluginVersion }
 // SupportedProjectVersions returns an array with all project versions supported by the plugin
 func (Plugin) SupportedProjectVersions() []config.Version { return supportedProjectVersions }
 
+// ScaffoldedFiles returns the files scaffolded by the plugin for the resource
+func (Plugin) ScaffoldedFiles(res resource.Resource, multiGroup bool) []string {
+	apiDir := filepath.Join("api", "%[version]")
+	controllersDir := "controllers"
+	if multiGroup {
+		apiDir = filepath.Join("apis", "%[group]", "%[version]")
+		controllersDir = filepath.Join("controllers", "%[group]")
+	}
+
+	var paths []string
+	if res.HasAPI() {
+		paths = append(paths, filepath.Join(apiDir, "%[kind]_types.go"))
+	}
+	if res.HasController() {
+		paths = append(paths, filepath.Join(controllersDir, "%[kind]_controller.go"))
+	}
+	if res.Webhooks != nil && !res.Webhooks.IsEmpty() {
+		paths = append(paths, filepath.Join(apiDir, "%[kind]_webhook.go"))
+	}
+
+	replacer := res.Replacer()
+	for i, path := range paths {
+		paths[i] = replacer.Replace(path)
+	}
+	return paths
+}
+
 // GetInitSubcommand will return the subcommand which is responsible for initializing and common scaffolding
 func (p Plugin) GetInitSubcommand() plugin.InitSubcommand { return &p.initSubcommand }
 
@@ -32,6 +32,7 @@ type Types struct {
 	machinery.MultiGroupMixin
 	machinery.BoilerplateMixin
 	machinery.ResourceMixin
+	machinery.EnvironmentMixin
 
 	Force bool
 }
@@ -46,7 +47,7 @@ func (f *Types) SetTemplateDefaults() error {
 		}
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	fmt.Fprintln(f.Environment.OutOrStdout(), f.Path)
 
 	f.TemplateBody = typesTemplate
 
@@ -32,6 +32,7 @@ type Webhook struct { // nolint:maligned
 	machinery.MultiGroupMixin
 	machinery.BoilerplateMixin
 	machinery.ResourceMixin
+	machinery.EnvironmentMixin
 
 	// Is the Group domain for the Resource replacing '.' with '-'
 	QualifiedGroupWithDash string
@@ -47,7 +48,7 @@ func (f *Webhook) SetTemplateDefaults() error {
 		}
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	fmt.Fprintln(f.Environment.OutOrStdout(), f.Path)
 
 	webhookTemplate := webhookTemplate
 	if f.Resource.HasDefaultingWebhook() {
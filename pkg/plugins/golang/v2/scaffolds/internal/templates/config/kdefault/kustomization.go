@@ -17,7 +17,6 @@ limitations under the License.
 package kdefault
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -30,6 +29,7 @@ var _ machinery.Template = &Kustomization{}
 type Kustomization struct {
 	machinery.TemplateMixin
 	machinery.ProjectNameMixin
+	machinery.EnvironmentMixin
 }
 
 // SetTemplateDefaults implements file.Template
@@ -44,7 +44,7 @@ func (f *Kustomization) SetTemplateDefaults() error {
 
 	if f.ProjectName == "" {
 		// Use directory name as project name, which will be empty if the project version is < v3.
-		dir, err := os.Getwd()
+		dir, err := f.Environment.Getwd()
 		if err != nil {
 			return err
 		}
@@ -32,6 +32,7 @@ type Controller struct {
 	machinery.MultiGroupMixin
 	machinery.BoilerplateMixin
 	machinery.ResourceMixin
+	machinery.EnvironmentMixin
 
 	ControllerRuntimeVersion string
 
@@ -48,7 +49,7 @@ func (f *Controller) SetTemplateDefaults() error {
 		}
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	fmt.Fprintln(f.Environment.OutOrStdout(), f.Path)
 
 	f.TemplateBody = controllerTemplate
 
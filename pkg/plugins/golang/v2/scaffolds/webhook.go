@@ -55,7 +55,7 @@ func (s *webhookScaffolder) InjectFS(fs machinery.Filesystem) {
 
 // Scaffold implements cmdutil.Scaffolder
 func (s *webhookScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	fmt.Fprintln(s.fs.Environment.OutOrStdout(), "Writing scaffold for you to edit...")
 
 	// Load the boilerplate
 	boilerplate, err := afero.ReadFile(s.fs.FS, hack.DefaultBoilerplatePath)
@@ -82,7 +82,7 @@ func (s *webhookScaffolder) Scaffold() error {
 	}
 
 	if s.resource.HasConversionWebhook() {
-		fmt.Println(`Webhook server has been set up for you.
+		fmt.Fprintln(s.fs.Environment.OutOrStdout(), `Webhook server has been set up for you.
 You need to implement the conversion.Hub and conversion.Convertible interfaces for your CRD types.`)
 	}
 
@@ -17,7 +17,7 @@ limitations under the License.
 package v3
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -45,6 +45,7 @@ var _ plugin.CreateAPISubcommand = &createAPISubcommand{}
 
 type createAPISubcommand struct {
 	config config.Config
+	env    *util.Environment
 
 	options *goPlugin.Options
 
@@ -57,6 +58,9 @@ type createAPISubcommand struct {
 	// force indicates that the resource should be created even if it already exists
 	force bool
 
+	// templatesDir is the directory with the files that replace the built-in templates
+	templatesDir string
+
 	// runMake indicates whether to run make or not after scaffolding APIs
 	runMake bool
 }
@@ -97,7 +101,8 @@ func (p *createAPISubcommand) BindFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&p.runMake, "make", true, "if true, run `make generate` after generating files")
 
 	fs.BoolVar(&p.force, "force", false,
-		"attempt to create resource even if it already exists")
+		"attempt to create resource even if it already exists, merging the changes made to the existing files")
+	fs.StringVar(&p.templatesDir, templatesDirFlag, "", templatesDirUsage+", defaults to the one in the PROJECT file")
 
 	p.options = &goPlugin.Options{}
 
@@ -109,6 +114,20 @@ func (p *createAPISubcommand) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&p.options.CRDVersion, "crd-version", defaultCRDVersion,
 		"version of CustomResourceDefinition to scaffold. Options: [v1, v1beta1]")
 	fs.BoolVar(&p.options.Namespaced, "namespaced", true, "resource is namespaced")
+	fs.StringSliceVar(&p.options.ShortNames, "short-names", nil,
+		"short names of the resource (requires project version 4)")
+	fs.StringSliceVar(&p.options.Categories, "categories", nil,
+		"categories the resource belongs to (requires project version 4)")
+	fs.StringSliceVar(&p.options.Subresources, "subresources", nil,
+		fmt.Sprintf("subresources to enable, %q if not set (requires project version 4). Options: [%s, %s]",
+			goPlugin.StatusSubresource, goPlugin.StatusSubresource, goPlugin.ScaleSubresource))
+	fs.BoolVar(&p.options.StorageVersion, "storage-version", false,
+		"if set, the version of the resource is marked as the storage version (requires project version 4)")
+	fs.StringVar(&p.options.Owner, "owner", "",
+		"team or person that owns the resource (requires project version 4)")
+	fs.StringVar(&p.options.Module, "module", "",
+		"Go module that provides the API types, which are then not scaffolded but imported from the package "+
+			"they would have in a project with this module path (requires project version 4)")
 
 	fs.BoolVar(&p.options.DoController, "controller", true,
 		"if set, generate the controller without prompting the user")
@@ -119,26 +138,63 @@ func (p *createAPISubcommand) BindFlags(fs *pflag.FlagSet) {
 	fs.MarkDeprecated("crd-version", deprecateMsg)
 }
 
+// SharedFlags implements plugin.SharesFlags so that `--force` also applies to the kustomize plugin.
+func (p *createAPISubcommand) SharedFlags() []string { return []string{"force"} }
+
+func (p *createAPISubcommand) InjectEnvironment(env *util.Environment) error {
+	p.env = env
+	return nil
+}
+
 func (p *createAPISubcommand) InjectConfig(c config.Config) error {
 	p.config = c
 
+	// The templates directory provided by flag takes precedence over the one stored in the project configuration
+	if p.templatesDir == "" {
+		templatesDir, err := getTemplatesDir(p.config)
+		if err != nil {
+			return err
+		}
+		p.templatesDir = templatesDir
+	}
+
 	return nil
 }
 
 func (p *createAPISubcommand) InjectResource(res *resource.Resource) error {
 	p.resource = res
 
+	// The API types of resources provided by other modules are not scaffolded
+	if p.options.Module != "" {
+		if p.resourceFlag.Changed && p.options.DoAPI {
+			return fmt.Errorf("the API of resources provided by module %q can not be scaffolded", p.options.Module)
+		}
+		p.options.DoAPI = false
+	}
+
 	// TODO: re-evaluate whether y/n input still makes sense. We should probably always
 	//       scaffold the resource and controller.
 	// Ask for API and Controller if not specified
-	reader := bufio.NewReader(os.Stdin)
-	if !p.resourceFlag.Changed {
-		fmt.Println("Create Resource [y/n]")
-		p.options.DoAPI = util.YesNo(reader)
+	if !p.resourceFlag.Changed && p.options.Module == "" {
+		doAPI, err := p.env.Confirm("Create Resource", p.resourceFlag.Name)
+		if err != nil {
+			return err
+		}
+		p.options.DoAPI = doAPI
 	}
 	if !p.controllerFlag.Changed {
-		fmt.Println("Create Controller [y/n]")
-		p.options.DoController = util.YesNo(reader)
+		doController, err := p.env.Confirm("Create Controller", p.controllerFlag.Name)
+		if err != nil {
+			return err
+		}
+		p.options.DoController = doController
+	}
+
+	for _, subresource := range p.options.Subresources {
+		if subresource != goPlugin.StatusSubresource && subresource != goPlugin.ScaleSubresource {
+			return fmt.Errorf("invalid subresource %q, it must be %q or %q",
+				subresource, goPlugin.StatusSubresource, goPlugin.ScaleSubresource)
+		}
 	}
 
 	p.options.UpdateResource(p.resource, p.config)
@@ -170,9 +226,9 @@ func (p *createAPISubcommand) InjectResource(res *resource.Resource) error {
 	return nil
 }
 
-func (p *createAPISubcommand) PreScaffold(machinery.Filesystem) error {
+func (p *createAPISubcommand) PreScaffold(fs machinery.Filesystem) error {
 	// check if main.go is present in the root directory
-	if _, err := os.Stat(DefaultMainPath); os.IsNotExist(err) {
+	if _, err := fs.FS.Stat(DefaultMainPath); os.IsNotExist(err) {
 		return fmt.Errorf("%s file should present in the root directory", DefaultMainPath)
 	}
 
@@ -180,32 +236,40 @@ func (p *createAPISubcommand) PreScaffold(machinery.Filesystem) error {
 }
 
 func (p *createAPISubcommand) Scaffold(fs machinery.Filesystem) error {
-	scaffolder := scaffolds.NewAPIScaffolder(p.config, *p.resource, p.force)
+	scaffolder := scaffolds.NewAPIScaffolder(p.config, *p.resource, p.force, p.templatesDir)
 	scaffolder.InjectFS(fs)
-	return scaffolder.Scaffold()
-}
-
-func (p *createAPISubcommand) PostScaffold() error {
+	if err := scaffolder.Scaffold(); err != nil {
+		return err
+	}
 
 	// Update the makefile to allow generate Webhooks to ensure backwards compatibility
 	// todo: it should be removed for go/v4
 	// nolint:lll,gosec
 	if p.resource.API.CRDVersion == "v1beta1" {
-		if err := applyScaffoldCustomizationsForVbeta1(); err != nil {
+		if err := applyScaffoldCustomizationsForVbeta1(fs); err != nil {
 			return err
 		}
 	}
 
-	err := util.RunCmd("Update dependencies", "go", "mod", "tidy")
+	return nil
+}
+
+func (p *createAPISubcommand) PostScaffold() error {
+	return p.PostScaffoldWithContext(context.Background())
+}
+
+func (p *createAPISubcommand) PostScaffoldWithContext(ctx context.Context) error {
+	err := util.RunCmdWithContext(ctx, "Update dependencies", "go", "mod", "tidy")
 	if err != nil {
 		return err
 	}
 	if p.runMake && p.resource.HasAPI() {
-		err = util.RunCmd("Running make", "make", "generate")
+		err = util.RunCmdWithContext(ctx, "Running make", "make", "generate")
 		if err != nil {
 			return err
 		}
-		fmt.Print("Next: implement your new API and generate the manifests (e.g. CRDs,CRs) with:\n$ make manifests\n")
+		fmt.Fprint(p.env.OutOrStdout(),
+			"Next: implement your new API and generate the manifests (e.g. CRDs,CRs) with:\n$ make manifests\n")
 	}
 
 	return nil
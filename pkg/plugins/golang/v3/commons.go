@@ -17,14 +17,16 @@ limitations under the License.
 package v3
 
 import (
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"path/filepath"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
 
-	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds"
 )
 
@@ -33,11 +35,43 @@ const deprecateMsg = "The v1beta1 API version for CRDs and Webhooks are deprecat
 	"recommend you no longer use these API versions." +
 	"More info: https://kubernetes.io/docs/reference/using-api/deprecation-guide/#v1-22"
 
-// Update the makefile to allow generate CRDs/Webhooks with v1beta1 to ensure backwards compatibility
+const (
+	templatesDirFlag  = "templates-dir"
+	templatesDirUsage = "directory with files that replace the built-in templates, each named after the path of " +
+		"the file it scaffolds followed by \".tmpl\", e.g. \"controllers/%[kind]_controller.go.tmpl\""
+)
+
+// getTemplatesDir returns the templates directory stored in the project configuration
+func getTemplatesDir(c config.Config) (string, error) {
+	cfg := pluginConfig{}
+	if err := c.DecodePluginConfig(pluginKey, &cfg); err != nil &&
+		!errors.As(err, &config.PluginKeyNotFoundError{}) && !errors.As(err, &config.UnsupportedFieldError{}) {
+		return "", err
+	}
+
+	return cfg.TemplatesDir, nil
+}
+
+// setTemplatesDir stores the templates directory in the project configuration
+func setTemplatesDir(c config.Config, templatesDir string) error {
+	cfg := pluginConfig{}
+	if err := c.DecodePluginConfig(pluginKey, &cfg); errors.As(err, &config.UnsupportedFieldError{}) {
+		return fmt.Errorf("the project configuration does not support storing the templates directory, " +
+			"upgrade it to a newer project version")
+	} else if err != nil && !errors.As(err, &config.PluginKeyNotFoundError{}) {
+		return err
+	}
+
+	cfg.TemplatesDir = templatesDir
+	return c.EncodePluginConfig(pluginKey, cfg)
+}
+
+// Update the makefile to allow generate CRDs/Webhooks with v1beta1 to ensure backwards compatibility.
+// The dependencies need to be updated afterwards as the go.mod file may be modified.
 // nolint:lll,gosec
-func applyScaffoldCustomizationsForVbeta1() error {
+func applyScaffoldCustomizationsForVbeta1(fs machinery.Filesystem) error {
 	makefilePath := filepath.Join("Makefile")
-	bs, err := ioutil.ReadFile(makefilePath)
+	bs, err := afero.ReadFile(fs.FS, makefilePath)
 	if err != nil {
 		return err
 	}
@@ -53,8 +87,9 @@ func applyScaffoldCustomizationsForVbeta1() error {
 		const makefileTarget = `$(CONTROLLER_GEN) rbac:roleName=manager-role crd webhook paths="./..." output:crd:artifacts:config=config/crd/bases`
 		const makefileTargetForV1beta1 = `$(CONTROLLER_GEN) $(CRD_OPTIONS) rbac:roleName=manager-role webhook paths="./..." output:crd:artifacts:config=config/crd/bases`
 
-		if err := util.ReplaceInFile("Makefile", makefileTarget, makefileTargetForV1beta1); err != nil {
-			fmt.Printf("unable to update the makefile to allow the usage of v1beta1: %s", err)
+		if err := replaceInFile(fs.FS, "Makefile", makefileTarget, makefileTargetForV1beta1); err != nil {
+			fmt.Fprintf(fs.Environment.OutOrStdout(),
+				"unable to update the makefile to allow the usage of v1beta1: %s", err)
 		}
 
 		const makegentarget = `
@@ -63,13 +98,13 @@ manifests: controller-gen`
 CRD_OPTIONS ?= "crd:crdVersions={v1beta1},trivialVersions=true,preserveUnknownFields=false"
 manifests: controller-gen`
 
-		if err := util.ReplaceInFile("Makefile", makegentarget, makegenV1beta1Options); err != nil {
+		if err := replaceInFile(fs.FS, "Makefile", makegentarget, makegenV1beta1Options); err != nil {
 			log.Warnf("unable to update the Makefile with %s: %s", makegenV1beta1Options, err)
 		}
 
 		// latest version of controller-tools where v1beta1 is supported
 		const controllerToolsVersionForVBeta1 = "v0.6.2"
-		if err := util.ReplaceInFile("Makefile",
+		if err := replaceInFile(fs.FS, "Makefile",
 			fmt.Sprintf("controller-gen@%s",
 				scaffolds.ControllerToolsVersion),
 			fmt.Sprintf("controller-gen@%s",
@@ -78,7 +113,7 @@ manifests: controller-gen`
 				controllerToolsVersionForVBeta1), err)
 		}
 
-		if err := util.ReplaceInFile("Makefile",
+		if err := replaceInFile(fs.FS, "Makefile",
 			"ENVTEST_K8S_VERSION = 1.22",
 			"ENVTEST_K8S_VERSION = 1.21"); err != nil {
 			log.Warnf("unable to update the Makefile with %s: %s", "ENVTEST_K8S_VERSION = 1.21", err)
@@ -87,35 +122,46 @@ manifests: controller-gen`
 		// latest version of controller-runtime where v1beta1 is supported
 		const controllerRuntimeVersionForVBeta1 = "v0.9.2"
 
-		if err := util.ReplaceInFile("go.mod",
+		if err := replaceInFile(fs.FS, "go.mod",
 			fmt.Sprintf("sigs.k8s.io/controller-runtime %s", scaffolds.ControllerRuntimeVersion),
 			fmt.Sprintf("sigs.k8s.io/controller-runtime %s", controllerRuntimeVersionForVBeta1)); err != nil {
 			log.Warnf("unable to update the go.mod with sigs.k8s.io/controller-runtime %s: %s",
 				controllerRuntimeVersionForVBeta1, err)
 		}
 
-		if err := util.ReplaceInFile("go.mod",
+		if err := replaceInFile(fs.FS, "go.mod",
 			"k8s.io/api v0.22.1",
 			"k8s.io/api v0.21.2"); err != nil {
 			log.Warnf("unable to update the go.mod with k8s.io/api v0.21.2: %s", err)
 		}
 
-		if err := util.ReplaceInFile("go.mod",
+		if err := replaceInFile(fs.FS, "go.mod",
 			"k8s.io/apimachinery v0.22.1",
 			"k8s.io/apimachinery v0.21.2"); err != nil {
 			log.Warnf("unable to update the go.mod with k8s.io/apimachinery v0.21.2: %s", err)
 		}
 
-		if err := util.ReplaceInFile("go.mod",
+		if err := replaceInFile(fs.FS, "go.mod",
 			"k8s.io/apimachinery v0.22.1",
 			"k8s.io/apimachinery v0.21.2"); err != nil {
 			log.Warnf("unable to update the go.mod with k8s.io/apimachinery v0.21.2: %s", err)
 		}
-
-		err = util.RunCmd("Update dependencies", "go", "mod", "tidy")
-		if err != nil {
-			return err
-		}
 	}
 	return nil
 }
+
+// replaceInFile replaces all instances of old with new in the file at path.
+func replaceInFile(fs afero.Fs, path, old, new string) error {
+	info, err := fs.Stat(path)
+	if err != nil {
+		return err
+	}
+	b, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(b), old) {
+		return errors.New("unable to find the content to be replaced")
+	}
+	return afero.WriteFile(fs, path, []byte(strings.Replace(string(b), old, new, -1)), info.Mode())
+}
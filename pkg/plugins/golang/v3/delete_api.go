@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin/util"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds"
+)
+
+var _ plugin.DeleteAPISubcommand = &deleteAPISubcommand{}
+
+type deleteAPISubcommand struct {
+	config config.Config
+	env    *util.Environment
+
+	resource *resource.Resource
+
+	// runMake indicates whether to run make or not after deleting APIs
+	runMake bool
+}
+
+func (p *deleteAPISubcommand) UpdateMetadata(cliMeta plugin.CLIMetadata, subcmdMeta *plugin.SubcommandMetadata) {
+	subcmdMeta.Description = `Delete a Kubernetes API scaffolded with the create api subcommand.
+
+The Resource definition and Controller files are deleted and they are no longer
+registered in main.go. The webhooks of the resource need to be deleted first.
+
+After the files are deleted, make generate will be run.
+`
+	subcmdMeta.Examples = fmt.Sprintf(`  # Delete the frigates API with Group: ship, Version: v1beta1 and Kind: Frigate
+  %[1]s delete api --group ship --version v1beta1 --kind Frigate
+
+  # Regenerate the manifests
+  make manifests
+`, cliMeta.CommandName)
+}
+
+func (p *deleteAPISubcommand) BindFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&p.runMake, "make", true, "if true, run `make generate` after deleting files")
+}
+
+func (p *deleteAPISubcommand) InjectEnvironment(env *util.Environment) error {
+	p.env = env
+	return nil
+}
+
+func (p *deleteAPISubcommand) InjectConfig(c config.Config) error {
+	p.config = c
+
+	return nil
+}
+
+func (p *deleteAPISubcommand) InjectResource(res *resource.Resource) error {
+	r, err := p.config.GetResource(res.GVK)
+	if err != nil {
+		return err
+	}
+
+	if r.Webhooks != nil && !r.Webhooks.IsEmpty() {
+		return fmt.Errorf("resource %v has webhooks, they need to be deleted first", r.GVK)
+	}
+
+	*res = r
+	p.resource = res
+
+	return nil
+}
+
+func (p *deleteAPISubcommand) Scaffold(fs machinery.Filesystem) error {
+	scaffolder := scaffolds.NewDeleteAPIScaffolder(p.config, *p.resource)
+	scaffolder.InjectFS(fs)
+	return scaffolder.Scaffold()
+}
+
+func (p *deleteAPISubcommand) PostScaffold() error {
+	return p.PostScaffoldWithContext(context.Background())
+}
+
+func (p *deleteAPISubcommand) PostScaffoldWithContext(ctx context.Context) error {
+	if p.runMake && p.resource.HasAPI() {
+		if err := util.RunCmdWithContext(ctx, "Running make", "make", "generate"); err != nil {
+			return err
+		}
+		fmt.Fprint(p.env.OutOrStdout(), "Next: regenerate the manifests (e.g. CRDs,RBAC) with:\n$ make manifests\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds"
+)
+
+var _ plugin.DeleteWebhookSubcommand = &deleteWebhookSubcommand{}
+
+type deleteWebhookSubcommand struct {
+	config config.Config
+
+	resource *resource.Resource
+}
+
+func (p *deleteWebhookSubcommand) UpdateMetadata(cliMeta plugin.CLIMetadata, subcmdMeta *plugin.SubcommandMetadata) {
+	subcmdMeta.Description = `Delete the webhooks scaffolded with the create webhook subcommand.
+
+The webhook file is deleted and the webhooks are no longer registered in main.go.
+`
+	subcmdMeta.Examples = fmt.Sprintf(`  # Delete the webhooks of the Group: ship, Version: v1beta1 and Kind: Frigate resource
+  %[1]s delete webhook --group ship --version v1beta1 --kind Frigate
+
+  # Regenerate the manifests
+  make manifests
+`, cliMeta.CommandName)
+}
+
+func (p *deleteWebhookSubcommand) InjectConfig(c config.Config) error {
+	p.config = c
+
+	return nil
+}
+
+func (p *deleteWebhookSubcommand) InjectResource(res *resource.Resource) error {
+	r, err := p.config.GetResource(res.GVK)
+	if err != nil {
+		return err
+	}
+
+	if r.Webhooks == nil || r.Webhooks.IsEmpty() {
+		return fmt.Errorf("resource %v has no webhooks", r.GVK)
+	}
+
+	*res = r
+	p.resource = res
+
+	return nil
+}
+
+func (p *deleteWebhookSubcommand) Scaffold(fs machinery.Filesystem) error {
+	scaffolder := scaffolds.NewDeleteWebhookScaffolder(p.config, *p.resource)
+	scaffolder.InjectFS(fs)
+	return scaffolder.Scaffold()
+}
@@ -33,28 +33,40 @@ type editSubcommand struct {
 	config config.Config
 
 	multigroup bool
+
+	// templatesDir is the directory with the files that replace the built-in templates
+	templatesDir string
 }
 
 func (p *editSubcommand) UpdateMetadata(cliMeta plugin.CLIMetadata, subcmdMeta *plugin.SubcommandMetadata) {
 	subcmdMeta.Description = `This command will edit the project configuration.
 Features supported:
   - Toggle between single or multi group projects.
+  - Set the directory with the files that replace the built-in templates.
 `
 	subcmdMeta.Examples = fmt.Sprintf(`  # Enable the multigroup layout
   %[1]s edit --multigroup
 
   # Disable the multigroup layout
   %[1]s edit --multigroup=false
+
+  # Replace the built-in templates with the files in the hack/templates directory
+  %[1]s edit --templates-dir hack/templates
 `, cliMeta.CommandName)
 }
 
 func (p *editSubcommand) BindFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&p.multigroup, "multigroup", false, "enable or disable multigroup layout")
+	fs.StringVar(&p.templatesDir, templatesDirFlag, "", templatesDirUsage)
 }
 
 func (p *editSubcommand) InjectConfig(c config.Config) error {
 	p.config = c
 
+	if p.templatesDir != "" {
+		return setTemplatesDir(p.config, p.templatesDir)
+	}
+
 	return nil
 }
 
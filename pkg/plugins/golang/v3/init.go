@@ -17,12 +17,14 @@ limitations under the License.
 package v3
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"unicode"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
@@ -43,6 +45,7 @@ var _ plugin.InitSubcommand = &initSubcommand{}
 
 type initSubcommand struct {
 	config config.Config
+	env    *util.Environment
 	// For help text.
 	commandName string
 
@@ -53,6 +56,9 @@ type initSubcommand struct {
 	// go config options
 	repo string
 
+	// templatesDir is the directory with the files that replace the built-in templates
+	templatesDir string
+
 	// flags
 	fetchDeps          bool
 	skipGoVersionCheck bool
@@ -91,37 +97,62 @@ func (p *initSubcommand) BindFlags(fs *pflag.FlagSet) {
 	// project args
 	fs.StringVar(&p.repo, "repo", "", "name to use for go module (e.g., github.com/user/repo), "+
 		"defaults to the go package of the current working directory.")
+
+	// template args
+	fs.StringVar(&p.templatesDir, templatesDirFlag, "", templatesDirUsage)
+}
+
+func (p *initSubcommand) InjectEnvironment(env *util.Environment) error {
+	p.env = env
+	return nil
 }
 
 func (p *initSubcommand) InjectConfig(c config.Config) error {
 	p.config = c
 
-	// Try to guess repository if flag is not set.
-	if p.repo == "" {
-		repoPath, err := golang.FindCurrentRepo()
-		if err != nil {
-			return fmt.Errorf("error finding current repository: %v", err)
+	if p.templatesDir != "" {
+		if err := setTemplatesDir(p.config, p.templatesDir); err != nil {
+			return err
 		}
-		p.repo = repoPath
 	}
 
-	return p.config.SetRepository(p.repo)
+	return nil
 }
 
-func (p *initSubcommand) PreScaffold(machinery.Filesystem) error {
+func (p *initSubcommand) PreScaffold(fs machinery.Filesystem) error {
+	return p.PreScaffoldWithContext(context.Background(), fs)
+}
+
+func (p *initSubcommand) PreScaffoldWithContext(ctx context.Context, fs machinery.Filesystem) error {
 	// Ensure Go version is in the allowed range if check not turned off.
 	if !p.skipGoVersionCheck {
-		if err := golang.ValidateGoVersion(goVerMin, goVerMax); err != nil {
+		if err := golang.ValidateGoVersion(ctx, goVerMin, goVerMax); err != nil {
 			return err
 		}
 	}
 
 	// Check if the current directory has not files or directories which does not allow to init the project
-	return checkDir()
+	if err := checkDir(fs.FS, p.templatesDir); err != nil {
+		return err
+	}
+
+	// Try to guess repository if flag is not set.
+	if p.repo == "" {
+		repoPath, err := golang.FindCurrentRepo(ctx)
+		if err != nil {
+			return fmt.Errorf("error finding current repository: %v", err)
+		}
+		p.repo = repoPath
+	}
+	return p.config.SetRepository(p.repo)
 }
 
 func (p *initSubcommand) Scaffold(fs machinery.Filesystem) error {
-	scaffolder := scaffolds.NewInitScaffolder(p.config, p.license, p.owner)
+	return p.ScaffoldWithContext(context.Background(), fs)
+}
+
+func (p *initSubcommand) ScaffoldWithContext(ctx context.Context, fs machinery.Filesystem) error {
+	scaffolder := scaffolds.NewInitScaffolder(p.config, p.license, p.owner, p.templatesDir)
 	scaffolder.InjectFS(fs)
 	err := scaffolder.Scaffold()
 	if err != nil {
@@ -129,13 +160,13 @@ func (p *initSubcommand) Scaffold(fs machinery.Filesystem) error {
 	}
 
 	if !p.fetchDeps {
-		fmt.Println("Skipping fetching dependencies.")
+		fmt.Fprintln(p.env.OutOrStdout(), "Skipping fetching dependencies.")
 		return nil
 	}
 
 	// Ensure that we are pinning controller-runtime version
 	// xref: https://github.com/kubernetes-sigs/kubebuilder/issues/997
-	err = util.RunCmd("Get controller runtime", "go", "get",
+	err = util.RunCmdWithContext(ctx, "Get controller runtime", "go", "get",
 		"sigs.k8s.io/controller-runtime@"+scaffolds.ControllerRuntimeVersion)
 	if err != nil {
 		return err
@@ -145,24 +176,42 @@ func (p *initSubcommand) Scaffold(fs machinery.Filesystem) error {
 }
 
 func (p *initSubcommand) PostScaffold() error {
-	err := util.RunCmd("Update dependencies", "go", "mod", "tidy")
+	return p.PostScaffoldWithContext(context.Background())
+}
+
+func (p *initSubcommand) PostScaffoldWithContext(ctx context.Context) error {
+	err := util.RunCmdWithContext(ctx, "Update dependencies", "go", "mod", "tidy")
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Next: define a resource with:\n$ %s create api\n", p.commandName)
+	fmt.Fprintf(p.env.OutOrStdout(), "Next: define a resource with:\n$ %s create api\n", p.commandName)
 	return nil
 }
 
 // checkDir will return error if the current directory has files which are not allowed.
 // Note that, it is expected that the directory to scaffold the project is cleaned.
 // Otherwise, it might face issues to do the scaffold.
-func checkDir() error {
-	err := filepath.Walk(".",
+// The templates directory is allowed as it needs to exist beforehand.
+func checkDir(fs afero.Fs, templatesDir string) error {
+	err := afero.Walk(fs, ".",
 		func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
+			// Allow the directory itself, whose name depends on how the filesystem is rooted
+			if path == "." {
+				return nil
+			}
+			// Allow the templates directory and the directories that contain it
+			if info.IsDir() && templatesDir != "" && path != "." {
+				if filepath.Clean(path) == filepath.Clean(templatesDir) {
+					return filepath.SkipDir
+				}
+				if strings.HasPrefix(filepath.Clean(templatesDir), filepath.Clean(path)+string(filepath.Separator)) {
+					return nil
+				}
+			}
 			// Allow directory trees starting with '.'
 			if info.IsDir() && strings.HasPrefix(info.Name(), ".") && info.Name() != "." {
 				return filepath.SkipDir
@@ -0,0 +1,225 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
+	goPlugin "sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds"
+)
+
+var (
+	_ plugin.MigrateSubcommand = &migrateSubcommand{}
+	_ plugin.RequiresReview    = &migrateSubcommand{}
+)
+
+type migrateSubcommand struct {
+	config config.Config
+
+	// filesToReview are the files that could not be migrated automatically
+	filesToReview []plugin.FileToReview
+}
+
+func (p *migrateSubcommand) UpdateMetadata(cliMeta plugin.CLIMetadata, subcmdMeta *plugin.SubcommandMetadata) {
+	subcmdMeta.Description += `
+The go/v3 plugin migrates projects scaffolded with the go/v2 plugin:
+  - The APIs, controllers and webhooks found in the project are tracked in the project configuration.
+  - The main.go, go.mod, Makefile, Dockerfile, .gitignore and .dockerignore files are regenerated.
+  - The files that need to be updated manually, e.g. the controllers for the new controller-runtime version,
+    are listed.
+`
+}
+
+func (p *migrateSubcommand) InjectConfig(c config.Config) error {
+	p.config = c
+
+	return nil
+}
+
+func (p *migrateSubcommand) PreScaffold(fs machinery.Filesystem) error {
+	p.filesToReview = nil
+
+	// Projects scaffolded with go/v2 only track the GVK of their resources,
+	// so the rest of the information is obtained from the files that were scaffolded for them
+	resources, err := p.config.GetResources()
+	if err != nil {
+		return fmt.Errorf("unable to load resources: %w", err)
+	}
+	for _, res := range resources {
+		if err := p.migrateResource(fs, res); err != nil {
+			return fmt.Errorf("unable to migrate resource %v: %w", res.GVK, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateResource updates the resource in the project configuration according to its scaffolded files
+func (p *migrateSubcommand) migrateResource(fs machinery.Filesystem, res resource.Resource) error {
+	apiDir := strings.TrimPrefix(resource.APIPackagePath("", res.Group, res.Version, p.config.IsMultiGroup()), "/")
+	controllersDir := "controllers"
+	if p.config.IsMultiGroup() && res.Group != "" {
+		controllersDir = filepath.Join(controllersDir, res.Group)
+	}
+	kind := strings.ToLower(res.Kind)
+	typesPath := filepath.Join(apiDir, kind+"_types.go")
+	webhookPath := filepath.Join(apiDir, kind+"_webhook.go")
+	controllerPath := filepath.Join(controllersDir, kind+"_controller.go")
+
+	options := goPlugin.Options{
+		CRDVersion:     defaultCRDVersion,
+		WebhookVersion: defaultWebhookVersion,
+		Namespaced:     true,
+	}
+
+	if types, err := afero.ReadFile(fs.FS, typesPath); err == nil {
+		options.DoAPI = true
+		options.Namespaced = !isClusterScoped(string(types))
+		p.review(typesPath, fmt.Sprintf("the CustomResourceDefinition is now generated with API version %s, "+
+			"which requires a structural schema", defaultCRDVersion))
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	var err error
+	if options.DoController, err = afero.Exists(fs.FS, controllerPath); err != nil {
+		return err
+	}
+	if options.DoController {
+		p.review(controllerPath, fmt.Sprintf("controller-runtime %s passes a context to Reconcile, "+
+			"update its signature to Reconcile(ctx context.Context, req ctrl.Request)",
+			scaffolds.ControllerRuntimeVersion))
+	}
+
+	if webhook, err := afero.ReadFile(fs.FS, webhookPath); err == nil && isWebhook(string(webhook)) {
+		options.DoDefaulting = strings.Contains(string(webhook), "Default()")
+		options.DoValidation = strings.Contains(string(webhook), "ValidateCreate()")
+		if options.DoConversion, err = implementsConversion(fs.FS, apiDir, res.Kind); err != nil {
+			return err
+		}
+		p.review(webhookPath, fmt.Sprintf("the webhook configurations are now generated with API version %s, "+
+			"add admissionReviewVersions=%s and sideEffects=None to the +kubebuilder:webhook markers",
+			defaultWebhookVersion, defaultWebhookVersion))
+		if !options.DoDefaulting && !options.DoValidation && !options.DoConversion {
+			p.review(webhookPath, fmt.Sprintf("the webhook neither defaults nor validates %s and no Hub or "+
+				"ConvertTo method was found for it, so it is not tracked; if it is a conversion webhook, "+
+				"set conversion: true for its webhooks in the PROJECT file and set it up in %s",
+				res.Kind, DefaultMainPath))
+		}
+	} else if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Resources are tracked with their domain since project version 3, so they are replaced
+	if err := p.config.RemoveResource(res.GVK); err != nil {
+		return err
+	}
+	res.Domain = p.config.GetDomain()
+	if res.Webhooks == nil {
+		res.Webhooks = &resource.Webhooks{}
+	}
+	options.UpdateResource(&res, p.config)
+	return p.config.UpdateResource(res)
+}
+
+func (p *migrateSubcommand) Scaffold(fs machinery.Filesystem) error {
+	templatesDir, err := getTemplatesDir(p.config)
+	if err != nil {
+		return err
+	}
+
+	scaffolder := scaffolds.NewMigrateScaffolder(p.config, templatesDir)
+	scaffolder.InjectFS(fs)
+	if err := scaffolder.Scaffold(); err != nil {
+		return err
+	}
+
+	p.review(DefaultMainPath, "the file was regenerated, add back any custom setup code")
+	p.review("go.mod", "the file was regenerated, add back any other dependency and run `go mod tidy`")
+	p.review("Makefile", "the file was regenerated, add back any custom target")
+	p.review("Dockerfile", "the file was regenerated, add back any custom build step")
+
+	return nil
+}
+
+func (p *migrateSubcommand) FilesToReview() []plugin.FileToReview {
+	return p.filesToReview
+}
+
+// review records a file that needs to be reviewed manually
+func (p *migrateSubcommand) review(path, reason string) {
+	p.filesToReview = append(p.filesToReview, plugin.FileToReview{Path: path, Reason: reason})
+}
+
+// isWebhook checks if the content of a webhook file registers a webhook with the manager
+func isWebhook(content string) bool {
+	return strings.Contains(content, "NewWebhookManagedBy")
+}
+
+// isClusterScoped checks if the content of a types file marks the resource as cluster-scoped
+// with the +kubebuilder:resource:scope=Cluster marker
+func isClusterScoped(content string) bool {
+	const resourceMarker = "+kubebuilder:resource:"
+	for _, line := range strings.Split(content, "\n") {
+		marker := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "//"))
+		if !strings.HasPrefix(marker, resourceMarker) {
+			continue
+		}
+		for _, arg := range strings.Split(strings.TrimPrefix(marker, resourceMarker), ",") {
+			if strings.TrimSpace(arg) == "scope=Cluster" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// implementsConversion checks if the Go files of an API package implement the conversion of kind,
+// either as the hub version, with a Hub method, or as a convertible version, with a ConvertTo method
+func implementsConversion(fs afero.Fs, apiDir, kind string) (bool, error) {
+	conversionMethod := regexp.MustCompile(
+		`func\s*\(\s*\w*\s*\*` + regexp.QuoteMeta(kind) + `\s*\)\s*(Hub|ConvertTo)\(`)
+
+	files, err := afero.ReadDir(fs, apiDir)
+	if err != nil {
+		return false, err
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".go" || strings.HasSuffix(file.Name(), "_test.go") {
+			continue
+		}
+		content, err := afero.ReadFile(fs, filepath.Join(apiDir, file.Name()))
+		if err != nil {
+			return false, err
+		}
+		if conversionMethod.Match(content) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
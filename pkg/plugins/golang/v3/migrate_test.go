@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/config/store/yaml"
+	cfgv2 "sigs.k8s.io/kubebuilder/v3/pkg/config/v2"
+	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+)
+
+var _ = Describe("migrateSubcommand", func() {
+	var (
+		fs         machinery.Filesystem
+		cfg        config.Config
+		subcommand *migrateSubcommand
+
+		gvk = func(kind string) resource.GVK {
+			return resource.GVK{Group: "crew", Domain: "testproject.org", Version: "v1", Kind: kind}
+		}
+	)
+
+	BeforeEach(func() {
+		// The project is read from the fixture and the changes are kept in memory
+		base := afero.NewReadOnlyFs(afero.NewBasePathFs(afero.NewOsFs(), filepath.Join("..", "..", "..", "..",
+			"testdata", "project-v2")))
+		fs = machinery.Filesystem{FS: afero.NewCopyOnWriteFs(base, afero.NewMemMapFs())}
+
+		store := yaml.New(fs)
+		Expect(store.Load()).To(Succeed())
+		Expect(store.Config().GetVersion().Compare(cfgv2.Version)).To(Equal(0))
+
+		var err error
+		cfg, err = config.ConvertTo(store.Config(), cfgv3.Version, "go.kubebuilder.io/v2")
+		Expect(err).NotTo(HaveOccurred())
+
+		subcommand = &migrateSubcommand{}
+		Expect(subcommand.InjectConfig(cfg)).To(Succeed())
+	})
+
+	It("should track the webhooks found in the project", func() {
+		Expect(subcommand.PreScaffold(fs)).To(Succeed())
+
+		captain, err := cfg.GetResource(gvk("Captain"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(captain.Webhooks.Defaulting).To(BeTrue())
+		Expect(captain.Webhooks.Validation).To(BeTrue())
+		Expect(captain.Webhooks.Conversion).To(BeFalse())
+
+		admiral, err := cfg.GetResource(gvk("Admiral"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(admiral.Webhooks.Defaulting).To(BeTrue())
+		Expect(admiral.Webhooks.Conversion).To(BeFalse())
+	})
+
+	It("should track the scope of the resources from their markers", func() {
+		Expect(subcommand.PreScaffold(fs)).To(Succeed())
+
+		captain, err := cfg.GetResource(gvk("Captain"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(captain.API.Namespaced).To(BeTrue())
+
+		admiral, err := cfg.GetResource(gvk("Admiral"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(admiral.API.Namespaced).To(BeFalse())
+	})
+
+	It("should list the webhooks that neither default, validate nor convert for review", func() {
+		Expect(subcommand.PreScaffold(fs)).To(Succeed())
+
+		firstMate, err := cfg.GetResource(gvk("FirstMate"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(firstMate.Webhooks.IsEmpty()).To(BeTrue())
+
+		var reasons []string
+		for _, file := range subcommand.FilesToReview() {
+			if file.Path == filepath.Join("api", "v1", "firstmate_webhook.go") {
+				reasons = append(reasons, file.Reason)
+			}
+		}
+		Expect(reasons).To(ContainElement(ContainSubstring("no Hub or ConvertTo method was found")))
+	})
+
+	DescribeTable("should track the conversion webhooks whose conversion methods are implemented",
+		func(method string) {
+			Expect(afero.WriteFile(fs.FS, filepath.Join("api", "v1", "firstmate_conversion.go"),
+				[]byte("package v1\n\n"+method+"\n"), 0600)).To(Succeed())
+
+			Expect(subcommand.PreScaffold(fs)).To(Succeed())
+
+			firstMate, err := cfg.GetResource(gvk("FirstMate"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(firstMate.Webhooks.Defaulting).To(BeFalse())
+			Expect(firstMate.Webhooks.Validation).To(BeFalse())
+			Expect(firstMate.Webhooks.Conversion).To(BeTrue())
+		},
+		Entry("in the hub version", "func (*FirstMate) Hub() {}"),
+		Entry("in a convertible version",
+			"func (src *FirstMate) ConvertTo(dstRaw conversion.Hub) error { return nil }"),
+	)
+
+	It("should wire the webhooks found in the project in main.go", func() {
+		Expect(subcommand.PreScaffold(fs)).To(Succeed())
+		Expect(subcommand.Scaffold(fs)).To(Succeed())
+
+		main, err := afero.ReadFile(fs.FS, DefaultMainPath)
+		Expect(err).NotTo(HaveOccurred())
+		for _, kind := range []string{"Captain", "Admiral"} {
+			Expect(string(main)).To(ContainSubstring("(&crewv1.%s{}).SetupWebhookWithManager(mgr)", kind))
+		}
+	})
+})
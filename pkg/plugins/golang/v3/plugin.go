@@ -17,8 +17,12 @@ limitations under the License.
 package v3
 
 import (
+	"path/filepath"
+
 	"sigs.k8s.io/kubebuilder/v3/pkg/config"
 	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	cfgv4 "sigs.k8s.io/kubebuilder/v3/pkg/config/v4"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugin"
 	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang"
 )
@@ -27,10 +31,19 @@ const pluginName = "base." + golang.DefaultNameQualifier
 
 var (
 	pluginVersion            = plugin.Version{Number: 3}
-	supportedProjectVersions = []config.Version{cfgv3.Version}
+	supportedProjectVersions = []config.Version{cfgv3.Version, cfgv4.Version}
+	pluginKey                = plugin.KeyFor(Plugin{})
 )
 
-var _ plugin.Full = Plugin{}
+var (
+	_ plugin.Full               = Plugin{}
+	_ plugin.DeleteAPI          = Plugin{}
+	_ plugin.DeleteWebhook      = Plugin{}
+	_ plugin.HasDependencies    = Plugin{}
+	_ plugin.Migrator           = Plugin{}
+	_ plugin.HasConfigSchema    = Plugin{}
+	_ plugin.HasScaffoldedFiles = Plugin{}
+)
 
 // Plugin implements the plugin.Full interface
 type Plugin struct {
@@ -38,6 +51,14 @@ type Plugin struct {
 	createAPISubcommand
 	createWebhookSubcommand
 	editSubcommand
+	deleteAPISubcommand
+	deleteWebhookSubcommand
+	migrateSubcommand
+}
+
+type pluginConfig struct {
+	// TemplatesDir is the directory that contains the files that replace the built-in templates
+	TemplatesDir string `json:"templatesDir,omitempty"`
 }
 
 // Name returns the name of the plugin
@@ -49,6 +70,59 @@ func (Plugin) Version() plugin.Version { return pluginVersion }
 // SupportedProjectVersions returns an array with all project versions supported by the plugin
 func (Plugin) SupportedProjectVersions() []config.Version { return supportedProjectVersions }
 
+// Dependencies returns the relationships of the plugin with other plugins
+func (Plugin) Dependencies() plugin.Dependencies {
+	return plugin.Dependencies{
+		// Go plugin v2 scaffolds a different layout
+		ConflictsWith: []string{golang.DefaultNameQualifier + "/v2"},
+	}
+}
+
+// ConfigSchema returns the JSON Schema of the configuration stored by the plugin
+func (Plugin) ConfigSchema() config.Schema {
+	return config.Schema{
+		"type":                 "object",
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"templatesDir": map[string]interface{}{
+				"description": "Directory with files that replace the built-in templates.",
+				"type":        "string",
+			},
+		},
+	}
+}
+
+// ScaffoldedFiles returns the files scaffolded by the plugin for the resource
+func (Plugin) ScaffoldedFiles(res resource.Resource, multiGroup bool) []string {
+	apiDir := filepath.Join("api", "%[version]")
+	controllersDir := "controllers"
+	if multiGroup {
+		apiDir = filepath.Join("apis", "%[version]")
+		if res.Group != "" {
+			apiDir = filepath.Join("apis", "%[group]", "%[version]")
+			controllersDir = filepath.Join("controllers", "%[group]")
+		}
+	}
+
+	// The API types of resources provided by other modules are not part of the project
+	var paths []string
+	if res.HasAPI() && !res.IsExternal() {
+		paths = append(paths, filepath.Join(apiDir, "%[kind]_types.go"))
+	}
+	if res.HasController() {
+		paths = append(paths, filepath.Join(controllersDir, "%[kind]_controller.go"))
+	}
+	if res.Webhooks != nil && !res.Webhooks.IsEmpty() && !res.IsExternal() {
+		paths = append(paths, filepath.Join(apiDir, "%[kind]_webhook.go"))
+	}
+
+	replacer := res.Replacer()
+	for i, path := range paths {
+		paths[i] = replacer.Replace(path)
+	}
+	return paths
+}
+
 // GetInitSubcommand will return the subcommand which is responsible for initializing and common scaffolding
 func (p Plugin) GetInitSubcommand() plugin.InitSubcommand { return &p.initSubcommand }
 
@@ -62,3 +136,19 @@ func (p Plugin) GetCreateWebhookSubcommand() plugin.CreateWebhookSubcommand {
 
 // GetEditSubcommand will return the subcommand which is responsible for editing the scaffold of the project
 func (p Plugin) GetEditSubcommand() plugin.EditSubcommand { return &p.editSubcommand }
+
+// GetDeleteAPISubcommand will return the subcommand which is responsible for deleting apis
+func (p Plugin) GetDeleteAPISubcommand() plugin.DeleteAPISubcommand { return &p.deleteAPISubcommand }
+
+// GetDeleteWebhookSubcommand will return the subcommand which is responsible for deleting webhooks
+func (p Plugin) GetDeleteWebhookSubcommand() plugin.DeleteWebhookSubcommand {
+	return &p.deleteWebhookSubcommand
+}
+
+// MigrateFrom will return the subcommand which is responsible for migrating projects scaffolded with go/v2
+func (p Plugin) MigrateFrom(previousKey string) plugin.MigrateSubcommand {
+	if previousKey != golang.DefaultNameQualifier+"/v2" {
+		return nil
+	}
+	return &p.migrateSubcommand
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v3
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+)
+
+var _ = Describe("Plugin", func() {
+	Context("ScaffoldedFiles", func() {
+		res := resource.Resource{
+			GVK:        resource.GVK{Group: "crew", Domain: "example.com", Version: "v1", Kind: "Captain"},
+			API:        &resource.API{CRDVersion: "v1", Namespaced: true},
+			Controller: true,
+			Webhooks:   &resource.Webhooks{WebhookVersion: "v1", Defaulting: true},
+		}
+
+		It("should list the files of a single group project", func() {
+			Expect(Plugin{}.ScaffoldedFiles(res, false)).To(Equal([]string{
+				filepath.Join("api", "v1", "captain_types.go"),
+				filepath.Join("controllers", "captain_controller.go"),
+				filepath.Join("api", "v1", "captain_webhook.go"),
+			}))
+		})
+
+		It("should list the files of a multigroup project", func() {
+			Expect(Plugin{}.ScaffoldedFiles(res, true)).To(Equal([]string{
+				filepath.Join("apis", "crew", "v1", "captain_types.go"),
+				filepath.Join("controllers", "crew", "captain_controller.go"),
+				filepath.Join("apis", "crew", "v1", "captain_webhook.go"),
+			}))
+		})
+
+		It("should not list the API types of external resources", func() {
+			external := resource.Resource{
+				GVK:        res.GVK,
+				Module:     "github.com/example/apis",
+				Controller: true,
+			}
+			Expect(Plugin{}.ScaffoldedFiles(external, false)).To(Equal([]string{
+				filepath.Join("controllers", "captain_controller.go"),
+			}))
+		})
+	})
+})
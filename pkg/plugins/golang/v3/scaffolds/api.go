@@ -44,14 +44,19 @@ type apiScaffolder struct {
 
 	// force indicates whether to scaffold controller files even if it exists or not
 	force bool
+
+	// templatesDir is the directory with the files that replace the built-in templates
+	templatesDir string
 }
 
 // NewAPIScaffolder returns a new Scaffolder for API/controller creation operations
-func NewAPIScaffolder(config config.Config, res resource.Resource, force bool) plugins.Scaffolder {
+func NewAPIScaffolder(config config.Config, res resource.Resource, force bool,
+	templatesDir string) plugins.Scaffolder {
 	return &apiScaffolder{
-		config:   config,
-		resource: res,
-		force:    force,
+		config:       config,
+		resource:     res,
+		force:        force,
+		templatesDir: templatesDir,
 	}
 }
 
@@ -62,7 +67,7 @@ func (s *apiScaffolder) InjectFS(fs machinery.Filesystem) {
 
 // Scaffold implements cmdutil.Scaffolder
 func (s *apiScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	fmt.Fprintln(s.fs.Environment.OutOrStdout(), "Writing scaffold for you to edit...")
 
 	// Load the boilerplate
 	boilerplate, err := afero.ReadFile(s.fs.FS, hack.DefaultBoilerplatePath)
@@ -75,6 +80,7 @@ func (s *apiScaffolder) Scaffold() error {
 		machinery.WithConfig(s.config),
 		machinery.WithBoilerplate(string(boilerplate)),
 		machinery.WithResource(&s.resource),
+		machinery.WithTemplateOverrides(s.templatesDir),
 	)
 
 	// Keep track of these values before the update
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds/internal/templates"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds/internal/templates/api"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds/internal/templates/controllers"
+)
+
+// deepCopyFileName is the name of the file generated by `make generate` with the deep-copy functions
+const deepCopyFileName = "zz_generated.deepcopy.go"
+
+var _ plugins.Scaffolder = &deleteAPIScaffolder{}
+
+// deleteAPIScaffolder contains configuration for deleting the scaffolding of the Go type
+// representing the API and controller that implements the behavior for the API.
+type deleteAPIScaffolder struct {
+	config   config.Config
+	resource resource.Resource
+
+	// fs is the filesystem that will be used by the scaffolder
+	fs machinery.Filesystem
+}
+
+// NewDeleteAPIScaffolder returns a new Scaffolder for API/controller deletion operations
+func NewDeleteAPIScaffolder(config config.Config, res resource.Resource) plugins.Scaffolder {
+	return &deleteAPIScaffolder{
+		config:   config,
+		resource: res,
+	}
+}
+
+// InjectFS implements cmdutil.Scaffolder
+func (s *deleteAPIScaffolder) InjectFS(fs machinery.Filesystem) {
+	s.fs = fs
+}
+
+// Scaffold implements cmdutil.Scaffolder
+func (s *deleteAPIScaffolder) Scaffold() error {
+	fmt.Fprintln(s.fs.Environment.OutOrStdout(), "Deleting scaffold...")
+
+	// Initialize the machinery.Scaffold that will delete the files from disk
+	scaffold := machinery.NewScaffold(s.fs,
+		machinery.WithConfig(s.config),
+		machinery.WithResource(&s.resource),
+	)
+
+	// Files and code fragments shared by the resources of a group and version are only deleted with the last one
+	lastInGroupVersion, err := isLastInGroupVersion(s.config, s.resource, func(resource.Resource) bool { return true })
+	if err != nil {
+		return fmt.Errorf("error deleting API: %w", err)
+	}
+
+	if s.resource.HasAPI() {
+		types := &api.Types{}
+		builders := []machinery.Builder{types}
+		if lastInGroupVersion {
+			builders = append(builders, &api.Group{}, machinery.DeleteFile(&api.WebhookSuite{}))
+		}
+		if err := scaffold.Delete(builders...); err != nil {
+			return fmt.Errorf("error deleting APIs: %v", err)
+		}
+
+		// The deep-copy functions of the remaining types of the group and version are regenerated by `make generate`
+		if lastInGroupVersion {
+			deepCopyPath := filepath.Join(filepath.Dir(types.Path), deepCopyFileName)
+			if err := s.fs.FS.Remove(deepCopyPath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("error deleting APIs: %v", err)
+			}
+		}
+	}
+
+	if s.resource.HasController() {
+		if err := scaffold.Delete(&controllers.Controller{}); err != nil {
+			return fmt.Errorf("error deleting controller: %v", err)
+		}
+	}
+
+	if lastInGroupVersion {
+		if err := scaffold.Delete(&controllers.SuiteTest{}); err != nil {
+			return fmt.Errorf("error updating controllers test suite: %v", err)
+		}
+	}
+
+	if err := scaffold.Delete(
+		&templates.MainUpdater{
+			WireResource:   s.resource.HasAPI() && lastInGroupVersion,
+			WireController: s.resource.HasController(),
+		},
+	); err != nil {
+		return fmt.Errorf("error updating main.go: %v", err)
+	}
+
+	if err := s.config.RemoveResource(s.resource.GVK); err != nil {
+		return fmt.Errorf("error removing resource: %w", err)
+	}
+
+	return nil
+}
+
+// isLastInGroupVersion checks that no resource other than res in the same group and version matches filter
+func isLastInGroupVersion(cfg config.Config, res resource.Resource, filter func(resource.Resource) bool) (bool, error) {
+	resources, err := cfg.GetResources()
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range resources {
+		if r.GVK.IsEqualTo(res.GVK) {
+			continue
+		}
+		if r.Group == res.Group && r.Domain == res.Domain && r.Version == res.Version && filter(r) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	cfgv3 "sigs.k8s.io/kubebuilder/v3/pkg/config/v3"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+)
+
+var _ = Describe("deleteAPIScaffolder", func() {
+	const apiDir = "api/v1"
+
+	var (
+		fs  machinery.Filesystem
+		cfg config.Config
+
+		newResource = func(kind, plural string) resource.Resource {
+			return resource.Resource{
+				GVK: resource.GVK{
+					Group:   "crew",
+					Domain:  "testproject.org",
+					Version: "v1",
+					Kind:    kind,
+				},
+				Plural: plural,
+				Path:   "sigs.k8s.io/kubebuilder/testdata/project-v3/api/v1",
+				API: &resource.API{
+					CRDVersion: "v1",
+					Namespaced: true,
+				},
+			}
+		}
+		captain = newResource("Captain", "captains")
+		admiral = newResource("Admiral", "admirals")
+
+		deleteAPI = func(res resource.Resource) error {
+			scaffolder := NewDeleteAPIScaffolder(cfg, res)
+			scaffolder.InjectFS(fs)
+			return scaffolder.Scaffold()
+		}
+	)
+
+	BeforeEach(func() {
+		fs = machinery.Filesystem{FS: afero.NewMemMapFs()}
+
+		cfg = cfgv3.New()
+		Expect(cfg.SetDomain("testproject.org")).To(Succeed())
+		Expect(cfg.SetRepository("sigs.k8s.io/kubebuilder/testdata/project-v3")).To(Succeed())
+		Expect(cfg.AddResource(captain)).To(Succeed())
+		Expect(cfg.AddResource(admiral)).To(Succeed())
+
+		for _, name := range []string{"captain_types.go", "admiral_types.go", "groupversion_info.go", deepCopyFileName} {
+			Expect(afero.WriteFile(fs.FS, filepath.Join(apiDir, name), []byte("package v1\n"), 0600)).To(Succeed())
+		}
+	})
+
+	It("should keep the deep-copy functions while other kinds remain in the group and version", func() {
+		Expect(deleteAPI(captain)).To(Succeed())
+
+		Expect(afero.Exists(fs.FS, filepath.Join(apiDir, "captain_types.go"))).To(BeFalse())
+		Expect(afero.Exists(fs.FS, filepath.Join(apiDir, "admiral_types.go"))).To(BeTrue())
+		Expect(afero.Exists(fs.FS, filepath.Join(apiDir, "groupversion_info.go"))).To(BeTrue())
+		Expect(afero.Exists(fs.FS, filepath.Join(apiDir, deepCopyFileName))).To(BeTrue())
+		Expect(cfg.HasResource(admiral.GVK)).To(BeTrue())
+		Expect(cfg.HasResource(captain.GVK)).To(BeFalse())
+	})
+
+	It("should delete the deep-copy functions with the last kind of the group and version", func() {
+		Expect(deleteAPI(captain)).To(Succeed())
+		Expect(deleteAPI(admiral)).To(Succeed())
+
+		Expect(afero.Exists(fs.FS, filepath.Join(apiDir, "admiral_types.go"))).To(BeFalse())
+		Expect(afero.Exists(fs.FS, filepath.Join(apiDir, "groupversion_info.go"))).To(BeFalse())
+		Expect(afero.Exists(fs.FS, filepath.Join(apiDir, deepCopyFileName))).To(BeFalse())
+	})
+})
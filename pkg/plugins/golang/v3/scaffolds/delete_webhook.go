@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/model/resource"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds/internal/templates"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds/internal/templates/api"
+)
+
+var _ plugins.Scaffolder = &deleteWebhookScaffolder{}
+
+type deleteWebhookScaffolder struct {
+	config   config.Config
+	resource resource.Resource
+
+	// fs is the filesystem that will be used by the scaffolder
+	fs machinery.Filesystem
+}
+
+// NewDeleteWebhookScaffolder returns a new Scaffolder for webhook deletion operations
+func NewDeleteWebhookScaffolder(config config.Config, resource resource.Resource) plugins.Scaffolder {
+	return &deleteWebhookScaffolder{
+		config:   config,
+		resource: resource,
+	}
+}
+
+// InjectFS implements cmdutil.Scaffolder
+func (s *deleteWebhookScaffolder) InjectFS(fs machinery.Filesystem) {
+	s.fs = fs
+}
+
+// Scaffold implements cmdutil.Scaffolder
+func (s *deleteWebhookScaffolder) Scaffold() error {
+	fmt.Fprintln(s.fs.Environment.OutOrStdout(), "Deleting scaffold...")
+
+	// Initialize the machinery.Scaffold that will delete the files from disk
+	scaffold := machinery.NewScaffold(s.fs,
+		machinery.WithConfig(s.config),
+		machinery.WithResource(&s.resource),
+	)
+
+	if err := scaffold.Delete(
+		&api.Webhook{},
+		&templates.MainUpdater{WireWebhook: true},
+	); err != nil {
+		return err
+	}
+
+	// The webhook test suite is only scaffolded for defaulting and validation webhooks
+	if s.resource.HasDefaultingWebhook() || s.resource.HasValidationWebhook() {
+		lastInGroupVersion, err := isLastInGroupVersion(s.config, s.resource, func(r resource.Resource) bool {
+			return r.HasDefaultingWebhook() || r.HasValidationWebhook()
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting webhook: %w", err)
+		}
+
+		var webhookSuite machinery.Builder = &api.WebhookSuite{}
+		if lastInGroupVersion {
+			webhookSuite = machinery.DeleteFile(webhookSuite)
+		}
+		if err := scaffold.Delete(webhookSuite); err != nil {
+			return err
+		}
+	}
+
+	// Resources that only had webhooks are no longer tracked
+	if err := s.config.RemoveResource(s.resource.GVK); err != nil {
+		return fmt.Errorf("error removing resource: %w", err)
+	}
+	if s.resource.HasAPI() || s.resource.HasController() {
+		res := s.resource.Copy()
+		res.Webhooks = nil
+		if err := s.config.AddResource(res); err != nil {
+			return fmt.Errorf("error updating resource: %w", err)
+		}
+	}
+
+	return nil
+}
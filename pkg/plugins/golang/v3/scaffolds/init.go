@@ -46,18 +46,20 @@ type initScaffolder struct {
 	boilerplatePath string
 	license         string
 	owner           string
+	templatesDir    string
 
 	// fs is the filesystem that will be used by the scaffolder
 	fs machinery.Filesystem
 }
 
 // NewInitScaffolder returns a new Scaffolder for project initialization operations
-func NewInitScaffolder(config config.Config, license, owner string) plugins.Scaffolder {
+func NewInitScaffolder(config config.Config, license, owner, templatesDir string) plugins.Scaffolder {
 	return &initScaffolder{
 		config:          config,
 		boilerplatePath: hack.DefaultBoilerplatePath,
 		license:         license,
 		owner:           owner,
+		templatesDir:    templatesDir,
 	}
 }
 
@@ -68,13 +70,14 @@ func (s *initScaffolder) InjectFS(fs machinery.Filesystem) {
 
 // Scaffold implements cmdutil.Scaffolder
 func (s *initScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	fmt.Fprintln(s.fs.Environment.OutOrStdout(), "Writing scaffold for you to edit...")
 
 	// Initialize the machinery.Scaffold that will write the boilerplate file to disk
 	// The boilerplate file needs to be scaffolded as a separate step as it is going to
 	// be used by the rest of the files, even those scaffolded in this command call.
 	scaffold := machinery.NewScaffold(s.fs,
 		machinery.WithConfig(s.config),
+		machinery.WithTemplateOverrides(s.templatesDir),
 	)
 
 	bpFile := &hack.Boilerplate{
@@ -95,6 +98,7 @@ func (s *initScaffolder) Scaffold() error {
 	scaffold = machinery.NewScaffold(s.fs,
 		machinery.WithConfig(s.config),
 		machinery.WithBoilerplate(string(boilerplate)),
+		machinery.WithTemplateOverrides(s.templatesDir),
 	)
 
 	return scaffold.Execute(
@@ -19,6 +19,7 @@ package api
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
 )
@@ -32,8 +33,12 @@ type Types struct {
 	machinery.MultiGroupMixin
 	machinery.BoilerplateMixin
 	machinery.ResourceMixin
+	machinery.EnvironmentMixin
 
 	Force bool
+
+	// ResourceMarker is the value of the kubebuilder:resource marker, empty if it is not needed
+	ResourceMarker string
 }
 
 // SetTemplateDefaults implements file.Template
@@ -50,12 +55,13 @@ func (f *Types) SetTemplateDefaults() error {
 		}
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	fmt.Fprintln(f.Environment.OutOrStdout(), f.Path)
 
 	f.TemplateBody = typesTemplate
+	f.ResourceMarker = resourceMarker(f)
 
 	if f.Force {
-		f.IfExistsAction = machinery.OverwriteFile
+		f.IfExistsAction = machinery.MergeFile
 	} else {
 		f.IfExistsAction = machinery.Error
 	}
@@ -63,6 +69,24 @@ func (f *Types) SetTemplateDefaults() error {
 	return nil
 }
 
+// resourceMarker builds the value of the kubebuilder:resource marker for the resource
+func resourceMarker(f *Types) string {
+	var args []string
+	if !f.Resource.IsRegularPlural() {
+		args = append(args, "path="+f.Resource.Plural)
+	}
+	if f.Resource.API == nil || !f.Resource.API.Namespaced {
+		args = append(args, "scope=Cluster")
+	}
+	if shortNames := f.Resource.API.GetShortNames(); len(shortNames) != 0 {
+		args = append(args, "shortName="+strings.Join(shortNames, ";"))
+	}
+	if categories := f.Resource.API.GetCategories(); len(categories) != 0 {
+		args = append(args, "categories="+strings.Join(categories, ";"))
+	}
+	return strings.Join(args, ",")
+}
+
 const typesTemplate = `{{ .Boilerplate }}
 
 package {{ .Resource.Version }}
@@ -81,22 +105,39 @@ type {{ .Resource.Kind }}Spec struct {
 
 	// Foo is an example field of {{ .Resource.Kind }}. Edit {{ lower .Resource.Kind }}_types.go to remove/update
 	Foo string ` + "`" + `json:"foo,omitempty"` + "`" + `
+{{- if .Resource.API.HasScaleSubresource }}
+
+	// Replicas is the desired number of replicas of {{ .Resource.Kind }}, exposed through the scale subresource
+	Replicas *int32 ` + "`" + `json:"replicas,omitempty"` + "`" + `
+{{- end }}
 }
 
 // {{ .Resource.Kind }}Status defines the observed state of {{ .Resource.Kind }}
 type {{ .Resource.Kind }}Status struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
+{{- if .Resource.API.HasScaleSubresource }}
+
+	// Replicas is the observed number of replicas of {{ .Resource.Kind }}, exposed through the scale subresource
+	Replicas int32 ` + "`" + `json:"replicas,omitempty"` + "`" + `
+
+	// Selector is the label selector of the replicas of {{ .Resource.Kind }}, exposed through the scale subresource
+	Selector string ` + "`" + `json:"selector,omitempty"` + "`" + `
+{{- end }}
 }
 
 //+kubebuilder:object:root=true
+{{- if or (not .Resource.API.Subresources) .Resource.API.HasStatusSubresource }}
 //+kubebuilder:subresource:status
-{{- if and (not .Resource.API.Namespaced) (not .Resource.IsRegularPlural) }}
-//+kubebuilder:resource:path={{ .Resource.Plural }},scope=Cluster
-{{- else if not .Resource.API.Namespaced }}
-//+kubebuilder:resource:scope=Cluster
-{{- else if not .Resource.IsRegularPlural }}
-//+kubebuilder:resource:path={{ .Resource.Plural }}
+{{- end }}
+{{- if .Resource.API.HasScaleSubresource }}
+//+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+{{- end }}
+{{- if .ResourceMarker }}
+//+kubebuilder:resource:{{ .ResourceMarker }}
+{{- end }}
+{{- if .Resource.API.IsStorageVersion }}
+//+kubebuilder:storageversion
 {{- end }}
 
 // {{ .Resource.Kind }} is the Schema for the {{ .Resource.Plural }} API
@@ -32,6 +32,7 @@ type Webhook struct { // nolint:maligned
 	machinery.MultiGroupMixin
 	machinery.BoilerplateMixin
 	machinery.ResourceMixin
+	machinery.EnvironmentMixin
 
 	// Is the Group domain for the Resource replacing '.' with '-'
 	QualifiedGroupWithDash string
@@ -56,7 +57,7 @@ func (f *Webhook) SetTemplateDefaults() error {
 		}
 	}
 	f.Path = f.Resource.Replacer().Replace(f.Path)
-	fmt.Println(f.Path)
+	fmt.Fprintln(f.Environment.OutOrStdout(), f.Path)
 
 	webhookTemplate := webhookTemplate
 	if f.Resource.HasDefaultingWebhook() {
@@ -68,7 +69,7 @@ func (f *Webhook) SetTemplateDefaults() error {
 	f.TemplateBody = webhookTemplate
 
 	if f.Force {
-		f.IfExistsAction = machinery.OverwriteFile
+		f.IfExistsAction = machinery.MergeFile
 	} else {
 		f.IfExistsAction = machinery.Error
 	}
@@ -9,6 +9,7 @@ import (
 
 var _ machinery.Template = &WebhookSuite{}
 var _ machinery.Inserter = &WebhookSuite{}
+var _ machinery.Remover = &WebhookSuite{}
 
 // WebhookSuite scaffolds the file that sets up the webhook tests
 type WebhookSuite struct { //nolint:maligned
@@ -117,6 +118,16 @@ func (f *WebhookSuite) GetCodeFragments() machinery.CodeFragmentsMap {
 	return fragments
 }
 
+// GetCodeFragmentsToRemove implements file.Remover
+func (f *WebhookSuite) GetCodeFragmentsToRemove() machinery.CodeFragmentsMap {
+	// The admission imports and scheme are shared by every webhook, so only the webhook setup is removed
+	return machinery.CodeFragmentsMap{
+		machinery.NewMarkerFor(f.Path, addWebhookManagerMarker): {
+			fmt.Sprintf(addWebhookManagerCodeFragment, f.Resource.Kind),
+		},
+	}
+}
+
 const webhookTestSuiteTemplate = `{{ .Boilerplate }}
 
 package {{ .Resource.Version }}
@@ -25,6 +25,8 @@ import (
 
 var _ machinery.Template = &SuiteTest{}
 var _ machinery.Inserter = &SuiteTest{}
+var _ machinery.GoInserter = &SuiteTest{}
+var _ machinery.Remover = &SuiteTest{}
 
 // SuiteTest scaffolds the file that sets up the controller tests
 // nolint:maligned
@@ -64,7 +66,7 @@ func (f *SuiteTest) SetTemplateDefaults() error {
 	}
 
 	if f.Force {
-		f.IfExistsAction = machinery.OverwriteFile
+		f.IfExistsAction = machinery.MergeFile
 	}
 
 	return nil
@@ -119,6 +121,29 @@ func (f *SuiteTest) GetCodeFragments() machinery.CodeFragmentsMap {
 	return fragments
 }
 
+// GetGoAnchors implements file.GoInserter
+func (f *SuiteTest) GetGoAnchors() map[machinery.Marker]machinery.GoAnchor {
+	return map[machinery.Marker]machinery.GoAnchor{
+		machinery.NewMarkerFor(f.Path, importMarker): {Imports: true},
+		// Schemes need to be registered before the client is created
+		machinery.NewMarkerFor(f.Path, addSchemeMarker): {Func: "BeforeSuite", Before: []string{"client.New"}},
+	}
+}
+
+// GetCodeFragmentsToRemove implements file.Remover
+func (f *SuiteTest) GetCodeFragmentsToRemove() machinery.CodeFragmentsMap {
+	fragments := make(machinery.CodeFragmentsMap, 1)
+
+	// Import code fragments are not removed, as imports that are no longer used are dropped when formatting
+	if f.Resource.Path != "" {
+		fragments[machinery.NewMarkerFor(f.Path, addSchemeMarker)] = []string{
+			fmt.Sprintf(addschemeCodeFragment, f.Resource.ImportAlias()),
+		}
+	}
+
+	return fragments
+}
+
 const controllerSuiteTestTemplate = `{{ .Boilerplate }}
 
 {{if and .MultiGroup .Resource.Group }}
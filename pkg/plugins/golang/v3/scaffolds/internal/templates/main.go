@@ -51,7 +51,11 @@ func (f *Main) SetTemplateDefaults() error {
 	return nil
 }
 
-var _ machinery.Inserter = &MainUpdater{}
+var (
+	_ machinery.Inserter   = &MainUpdater{}
+	_ machinery.GoInserter = &MainUpdater{}
+	_ machinery.Remover    = &MainUpdater{}
+)
 
 // MainUpdater updates main.go to run Controllers
 type MainUpdater struct { //nolint:maligned
@@ -151,6 +155,68 @@ func (f *MainUpdater) GetCodeFragments() machinery.CodeFragmentsMap {
 	}
 
 	// Generate setup code fragments
+	setup := f.getSetupCodeFragments()
+
+	// Only store code fragments in the map if the slices are non-empty
+	if len(imports) != 0 {
+		fragments[machinery.NewMarkerFor(defaultMainPath, importMarker)] = imports
+	}
+	if len(addScheme) != 0 {
+		fragments[machinery.NewMarkerFor(defaultMainPath, addSchemeMarker)] = addScheme
+	}
+	if len(setup) != 0 {
+		fragments[machinery.NewMarkerFor(defaultMainPath, setupMarker)] = setup
+	}
+
+	return fragments
+}
+
+// GetGoAnchors implements file.GoInserter
+func (f *MainUpdater) GetGoAnchors() map[machinery.Marker]machinery.GoAnchor {
+	return map[machinery.Marker]machinery.GoAnchor{
+		machinery.NewMarkerFor(defaultMainPath, importMarker):    {Imports: true},
+		machinery.NewMarkerFor(defaultMainPath, addSchemeMarker): {Func: "init"},
+		// Controllers and webhooks need to be set up before the health checks are added and the manager is started
+		machinery.NewMarkerFor(defaultMainPath, setupMarker): {
+			Func:   "main",
+			Before: []string{"mgr.AddHealthzCheck", "mgr.AddReadyzCheck", "mgr.Start"},
+		},
+	}
+}
+
+// GetCodeFragmentsToRemove implements file.Remover
+func (f *MainUpdater) GetCodeFragmentsToRemove() machinery.CodeFragmentsMap {
+	fragments := make(machinery.CodeFragmentsMap, 2)
+
+	// If resource is not being provided there is nothing to remove
+	if f.Resource == nil {
+		return fragments
+	}
+
+	// Import code fragments are not removed, as imports that are no longer used are dropped when formatting
+
+	// Generate add scheme code fragments
+	addScheme := make([]string, 0)
+	if f.WireResource {
+		addScheme = append(addScheme, fmt.Sprintf(addschemeCodeFragment, f.Resource.ImportAlias()))
+	}
+
+	// Generate setup code fragments
+	setup := f.getSetupCodeFragments()
+
+	// Only store code fragments in the map if the slices are non-empty
+	if len(addScheme) != 0 {
+		fragments[machinery.NewMarkerFor(defaultMainPath, addSchemeMarker)] = addScheme
+	}
+	if len(setup) != 0 {
+		fragments[machinery.NewMarkerFor(defaultMainPath, setupMarker)] = setup
+	}
+
+	return fragments
+}
+
+// getSetupCodeFragments returns the code fragments that set up the controller and webhook with the manager
+func (f *MainUpdater) getSetupCodeFragments() []string {
 	setup := make([]string, 0)
 	if f.WireController {
 		if !f.MultiGroup || f.Resource.Group == "" {
@@ -166,18 +232,7 @@ func (f *MainUpdater) GetCodeFragments() machinery.CodeFragmentsMap {
 			f.Resource.ImportAlias(), f.Resource.Kind, f.Resource.Kind))
 	}
 
-	// Only store code fragments in the map if the slices are non-empty
-	if len(imports) != 0 {
-		fragments[machinery.NewMarkerFor(defaultMainPath, importMarker)] = imports
-	}
-	if len(addScheme) != 0 {
-		fragments[machinery.NewMarkerFor(defaultMainPath, addSchemeMarker)] = addScheme
-	}
-	if len(setup) != 0 {
-		fragments[machinery.NewMarkerFor(defaultMainPath, setupMarker)] = setup
-	}
-
-	return fragments
+	return setup
 }
 
 var mainTemplate = `{{ .Boilerplate }}
@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaffolds
+
+import (
+	"fmt"
+
+	"github.com/spf13/afero"
+
+	"sigs.k8s.io/kubebuilder/v3/pkg/config"
+	"sigs.k8s.io/kubebuilder/v3/pkg/machinery"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds/internal/templates"
+	"sigs.k8s.io/kubebuilder/v3/pkg/plugins/golang/v3/scaffolds/internal/templates/hack"
+)
+
+var _ plugins.Scaffolder = &migrateScaffolder{}
+
+type migrateScaffolder struct {
+	config       config.Config
+	templatesDir string
+
+	// fs is the filesystem that will be used by the scaffolder
+	fs machinery.Filesystem
+}
+
+// NewMigrateScaffolder returns a new Scaffolder for project migration operations, which regenerates the project
+// files that are not specific to a resource and wires the resources tracked in the configuration in main.go
+func NewMigrateScaffolder(config config.Config, templatesDir string) plugins.Scaffolder {
+	return &migrateScaffolder{
+		config:       config,
+		templatesDir: templatesDir,
+	}
+}
+
+// InjectFS implements cmdutil.Scaffolder
+func (s *migrateScaffolder) InjectFS(fs machinery.Filesystem) {
+	s.fs = fs
+}
+
+// Scaffold implements cmdutil.Scaffolder
+func (s *migrateScaffolder) Scaffold() error {
+	fmt.Fprintln(s.fs.Environment.OutOrStdout(), "Regenerating scaffold...")
+
+	// Load the boilerplate
+	boilerplate, err := afero.ReadFile(s.fs.FS, hack.DefaultBoilerplatePath)
+	if err != nil {
+		return fmt.Errorf("error migrating project: unable to load boilerplate: %w", err)
+	}
+
+	// Initialize the machinery.Scaffold that will overwrite the existing files
+	scaffold := machinery.NewScaffold(s.fs,
+		machinery.WithConfig(s.config),
+		machinery.WithBoilerplate(string(boilerplate)),
+		machinery.WithTemplateOverrides(s.templatesDir),
+		machinery.WithIfExistsAction(machinery.OverwriteFile),
+	)
+
+	if err := scaffold.Execute(
+		&templates.Main{},
+		&templates.GoMod{
+			ControllerRuntimeVersion: ControllerRuntimeVersion,
+		},
+		&templates.GitIgnore{},
+		&templates.Makefile{
+			Image:                    imageName,
+			BoilerplatePath:          hack.DefaultBoilerplatePath,
+			ControllerToolsVersion:   ControllerToolsVersion,
+			KustomizeVersion:         KustomizeVersion,
+			ControllerRuntimeVersion: ControllerRuntimeVersion,
+		},
+		&templates.Dockerfile{},
+		&templates.DockerIgnore{},
+	); err != nil {
+		return fmt.Errorf("error migrating project: %v", err)
+	}
+
+	resources, err := s.config.GetResources()
+	if err != nil {
+		return fmt.Errorf("error migrating project: unable to load resources: %w", err)
+	}
+	for i := range resources {
+		res := resources[i]
+
+		// Initialize the machinery.Scaffold that will insert the code of the resource in main.go
+		scaffold := machinery.NewScaffold(s.fs,
+			machinery.WithConfig(s.config),
+			machinery.WithBoilerplate(string(boilerplate)),
+			machinery.WithResource(&res),
+		)
+
+		if err := scaffold.Execute(&templates.MainUpdater{
+			WireResource:   res.HasAPI(),
+			WireController: res.HasController(),
+			WireWebhook:    res.Webhooks != nil && !res.Webhooks.IsEmpty(),
+		}); err != nil {
+			return fmt.Errorf("error updating main.go: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -41,14 +41,19 @@ type webhookScaffolder struct {
 
 	// force indicates whether to scaffold controller files even if it exists or not
 	force bool
+
+	// templatesDir is the directory with the files that replace the built-in templates
+	templatesDir string
 }
 
 // NewWebhookScaffolder returns a new Scaffolder for v2 webhook creation operations
-func NewWebhookScaffolder(config config.Config, resource resource.Resource, force bool) plugins.Scaffolder {
+func NewWebhookScaffolder(config config.Config, resource resource.Resource, force bool,
+	templatesDir string) plugins.Scaffolder {
 	return &webhookScaffolder{
-		config:   config,
-		resource: resource,
-		force:    force,
+		config:       config,
+		resource:     resource,
+		force:        force,
+		templatesDir: templatesDir,
 	}
 }
 
@@ -59,7 +64,7 @@ func (s *webhookScaffolder) InjectFS(fs machinery.Filesystem) {
 
 // Scaffold implements cmdutil.Scaffolder
 func (s *webhookScaffolder) Scaffold() error {
-	fmt.Println("Writing scaffold for you to edit...")
+	fmt.Fprintln(s.fs.Environment.OutOrStdout(), "Writing scaffold for you to edit...")
 
 	// Load the boilerplate
 	boilerplate, err := afero.ReadFile(s.fs.FS, hack.DefaultBoilerplatePath)
@@ -72,6 +77,7 @@ func (s *webhookScaffolder) Scaffold() error {
 		machinery.WithConfig(s.config),
 		machinery.WithBoilerplate(string(boilerplate)),
 		machinery.WithResource(&s.resource),
+		machinery.WithTemplateOverrides(s.templatesDir),
 	)
 
 	// Keep track of these values before the update
@@ -91,7 +97,7 @@ func (s *webhookScaffolder) Scaffold() error {
 	}
 
 	if doConversion {
-		fmt.Println(`Webhook server has been set up for you.
+		fmt.Fprintln(s.fs.Environment.OutOrStdout(), `Webhook server has been set up for you.
 You need to implement the conversion.Hub and conversion.Convertible interfaces for your CRD types.`)
 	}
 
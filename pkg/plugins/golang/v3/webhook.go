@@ -17,6 +17,7 @@ limitations under the License.
 package v3
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/pflag"
@@ -37,6 +38,7 @@ var _ plugin.CreateWebhookSubcommand = &createWebhookSubcommand{}
 
 type createWebhookSubcommand struct {
 	config config.Config
+	env    *pluginutil.Environment
 	// For help text.
 	commandName string
 
@@ -46,6 +48,9 @@ type createWebhookSubcommand struct {
 
 	// force indicates that the resource should be created even if it already exists
 	force bool
+
+	// templatesDir is the directory with the files that replace the built-in templates
+	templatesDir string
 }
 
 func (p *createWebhookSubcommand) UpdateMetadata(cliMeta plugin.CLIMetadata, subcmdMeta *plugin.SubcommandMetadata) {
@@ -79,16 +84,34 @@ func (p *createWebhookSubcommand) BindFlags(fs *pflag.FlagSet) {
 		"if set, scaffold the conversion webhook")
 
 	fs.BoolVar(&p.force, "force", false,
-		"attempt to create resource even if it already exists")
+		"attempt to create resource even if it already exists, merging the changes made to the existing files")
+	fs.StringVar(&p.templatesDir, templatesDirFlag, "", templatesDirUsage+", defaults to the one in the PROJECT file")
 
 	// (not required raise an error in this case)
 	// nolint:errcheck,gosec
 	fs.MarkDeprecated("webhook-version", deprecateMsg)
 }
 
+// SharedFlags implements plugin.SharesFlags so that `--force` also applies to the kustomize plugin.
+func (p *createWebhookSubcommand) SharedFlags() []string { return []string{"force"} }
+
+func (p *createWebhookSubcommand) InjectEnvironment(env *pluginutil.Environment) error {
+	p.env = env
+	return nil
+}
+
 func (p *createWebhookSubcommand) InjectConfig(c config.Config) error {
 	p.config = c
 
+	// The templates directory provided by flag takes precedence over the one stored in the project configuration
+	if p.templatesDir == "" {
+		templatesDir, err := getTemplatesDir(p.config)
+		if err != nil {
+			return err
+		}
+		p.templatesDir = templatesDir
+	}
+
 	return nil
 }
 
@@ -122,28 +145,37 @@ func (p *createWebhookSubcommand) InjectResource(res *resource.Resource) error {
 }
 
 func (p *createWebhookSubcommand) Scaffold(fs machinery.Filesystem) error {
-	scaffolder := scaffolds.NewWebhookScaffolder(p.config, *p.resource, p.force)
+	scaffolder := scaffolds.NewWebhookScaffolder(p.config, *p.resource, p.force, p.templatesDir)
 	scaffolder.InjectFS(fs)
-	return scaffolder.Scaffold()
-}
+	if err := scaffolder.Scaffold(); err != nil {
+		return err
+	}
 
-func (p *createWebhookSubcommand) PostScaffold() error {
 	if p.resource.Webhooks.WebhookVersion == "v1beta1" {
-		if err := applyScaffoldCustomizationsForVbeta1(); err != nil {
+		if err := applyScaffoldCustomizationsForVbeta1(fs); err != nil {
 			return err
 		}
 	}
 
-	err := pluginutil.RunCmd("Update dependencies", "go", "mod", "tidy")
+	return nil
+}
+
+func (p *createWebhookSubcommand) PostScaffold() error {
+	return p.PostScaffoldWithContext(context.Background())
+}
+
+func (p *createWebhookSubcommand) PostScaffoldWithContext(ctx context.Context) error {
+	err := pluginutil.RunCmdWithContext(ctx, "Update dependencies", "go", "mod", "tidy")
 	if err != nil {
 		return err
 	}
 
-	err = pluginutil.RunCmd("Running make", "make", "generate")
+	err = pluginutil.RunCmdWithContext(ctx, "Running make", "make", "generate")
 	if err != nil {
 		return err
 	}
-	fmt.Print("Next: implement your new Webhook and generate the manifests with:\n$ make manifests\n")
+	fmt.Fprint(p.env.OutOrStdout(),
+		"Next: implement your new Webhook and generate the manifests with:\n$ make manifests\n")
 
 	return nil
 }